@@ -11,6 +11,7 @@ import (
 	"fyne.io/fyne/v2/widget"
 
 	"github.com/adrielfilipedesign/gamekeep/internal/core"
+	"github.com/adrielfilipedesign/gamekeep/internal/models"
 	"github.com/adrielfilipedesign/gamekeep/internal/storage"
 	"github.com/adrielfilipedesign/gamekeep/internal/vault"
 	"github.com/adrielfilipedesign/gamekeep/ui"
@@ -24,7 +25,7 @@ const (
 func main() {
 	// Initialize Fyne app
 	a := app.NewWithID(appID)
-	
+
 	// Setup directories
 	homeDir, err := os.UserHomeDir()
 	if err != nil {
@@ -52,6 +53,12 @@ func main() {
 
 	// Initialize service
 	service := core.NewService(store, vaultMgr)
+	service.SetOrigin(models.OriginGUI)
+
+	// Fix up game IDs written by older versions
+	if _, err := service.MigrateGameIDs(); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to migrate game IDs: %v\n", err)
+	}
 
 	// Create main window
 	mainWindow := a.NewWindow("GameKeep - Save Manager")
@@ -62,6 +69,15 @@ func main() {
 	mainUI := ui.NewMainUI(mainWindow, service)
 	mainWindow.SetContent(mainUI.Build())
 
+	// Watch for games being played, run checkpoint schedules and pick up
+	// changes made from the CLI while the window is open
+	mainUI.StartWatcher()
+	defer mainUI.StopWatcher()
+	mainUI.StartScheduler()
+	defer mainUI.StopScheduler()
+	mainUI.StartFollowingChanges()
+	defer mainUI.StopFollowingChanges()
+
 	// Show and run
 	mainWindow.ShowAndRun()
 }
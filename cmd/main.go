@@ -1,13 +1,23 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
 	"text/tabwriter"
+	"time"
 
 	"github.com/adrielfilipedesign/gamekeep/internal/core"
+	"github.com/adrielfilipedesign/gamekeep/internal/launcher"
+	"github.com/adrielfilipedesign/gamekeep/internal/models"
 	"github.com/adrielfilipedesign/gamekeep/internal/storage"
 	"github.com/adrielfilipedesign/gamekeep/internal/vault"
 )
@@ -36,20 +46,52 @@ func (c *CLI) Run(args []string) error {
 	}
 
 	command := args[0]
-	
+
 	switch command {
 	case "add-game":
 		return c.addGame(args[1:])
 	case "list-games":
-		return c.listGames()
+		return c.listGames(args[1:])
+	case "show-game":
+		return c.showGame(args[1:])
+	case "edit-game":
+		return c.editGame(args[1:])
+	case "remove-game":
+		return c.removeGame(args[1:])
+	case "export":
+		return c.exportGame(args[1:])
+	case "import":
+		return c.importBundle(args[1:])
 	case "checkpoint":
 		return c.createCheckpoint(args[1:])
 	case "list":
 		return c.listCheckpoints(args[1:])
+	case "tree":
+		return c.showTree(args[1:])
+	case "inspect":
+		return c.inspectSave(args[1:])
+	case "run":
+		return c.runGame(args[1:])
+	case "watch":
+		return c.watch(args[1:])
+	case "schedule":
+		return c.schedule(args[1:])
+	case "hook":
+		return c.hook(args[1:])
 	case "restore":
 		return c.restoreCheckpoint(args[1:])
+	case "edit-checkpoint":
+		return c.editCheckpoint(args[1:])
+	case "tag":
+		return c.tagCheckpoint(args[1:])
 	case "delete":
 		return c.deleteCheckpoint(args[1:])
+	case "trash":
+		return c.trash(args[1:])
+	case "settings":
+		return c.settings(args[1:])
+	case "log":
+		return c.showLog(args[1:])
 	case "version":
 		fmt.Printf("GameKeep v%s\n", version)
 		return nil
@@ -66,7 +108,13 @@ func (c *CLI) addGame(args []string) error {
 	fs := flag.NewFlagSet("add-game", flag.ExitOnError)
 	name := fs.String("name", "", "Game name (required)")
 	path := fs.String("path", "", "Save directory path (required)")
-	
+	var opts models.GameOptions
+	fs.StringVar(&opts.Platform, "platform", "", "Store or launcher, e.g. Steam or GOG")
+	fs.StringVar(&opts.Executable, "exe", "", "Path to the game's executable")
+	fs.StringVar(&opts.LaunchCommand, "launch", "", "Command that starts the game")
+	fs.StringVar(&opts.Notes, "notes", "", "Free-text notes")
+	fs.StringVar(&opts.CoverImage, "cover", "", "Cover image to copy into GameKeep (PNG, JPEG, GIF or WebP)")
+
 	if err := fs.Parse(args); err != nil {
 		return err
 	}
@@ -75,7 +123,7 @@ func (c *CLI) addGame(args []string) error {
 		return fmt.Errorf("both --name and --path are required")
 	}
 
-	game, err := c.service.AddGame(*name, *path)
+	game, err := c.service.AddGameWithOptions(*name, *path, opts)
 	if err != nil {
 		return fmt.Errorf("failed to add game: %w", err)
 	}
@@ -84,12 +132,26 @@ func (c *CLI) addGame(args []string) error {
 	fmt.Printf("  ID:   %s\n", game.ID)
 	fmt.Printf("  Name: %s\n", game.Name)
 	fmt.Printf("  Path: %s\n", game.SavePath)
-	
+	if game.Platform != "" {
+		fmt.Printf("  Platform: %s\n", game.Platform)
+	}
+
 	return nil
 }
 
 // listGames handles the list-games command
-func (c *CLI) listGames() error {
+func (c *CLI) listGames(args []string) error {
+	fs := flag.NewFlagSet("list-games", flag.ExitOnError)
+	archived := fs.Bool("archived", false, "List removed games whose checkpoints were kept")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *archived {
+		return c.listArchivedGames()
+	}
+
 	games, err := c.service.ListGames()
 	if err != nil {
 		return fmt.Errorf("failed to list games: %w", err)
@@ -102,16 +164,329 @@ func (c *CLI) listGames() error {
 	}
 
 	fmt.Printf("Registered Games (%d):\n\n", len(games))
-	
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "ID\tNAME\tPLATFORM\tSAVE PATH")
+	fmt.Fprintln(w, "──\t────\t────────\t─────────")
+
+	for _, game := range games {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", game.ID, game.Name, orDash(game.Platform), game.SavePath)
+	}
+
+	w.Flush()
+	return nil
+}
+
+// showGame handles the show-game command
+func (c *CLI) showGame(args []string) error {
+	fs := flag.NewFlagSet("show-game", flag.ExitOnError)
+	gameID := fs.String("game", "", "Game ID or name (required)")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *gameID == "" {
+		return fmt.Errorf("--game is required")
+	}
+
+	game, err := c.service.GetGame(*gameID)
+	if err != nil {
+		return fmt.Errorf("failed to show game: %w", err)
+	}
+
+	stats, err := c.service.GameStats(game.ID)
+	if err != nil {
+		return fmt.Errorf("failed to show game: %w", err)
+	}
+
+	fmt.Printf("%s\n", game.Name)
+	fmt.Printf("  ID:          %s\n", game.ID)
+	fmt.Printf("  Save path:   %s\n", game.SavePath)
+	fmt.Printf("  Platform:    %s\n", orDash(game.Platform))
+	fmt.Printf("  Executable:  %s\n", orDash(game.Executable))
+	fmt.Printf("  Processes:   %s\n", orDash(strings.Join(game.ProcessNames, ", ")))
+	fmt.Printf("  Launch:      %s\n", orDash(game.LaunchCommand))
+	fmt.Printf("  Compression: %s\n", game.ArchiveCompression())
+	fmt.Printf("  Cover:       %s\n", orDash(c.service.CoverPath(game)))
+	fmt.Printf("  Added:       %s\n", formatTime(game.CreatedAt))
+	if game.LastPlayedAt != nil {
+		fmt.Printf("  Last played: %s\n", formatTime(*game.LastPlayedAt))
+	} else {
+		fmt.Printf("  Last played: -\n")
+	}
+	if game.PlaytimeSeconds > 0 {
+		fmt.Printf("  Playtime:    %s\n", models.FormatPlaytime(game.PlaytimeSeconds))
+	}
+
+	fmt.Printf("\nStats:\n")
+	fmt.Printf("  Checkpoints:     %d (%s)\n", stats.CheckpointCount, formatBytes(stats.TotalSize))
+	fmt.Printf("  Last checkpoint: %s\n", formatTime(stats.LastCheckpointAt))
+
+	if game.Notes != "" {
+		fmt.Printf("\nNotes:\n%s\n", game.Notes)
+	}
+
+	return nil
+}
+
+// listArchivedGames handles the list-games --archived command
+func (c *CLI) listArchivedGames() error {
+	games, err := c.service.ListArchivedGames()
+	if err != nil {
+		return fmt.Errorf("failed to list games: %w", err)
+	}
+
+	if len(games) == 0 {
+		fmt.Println("No archived games.")
+		return nil
+	}
+
+	fmt.Printf("Archived Games (%d):\n\n", len(games))
+
 	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
 	fmt.Fprintln(w, "ID\tNAME\tSAVE PATH")
 	fmt.Fprintln(w, "──\t────\t─────────")
-	
+
 	for _, game := range games {
 		fmt.Fprintf(w, "%s\t%s\t%s\n", game.ID, game.Name, game.SavePath)
 	}
-	
+
 	w.Flush()
+	fmt.Println("\nUse 'gamekeep edit-game --game <id> --archived=false' to bring one back.")
+	return nil
+}
+
+// editGame handles the edit-game command
+func (c *CLI) editGame(args []string) error {
+	fs := flag.NewFlagSet("edit-game", flag.ExitOnError)
+	game := fs.String("game", "", "Game ID or name (required)")
+	name := fs.String("name", "", "New game name")
+	path := fs.String("path", "", "New save directory path")
+	archived := fs.Bool("archived", false, "Archive (true) or unarchive (false) the game")
+	var defineFields, dropFields listFlag
+	fs.Var(&defineFields, "field-def", "Define a custom checkpoint field as key:type, type is string, number, bool or date (repeatable)")
+	fs.Var(&dropFields, "drop-field", "Remove a custom field definition (repeatable)")
+	inspector := fs.String("inspector", "", "Save inspector to use: a name from 'gamekeep inspect --list', auto or none")
+	platform := fs.String("platform", "", "Store or launcher, e.g. Steam or GOG")
+	exe := fs.String("exe", "", "Path to the game's executable")
+	launch := fs.String("launch", "", "Command that starts the game")
+	notes := fs.String("notes", "", "Free-text notes")
+	cover := fs.String("cover", "", "Cover image to copy into GameKeep; empty removes the cover")
+	processes := fs.String("processes", "", "Comma-separated process names the watcher knows the game by, besides its executable")
+	compression := fs.String("compression", "", "Compression for new checkpoints: store, deflate[:1-9], zstd[:1-22] or default")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *game == "" {
+		return fmt.Errorf("--game is required")
+	}
+
+	// Only change the fields that were given
+	var update models.GameUpdate
+	fs.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "name":
+			update.Name = name
+		case "path":
+			update.SavePath = path
+		case "archived":
+			update.Archived = archived
+		case "inspector":
+			if *inspector == "auto" {
+				*inspector = ""
+			}
+			update.Inspector = inspector
+		case "platform":
+			update.Platform = platform
+		case "exe":
+			update.Executable = exe
+		case "launch":
+			update.LaunchCommand = launch
+		case "notes":
+			update.Notes = notes
+		case "cover":
+			update.CoverImage = cover
+		case "processes":
+			names := strings.Split(*processes, ",")
+			update.ProcessNames = &names
+		case "compression":
+			update.Compression = &models.Compression{}
+		}
+	})
+
+	if update.Compression != nil && *compression != "default" {
+		parsed, err := models.ParseCompression(*compression)
+		if err != nil {
+			return err
+		}
+		update.Compression = &parsed
+	}
+
+	if len(defineFields) > 0 || len(dropFields) > 0 {
+		current, err := c.service.GetGame(*game)
+		if err != nil {
+			return fmt.Errorf("failed to update game: %w", err)
+		}
+		defs, err := changeFieldDefs(current.Fields, defineFields, dropFields)
+		if err != nil {
+			return err
+		}
+		update.Fields = &defs
+	}
+
+	if update == (models.GameUpdate{}) {
+		return fmt.Errorf("nothing to change: see 'gamekeep edit-game -h'")
+	}
+
+	updated, err := c.service.UpdateGame(*game, update)
+	if err != nil {
+		return fmt.Errorf("failed to update game: %w", err)
+	}
+
+	fmt.Printf("✓ Game updated successfully\n")
+	fmt.Printf("  ID:   %s\n", updated.ID)
+	fmt.Printf("  Name: %s\n", updated.Name)
+	fmt.Printf("  Path: %s\n", updated.SavePath)
+	if len(updated.Fields) > 0 {
+		defs := make([]string, len(updated.Fields))
+		for i, def := range updated.Fields {
+			defs[i] = def.String()
+		}
+		fmt.Printf("  Fields: %s\n", strings.Join(defs, ", "))
+	}
+	if updated.Inspector != "" {
+		fmt.Printf("  Inspector: %s\n", updated.Inspector)
+	}
+	if updated.Platform != "" {
+		fmt.Printf("  Platform: %s\n", updated.Platform)
+	}
+	if updated.Compression != nil {
+		fmt.Printf("  Compression: %s\n", updated.Compression)
+	}
+
+	return nil
+}
+
+// removeGame handles the remove-game command
+func (c *CLI) removeGame(args []string) error {
+	fs := flag.NewFlagSet("remove-game", flag.ExitOnError)
+	game := fs.String("game", "", "Game ID or name (required)")
+	mode := fs.String("checkpoints", "", "What to do with its checkpoints: keep, delete or export (required)")
+	out := fs.String("out", "", "Bundle file to export to (with --checkpoints export)")
+	permanent := fs.Bool("permanent", false, "Delete checkpoints permanently instead of moving them to the trash")
+	force := fs.Bool("force", false, "Delete protected checkpoints too")
+	dryRun := fs.Bool("dry-run", false, "Show what would be removed without changing anything")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *game == "" || *mode == "" {
+		return fmt.Errorf("both --game and --checkpoints are required")
+	}
+
+	opts := models.RemoveGameOptions{
+		Mode:       models.RemoveMode(*mode),
+		ExportPath: *out,
+		Permanent:  *permanent,
+		Force:      *force,
+	}
+
+	if *dryRun {
+		summary, err := c.service.PlanRemoveGame(*game, opts)
+		if err != nil {
+			return fmt.Errorf("failed to plan removal: %w", err)
+		}
+		fmt.Printf("Would remove game '%s'\n", summary.Game.Name)
+		printRemoveSummary(summary, opts)
+		return nil
+	}
+
+	summary, err := c.service.RemoveGame(*game, opts)
+	if err != nil {
+		return fmt.Errorf("failed to remove game: %w", err)
+	}
+
+	fmt.Printf("✓ Game '%s' removed\n", summary.Game.Name)
+	printRemoveSummary(summary, opts)
+
+	return nil
+}
+
+// printRemoveSummary prints what removing a game affects
+func printRemoveSummary(summary *models.RemoveGameSummary, opts models.RemoveGameOptions) {
+	fmt.Printf("  Checkpoints: %d (%s)\n", summary.CheckpointCount, formatBytes(summary.VaultBytes))
+	if summary.ProtectedCount > 0 {
+		fmt.Printf("  Protected:   %d (need --force unless kept)\n", summary.ProtectedCount)
+	}
+
+	switch {
+	case opts.Mode == models.RemoveKeep:
+		fmt.Printf("  Checkpoints kept; see 'gamekeep list-games --archived'\n")
+	case opts.Permanent:
+		fmt.Printf("  Space freed: %s\n", formatBytes(summary.FreedBytes))
+	default:
+		fmt.Printf("  Checkpoints moved to trash; space is freed when the trash is emptied\n")
+	}
+
+	if opts.Mode == models.RemoveExport {
+		fmt.Printf("  Exported to: %s\n", opts.ExportPath)
+	}
+}
+
+// exportGame handles the export command
+func (c *CLI) exportGame(args []string) error {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	game := fs.String("game", "", "Game ID or name (required)")
+	out := fs.String("out", "", "Bundle file to write (required)")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *game == "" || *out == "" {
+		return fmt.Errorf("both --game and --out are required")
+	}
+
+	count, err := c.service.ExportGame(*game, *out)
+	if err != nil {
+		return fmt.Errorf("failed to export game: %w", err)
+	}
+
+	fmt.Printf("✓ Exported %d checkpoint(s) to %s\n", count, *out)
+
+	return nil
+}
+
+// importBundle handles the import command
+func (c *CLI) importBundle(args []string) error {
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	file := fs.String("file", "", "Bundle file to import (required)")
+	path := fs.String("path", "", "Save directory path, if the game is not registered yet")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *file == "" {
+		return fmt.Errorf("--file is required")
+	}
+
+	game, count, err := c.service.ImportBundle(*file, *path)
+	if errors.Is(err, models.ErrArchiveLimit) {
+		return fmt.Errorf("failed to import bundle: %w (see 'gamekeep settings' to raise the limit if you trust it)", err)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to import bundle: %w", err)
+	}
+
+	fmt.Printf("✓ Imported %d checkpoint(s)\n", count)
+	fmt.Printf("  Game: %s (%s)\n", game.Name, game.ID)
+	fmt.Printf("  Path: %s\n", game.SavePath)
+
 	return nil
 }
 
@@ -121,7 +496,10 @@ func (c *CLI) createCheckpoint(args []string) error {
 	game := fs.String("game", "", "Game ID or name (required)")
 	name := fs.String("name", "", "Checkpoint name (required)")
 	note := fs.String("note", "", "Optional note")
-	
+	var fields listFlag
+	fs.Var(&fields, "field", "Custom field value as key=value (repeatable)")
+	force := fs.Bool("force", false, "Create the checkpoint even if the saves haven't changed")
+
 	if err := fs.Parse(args); err != nil {
 		return err
 	}
@@ -130,9 +508,32 @@ func (c *CLI) createCheckpoint(args []string) error {
 		return fmt.Errorf("both --game and --name are required")
 	}
 
+	values, err := parseFieldValues(fields)
+	if err != nil {
+		return err
+	}
+
 	fmt.Printf("Creating checkpoint...\n")
-	
-	checkpoint, err := c.service.CreateCheckpoint(*game, *name, *note)
+
+	ctx, cancel := interruptContext()
+	defer cancel()
+	progress, clearProgress := newProgressPrinter("Archiving")
+
+	opts := models.CheckpointOptions{Fields: values, Force: *force, Progress: progress}
+	checkpoint, err := c.service.CreateCheckpointContext(ctx, *game, *name, *note, opts)
+	clearProgress()
+	if errors.Is(err, context.Canceled) {
+		return fmt.Errorf("cancelled, no checkpoint was created")
+	}
+	var noChanges *models.NoChangesError
+	if errors.As(err, &noChanges) {
+		// Not a failure: the saves are already safe
+		latest := noChanges.Checkpoint
+		fmt.Printf("- No changes since the latest checkpoint, nothing created\n")
+		fmt.Printf("  Latest:  %s %s (%s)\n", latest.ID[:8], latest.Name, formatTime(latest.CreatedAt))
+		fmt.Printf("\nUse --force to create a checkpoint anyway.\n")
+		return nil
+	}
 	if err != nil {
 		return fmt.Errorf("failed to create checkpoint: %w", err)
 	}
@@ -143,9 +544,15 @@ func (c *CLI) createCheckpoint(args []string) error {
 	if checkpoint.Note != "" {
 		fmt.Printf("  Note:    %s\n", checkpoint.Note)
 	}
+	for _, key := range models.SortedFieldKeys(nil, checkpoint.Fields) {
+		fmt.Printf("  %-8s %s\n", key+":", checkpoint.Fields[key])
+	}
+	if checkpoint.SaveInfo != nil {
+		fmt.Printf("  Save:    %s (%s)\n", orDash(checkpoint.SaveInfo.Summary()), checkpoint.SaveInfo.Inspector)
+	}
 	fmt.Printf("  Created: %s\n", checkpoint.CreatedAt.Format("2006-01-02 15:04:05 MST"))
 	fmt.Printf("  Hash:    %s\n", checkpoint.Hash[:16]+"...")
-	
+
 	return nil
 }
 
@@ -153,7 +560,12 @@ func (c *CLI) createCheckpoint(args []string) error {
 func (c *CLI) listCheckpoints(args []string) error {
 	fs := flag.NewFlagSet("list", flag.ExitOnError)
 	game := fs.String("game", "", "Game ID or name (required)")
-	
+	tag := fs.String("tag", "", "Only list checkpoints with this tag")
+	sortBy := fs.String("sort", "", "Sort by created, name or a custom field")
+	desc := fs.Bool("desc", false, "Sort in descending order")
+	var where listFlag
+	fs.Var(&where, "where", "Filter by a custom field, e.g. level>=10 (repeatable)")
+
 	if err := fs.Parse(args); err != nil {
 		return err
 	}
@@ -162,148 +574,1421 @@ func (c *CLI) listCheckpoints(args []string) error {
 		return fmt.Errorf("--game is required")
 	}
 
-	checkpoints, err := c.service.ListCheckpoints(*game)
+	query := models.CheckpointQuery{Tag: *tag, SortBy: *sortBy, Descending: *desc}
+	for _, expr := range where {
+		filter, err := models.ParseFieldFilter(expr)
+		if err != nil {
+			return err
+		}
+		query.Filters = append(query.Filters, filter)
+	}
+
+	checkpoints, err := c.service.QueryCheckpoints(*game, query)
 	if err != nil {
 		return fmt.Errorf("failed to list checkpoints: %w", err)
 	}
 
+	// Field definitions give the column its order
+	var defs []models.FieldDef
+	if g, err := c.service.GetGame(*game); err == nil {
+		defs = g.Fields
+	}
+
 	if len(checkpoints) == 0 {
+		if *tag != "" || len(where) > 0 {
+			fmt.Printf("No matching checkpoints found for game: %s\n", *game)
+			return nil
+		}
 		fmt.Printf("No checkpoints found for game: %s\n", *game)
 		return nil
 	}
 
 	fmt.Printf("Checkpoints for %s (%d):\n\n", *game, len(checkpoints))
-	
+
 	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
-	fmt.Fprintln(w, "ID\tNAME\tCREATED\tNOTE")
-	fmt.Fprintln(w, "──\t────\t───────\t────")
-	
+	fmt.Fprintln(w, "ID\tNAME\tCREATED\tTAGS\tFIELDS\tNOTE")
+	fmt.Fprintln(w, "──\t────\t───────\t────\t──────\t────")
+
 	for _, cp := range checkpoints {
 		// Format created time
 		created := cp.CreatedAt.Local().Format("2006-01-02 15:04")
-		
+
 		// Shorten ID for display
 		shortID := cp.ID
 		if len(shortID) > 8 {
 			shortID = shortID[:8]
 		}
-		
+
 		// Truncate note if too long
 		note := cp.Note
 		if len(note) > 40 {
 			note = note[:37] + "..."
 		}
-		
-		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", shortID, cp.Name, created, note)
+
+		name := cp.Name
+		if cp.Protected {
+			name = "📌 " + name
+		}
+
+		fields := make([]string, 0, len(cp.Fields))
+		for _, key := range models.SortedFieldKeys(defs, cp.Fields) {
+			fields = append(fields, key+"="+cp.Fields[key])
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", shortID, name, created, orDash(strings.Join(cp.Tags, ",")), orDash(strings.Join(fields, " ")), note)
 	}
-	
+
 	w.Flush()
 	return nil
 }
 
-// restoreCheckpoint handles the restore command
-func (c *CLI) restoreCheckpoint(args []string) error {
-	fs := flag.NewFlagSet("restore", flag.ExitOnError)
-	checkpoint := fs.String("checkpoint", "", "Checkpoint ID (required)")
-	
+// showTree handles the tree command
+func (c *CLI) showTree(args []string) error {
+	fs := flag.NewFlagSet("tree", flag.ExitOnError)
+	game := fs.String("game", "", "Game ID or name (required)")
+
 	if err := fs.Parse(args); err != nil {
 		return err
 	}
 
-	if *checkpoint == "" {
-		return fmt.Errorf("--checkpoint is required")
+	if *game == "" {
+		return fmt.Errorf("--game is required")
 	}
 
-	// Get checkpoint details
-	cp, err := c.service.GetCheckpoint(*checkpoint)
+	roots, head, err := c.service.CheckpointTree(*game)
 	if err != nil {
-		return fmt.Errorf("failed to get checkpoint: %w", err)
+		return fmt.Errorf("failed to load checkpoint tree: %w", err)
 	}
 
-	fmt.Printf("Restoring checkpoint...\n")
-	fmt.Printf("  Name:    %s\n", cp.Name)
-	fmt.Printf("  Created: %s\n", cp.CreatedAt.Format("2006-01-02 15:04:05 MST"))
-	fmt.Printf("\n")
+	if len(roots) == 0 {
+		fmt.Printf("No checkpoints found for game: %s\n", *game)
+		return nil
+	}
 
-	if err := c.service.RestoreCheckpoint(*checkpoint); err != nil {
-		return fmt.Errorf("failed to restore checkpoint: %w", err)
+	fmt.Printf("Checkpoint tree for %s:\n\n", *game)
+	for _, root := range roots {
+		printTreeNode(root, head, "", "")
 	}
+	fmt.Printf("\n* marks the current save (last created or restored)\n")
 
-	fmt.Printf("✓ Checkpoint restored successfully\n")
-	
 	return nil
 }
 
-// deleteCheckpoint handles the delete command
-func (c *CLI) deleteCheckpoint(args []string) error {
-	fs := flag.NewFlagSet("delete", flag.ExitOnError)
-	checkpoint := fs.String("checkpoint", "", "Checkpoint ID (required)")
-	
-	if err := fs.Parse(args); err != nil {
-		return err
+// printTreeNode prints a checkpoint and its children as an ASCII tree
+func printTreeNode(node *models.CheckpointNode, head, prefix, childPrefix string) {
+	cp := node.Checkpoint
+
+	marker := "o"
+	if cp.ID == head {
+		marker = "*"
 	}
 
-	if *checkpoint == "" {
-		return fmt.Errorf("--checkpoint is required")
+	shortID := cp.ID
+	if len(shortID) > 8 {
+		shortID = shortID[:8]
 	}
 
-	if err := c.service.DeleteCheckpoint(*checkpoint); err != nil {
-		return fmt.Errorf("failed to delete checkpoint: %w", err)
+	name := cp.Name
+	if cp.Protected {
+		name = "📌 " + name
 	}
 
-	fmt.Printf("✓ Checkpoint deleted successfully\n")
-	
-	return nil
+	fmt.Printf("%s%s %s  %s  (%s)\n", prefix, marker, shortID, name, cp.CreatedAt.Local().Format("2006-01-02 15:04"))
+
+	for i, child := range node.Children {
+		if i == len(node.Children)-1 {
+			printTreeNode(child, head, childPrefix+"└── ", childPrefix+"    ")
+		} else {
+			printTreeNode(child, head, childPrefix+"├── ", childPrefix+"│   ")
+		}
+	}
 }
 
-// printUsage prints usage information
-func (c *CLI) printUsage() {
-	fmt.Printf(`GameKeep v%s - Game Save Manager (CLI)
+// inspectSave handles the inspect command
+func (c *CLI) inspectSave(args []string) error {
+	fs := flag.NewFlagSet("inspect", flag.ExitOnError)
+	game := fs.String("game", "", "Game ID or name")
+	list := fs.Bool("list", false, "List the available save inspectors")
 
-USAGE:
-    gamekeep <command> [options]
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
 
-COMMANDS:
-    add-game      Register a new game
-    list-games    List all registered games
-    checkpoint    Create a checkpoint for a game
-    list          List checkpoints for a game
-    restore       Restore a checkpoint
-    delete        Delete a checkpoint
-    version       Show version information
-    help          Show this help message
+	if *list {
+		for _, name := range c.service.Inspectors().Names() {
+			fmt.Println(name)
+		}
+		return nil
+	}
 
-EXAMPLES:
-    # Register a game
-    gamekeep add-game --name "The Witcher 3" --path "C:/Users/You/Documents/The Witcher 3"
+	if *game == "" {
+		return fmt.Errorf("--game or --list is required")
+	}
 
-    # Create a checkpoint
-    gamekeep checkpoint --game witcher3 --name "Before Boss Fight" --note "Level 25, fire build"
+	meta, err := c.service.InspectSave(*game)
+	if err != nil {
+		return fmt.Errorf("failed to inspect saves: %w", err)
+	}
+	if meta == nil {
+		fmt.Println("No inspector recognized the save files.")
+		return nil
+	}
 
-    # List checkpoints
-    gamekeep list --game witcher3
+	fmt.Printf("Inspector: %s\n", meta.Inspector)
+	fmt.Printf("  Level:    %s\n", orDash(meta.Level))
+	fmt.Printf("  Location: %s\n", orDash(meta.Location))
+	if meta.PlaytimeSeconds > 0 {
+		fmt.Printf("  Playtime: %s\n", models.FormatPlaytime(meta.PlaytimeSeconds))
+	} else {
+		fmt.Printf("  Playtime: -\n")
+	}
+	fmt.Printf("  Slots:    %s\n", orDash(strings.Join(meta.Slots, ", ")))
 
-    # Restore a checkpoint
-    gamekeep restore --checkpoint abc12345
+	if len(meta.Extra) > 0 {
+		keys := make([]string, 0, len(meta.Extra))
+		for key := range meta.Extra {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
 
-    # Delete a checkpoint
-    gamekeep delete --checkpoint abc12345
+		fmt.Printf("\nOther values (usable as custom fields):\n")
+		for _, key := range keys {
+			fmt.Printf("  %s = %s\n", key, meta.Extra[key])
+		}
+	}
 
-    # List all games
-    gamekeep list-games
+	return nil
+}
 
-NOTE: For GUI interface, run 'gamekeep-gui' instead.
+// parseRunArgs parses the run command's flags and the game command after
+// them
+func parseRunArgs(args []string) (game string, command []string, opts models.RunOptions, err error) {
+	fs := flag.NewFlagSet("run", flag.ExitOnError)
+	fs.StringVar(&game, "game", "", "Game ID or name")
+	fs.BoolVar(&opts.SkipPreCheckpoint, "no-pre", false, "Don't checkpoint before launch")
+	fs.BoolVar(&opts.SkipPostCheckpoint, "no-post", false, "Don't checkpoint after exit")
 
-For more information, visit: https://github.com/adrielfilipedesign/gamekeep
-`, version)
-}
+	if err := fs.Parse(args); err != nil {
+		return "", nil, opts, err
+	}
+
+	command = fs.Args()
+	if len(command) == 0 {
+		return "", nil, opts, fmt.Errorf("usage: gamekeep run --game <game> -- <command> [args...]")
+	}
+	return game, command, opts, nil
+}
+
+// runGame handles the run command
+func (c *CLI) runGame(args []string) error {
+	game, command, opts, err := parseRunArgs(args)
+	if err != nil {
+		return err
+	}
+
+	// Never keep the game from starting; just say what won't be tracked
+	opts.OnWarning = func(err error) {
+		fmt.Fprintf(os.Stderr, "gamekeep: warning: %v\n", err)
+	}
+
+	session, err := c.service.RunGame(game, command, opts)
+	if err != nil {
+		return err
+	}
+
+	if session.GameID != "" {
+		fmt.Fprintf(os.Stderr, "gamekeep: played for %s", session.Duration().Round(time.Second))
+		if session.PostCheckpointID != "" {
+			fmt.Fprintf(os.Stderr, ", saves checkpointed as %s", session.PostCheckpointID[:8])
+		} else if !session.SavesChanged {
+			fmt.Fprintf(os.Stderr, ", saves unchanged")
+		}
+		fmt.Fprintln(os.Stderr)
+	}
+
+	// Pass the game's exit code on to whatever launched us
+	if session.ExitCode != 0 {
+		return &exitCodeError{code: session.ExitCode}
+	}
+	return nil
+}
+
+// runUntracked runs a game's command when GameKeep couldn't open its
+// config or vault, skipping the checkpoints around it rather than keeping
+// the game from starting
+func runUntracked(args []string, cause error) error {
+	_, command, _, err := parseRunArgs(args)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "gamekeep: warning: not checkpointing this session: %v\n", cause)
+
+	process, err := launcher.Start(command)
+	if err != nil {
+		return fmt.Errorf("failed to start game: %w", err)
+	}
+	code, err := process.Wait()
+	if err != nil {
+		return fmt.Errorf("failed to wait for game: %w", err)
+	}
+	if code != 0 {
+		return &exitCodeError{code: code}
+	}
+	return nil
+}
+
+// watch handles the watch command
+func (c *CLI) watch(args []string) error {
+	fs := flag.NewFlagSet("watch", flag.ExitOnError)
+	var opts models.WatchOptions
+	fs.DurationVar(&opts.Interval, "interval", 0, "How often to look for games (default from settings)")
+	fs.BoolVar(&opts.SkipExitCheckpoint, "no-checkpoint", false, "Don't checkpoint saves when a game exits")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	c.service.SetOrigin(models.OriginDaemon)
+	opts.OnEvent = printWatchEvent
+	watcher := c.service.NewWatcher(opts)
+
+	stop, cancel := interruptChannel()
+	defer cancel()
+
+	fmt.Printf("Watching for games, press Ctrl-C to stop...\n")
+	if err := watcher.Run(stop); err != nil {
+		return fmt.Errorf("failed to watch games: %w", err)
+	}
+
+	for _, game := range watcher.Running() {
+		fmt.Printf("%s is still running; this session won't be recorded\n", game.GameName)
+	}
+	return nil
+}
+
+// interruptChannel returns a channel that is closed on Ctrl-C or SIGTERM,
+// and a function to stop listening for them
+func interruptChannel() (<-chan struct{}, func()) {
+	stop := make(chan struct{})
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		if _, ok := <-signals; ok {
+			close(stop)
+		}
+	}()
+	return stop, func() {
+		signal.Stop(signals)
+		close(signals)
+	}
+}
+
+// interruptContext returns a context cancelled by Ctrl-C, so long
+// operations can stop cleanly, and a function that stops listening
+func interruptContext() (context.Context, context.CancelFunc) {
+	return signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+}
+
+// newProgressPrinter returns a ProgressFunc that keeps a progress line
+// up to date on stderr, and a function that clears it. Nothing is drawn
+// when stderr isn't a terminal.
+func newProgressPrinter(label string) (models.ProgressFunc, func()) {
+	info, err := os.Stderr.Stat()
+	if err != nil || info.Mode()&os.ModeCharDevice == 0 {
+		return nil, func() {}
+	}
+
+	drawn := false
+	progress := func(p models.Progress) {
+		fmt.Fprintf(os.Stderr, "\r\033[K  %s %3.0f%%  %d/%d files, %s of %s",
+			label, p.Fraction()*100, p.FilesDone, p.FilesTotal, formatBytes(p.BytesDone), formatBytes(p.BytesTotal))
+		drawn = true
+	}
+	return progress, func() {
+		if drawn {
+			fmt.Fprint(os.Stderr, "\r\033[K")
+		}
+	}
+}
+
+// printWatchEvent prints what the watcher noticed
+func printWatchEvent(event models.WatchEvent) {
+	at := event.At.Local().Format("15:04:05")
+	switch event.Type {
+	case models.WatchStarted:
+		fmt.Printf("[%s] ▶ %s started (pid %s)\n", at, event.GameName, joinPIDs(event.PIDs))
+	case models.WatchStopped:
+		session := event.Session
+		fmt.Printf("[%s] ■ %s stopped after %s", at, event.GameName, session.Duration().Round(time.Second))
+		if session.PostCheckpointID != "" {
+			fmt.Printf(", saves checkpointed as %s", session.PostCheckpointID[:8])
+		} else if !session.SavesChanged {
+			fmt.Printf(", saves unchanged")
+		}
+		fmt.Println()
+		if event.Err != nil {
+			fmt.Fprintf(os.Stderr, "  Warning: %v\n", event.Err)
+		}
+	case models.WatchError:
+		fmt.Fprintf(os.Stderr, "[%s] Warning: %v\n", at, event.Err)
+	}
+}
+
+// joinPIDs formats process IDs as a comma-separated list
+func joinPIDs(pids []int) string {
+	parts := make([]string, len(pids))
+	for i, pid := range pids {
+		parts[i] = strconv.Itoa(pid)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// exitCodeError makes the CLI exit with a code without printing an error
+type exitCodeError struct {
+	code int
+}
+
+func (e *exitCodeError) Error() string {
+	return fmt.Sprintf("exit code %d", e.code)
+}
+
+// restoreCheckpoint handles the restore command
+func (c *CLI) restoreCheckpoint(args []string) error {
+	fs := flag.NewFlagSet("restore", flag.ExitOnError)
+	checkpoint := fs.String("checkpoint", "", "Checkpoint ID (required)")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *checkpoint == "" {
+		return fmt.Errorf("--checkpoint is required")
+	}
+
+	// Get checkpoint details
+	cp, err := c.service.GetCheckpoint(*checkpoint)
+	if err != nil {
+		return fmt.Errorf("failed to get checkpoint: %w", err)
+	}
+
+	fmt.Printf("Restoring checkpoint...\n")
+	fmt.Printf("  Name:    %s\n", cp.Name)
+	fmt.Printf("  Created: %s\n", cp.CreatedAt.Format("2006-01-02 15:04:05 MST"))
+	fmt.Printf("\n")
+
+	ctx, cancel := interruptContext()
+	defer cancel()
+	progress, clearProgress := newProgressPrinter("Extracting")
+
+	err = c.service.RestoreCheckpointContext(ctx, *checkpoint, progress)
+	clearProgress()
+	if errors.Is(err, context.Canceled) {
+		return fmt.Errorf("cancelled, the saves were left as they were")
+	}
+	if errors.Is(err, models.ErrArchiveLimit) {
+		return fmt.Errorf("failed to restore checkpoint: %w (see 'gamekeep settings' to raise the limit if you trust it)", err)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to restore checkpoint: %w", err)
+	}
+
+	fmt.Printf("✓ Checkpoint restored successfully\n")
+
+	return nil
+}
+
+// editCheckpoint handles the edit-checkpoint command
+func (c *CLI) editCheckpoint(args []string) error {
+	fs := flag.NewFlagSet("edit-checkpoint", flag.ExitOnError)
+	checkpoint := fs.String("checkpoint", "", "Checkpoint ID (required)")
+	name := fs.String("name", "", "New checkpoint name")
+	note := fs.String("note", "", "New note (use --note \"\" to clear)")
+	protected := fs.Bool("protected", false, "Protect (true) or unprotect (false) the checkpoint")
+	var fields listFlag
+	fs.Var(&fields, "field", "Set a custom field as key=value, or clear it with key= (repeatable)")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *checkpoint == "" {
+		return fmt.Errorf("--checkpoint is required")
+	}
+
+	// Only change the fields that were given
+	var update models.CheckpointUpdate
+	fs.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "name":
+			update.Name = name
+		case "note":
+			update.Note = note
+		case "protected":
+			update.Protected = protected
+		}
+	})
+
+	values, err := parseFieldValues(fields)
+	if err != nil {
+		return err
+	}
+	update.Fields = values
+
+	if update.Name == nil && update.Note == nil && update.Protected == nil && len(update.Fields) == 0 {
+		return fmt.Errorf("nothing to change: use --name, --note, --protected or --field")
+	}
+
+	cp, err := c.service.UpdateCheckpoint(*checkpoint, update)
+	if err != nil {
+		return fmt.Errorf("failed to update checkpoint: %w", err)
+	}
+
+	fmt.Printf("✓ Checkpoint updated successfully\n")
+	fmt.Printf("  ID:   %s\n", cp.ID)
+	fmt.Printf("  Name: %s\n", cp.Name)
+	if cp.Note != "" {
+		fmt.Printf("  Note: %s\n", cp.Note)
+	}
+	if cp.Protected {
+		fmt.Printf("  📌 Protected\n")
+	}
+
+	return nil
+}
+
+// tagCheckpoint handles the tag command
+func (c *CLI) tagCheckpoint(args []string) error {
+	fs := flag.NewFlagSet("tag", flag.ExitOnError)
+	checkpoint := fs.String("checkpoint", "", "Checkpoint ID (required)")
+	add := fs.String("add", "", "Comma-separated tags to add")
+	remove := fs.String("remove", "", "Comma-separated tags to remove")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *checkpoint == "" {
+		return fmt.Errorf("--checkpoint is required")
+	}
+	if *add == "" && *remove == "" {
+		return fmt.Errorf("nothing to change: use --add and/or --remove")
+	}
+
+	var cp *models.Checkpoint
+	var err error
+	if *add != "" {
+		if cp, err = c.service.AddCheckpointTags(*checkpoint, splitList(*add)...); err != nil {
+			return fmt.Errorf("failed to add tags: %w", err)
+		}
+	}
+	if *remove != "" {
+		if cp, err = c.service.RemoveCheckpointTags(*checkpoint, splitList(*remove)...); err != nil {
+			return fmt.Errorf("failed to remove tags: %w", err)
+		}
+	}
+
+	fmt.Printf("✓ Tags updated\n")
+	fmt.Printf("  Name: %s\n", cp.Name)
+	fmt.Printf("  Tags: %s\n", orDash(strings.Join(cp.Tags, ", ")))
+
+	return nil
+}
+
+// deleteCheckpoint handles the delete command
+func (c *CLI) deleteCheckpoint(args []string) error {
+	fs := flag.NewFlagSet("delete", flag.ExitOnError)
+	checkpoint := fs.String("checkpoint", "", "Checkpoint ID (required)")
+	force := fs.Bool("force", false, "Delete even if the checkpoint is protected")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *checkpoint == "" {
+		return fmt.Errorf("--checkpoint is required")
+	}
+
+	if err := c.service.DeleteCheckpoint(*checkpoint, *force); err != nil {
+		if errors.Is(err, models.ErrCheckpointProtected) {
+			return fmt.Errorf("failed to delete checkpoint: %w (use --force to delete it anyway)", err)
+		}
+		return fmt.Errorf("failed to delete checkpoint: %w", err)
+	}
+
+	fmt.Printf("✓ Checkpoint moved to trash\n")
+	fmt.Printf("  Use 'gamekeep trash restore --checkpoint %s' to undo\n", *checkpoint)
+
+	return nil
+}
+
+// trash handles the trash command and its subcommands
+func (c *CLI) trash(args []string) error {
+	if len(args) < 1 {
+		return fmt.Errorf("usage: gamekeep trash <list|restore|empty> [options]")
+	}
+
+	switch args[0] {
+	case "list":
+		return c.listTrash()
+	case "restore":
+		return c.restoreFromTrash(args[1:])
+	case "empty":
+		return c.emptyTrash(args[1:])
+	default:
+		return fmt.Errorf("unknown trash command: %s", args[0])
+	}
+}
+
+// listTrash handles the trash list command
+func (c *CLI) listTrash() error {
+	entries, err := c.service.ListTrash()
+	if err != nil {
+		return fmt.Errorf("failed to list trash: %w", err)
+	}
+
+	if len(entries) == 0 {
+		fmt.Println("Trash is empty.")
+		return nil
+	}
+
+	fmt.Printf("Trash (%d):\n\n", len(entries))
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "ID\tNAME\tGAME\tDELETED\tEXPIRES")
+	fmt.Fprintln(w, "──\t────\t────\t───────\t───────")
+
+	for _, entry := range entries {
+		cp := entry.Checkpoint
+
+		shortID := cp.ID
+		if len(shortID) > 8 {
+			shortID = shortID[:8]
+		}
+
+		expires := "never"
+		if !entry.ExpiresAt.IsZero() {
+			expires = entry.ExpiresAt.Local().Format("2006-01-02 15:04")
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", shortID, cp.Name, cp.GameID, entry.DeletedAt.Local().Format("2006-01-02 15:04"), expires)
+	}
+
+	w.Flush()
+	return nil
+}
+
+// restoreFromTrash handles the trash restore command
+func (c *CLI) restoreFromTrash(args []string) error {
+	fs := flag.NewFlagSet("trash restore", flag.ExitOnError)
+	checkpoint := fs.String("checkpoint", "", "Checkpoint ID (required)")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *checkpoint == "" {
+		return fmt.Errorf("--checkpoint is required")
+	}
+
+	cp, err := c.service.RestoreFromTrash(*checkpoint)
+	if err != nil {
+		return fmt.Errorf("failed to restore from trash: %w", err)
+	}
+
+	fmt.Printf("✓ Checkpoint '%s' restored from trash\n", cp.Name)
+
+	return nil
+}
+
+// emptyTrash handles the trash empty command
+func (c *CLI) emptyTrash(args []string) error {
+	fs := flag.NewFlagSet("trash empty", flag.ExitOnError)
+	expired := fs.Bool("expired", false, "Only remove checkpoints past their expiry")
+	checkpoint := fs.String("checkpoint", "", "Only remove this checkpoint")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *checkpoint != "" {
+		if err := c.service.DeleteFromTrash(*checkpoint); err != nil {
+			return fmt.Errorf("failed to delete from trash: %w", err)
+		}
+		fmt.Printf("✓ Checkpoint permanently deleted\n")
+		return nil
+	}
+
+	var count int
+	var err error
+	if *expired {
+		count, err = c.service.PurgeExpiredTrash()
+	} else {
+		count, err = c.service.EmptyTrash()
+	}
+	if err != nil {
+		return fmt.Errorf("failed to empty trash: %w", err)
+	}
+
+	fmt.Printf("✓ Permanently deleted %d checkpoint(s)\n", count)
+
+	return nil
+}
+
+// schedule handles the schedule command
+func (c *CLI) schedule(args []string) error {
+	if len(args) < 1 {
+		return fmt.Errorf("usage: gamekeep schedule <add|list|pause|resume|remove|run> [options]")
+	}
+
+	switch args[0] {
+	case "add":
+		return c.addSchedule(args[1:])
+	case "list":
+		return c.listSchedules(args[1:])
+	case "pause":
+		return c.pauseSchedule(args[1:], true)
+	case "resume":
+		return c.pauseSchedule(args[1:], false)
+	case "remove":
+		return c.removeSchedule(args[1:])
+	case "run":
+		return c.runScheduler(args[1:])
+	default:
+		return fmt.Errorf("unknown schedule command: %s", args[0])
+	}
+}
+
+// addSchedule handles the schedule add command
+func (c *CLI) addSchedule(args []string) error {
+	fs := flag.NewFlagSet("schedule add", flag.ExitOnError)
+	game := fs.String("game", "", "Game ID or name (required)")
+	every := fs.Duration("every", 0, "Checkpoint this often while the saves keep changing (e.g. 30m)")
+	daily := fs.String("daily", "", "Checkpoint once a day at this local time (e.g. 21:30)")
+	cron := fs.String("cron", "", "Checkpoint on a cron schedule in local time (e.g. \"0 */2 * * *\")")
+	paused := fs.Bool("paused", false, "Add the schedule paused")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *game == "" {
+		return fmt.Errorf("--game is required")
+	}
+
+	spec := models.Schedule{Paused: *paused}
+	given := 0
+	if *every != 0 {
+		if *every%time.Minute != 0 {
+			return fmt.Errorf("--every must be whole minutes")
+		}
+		spec.Kind, spec.IntervalMinutes = models.ScheduleInterval, int(*every/time.Minute)
+		given++
+	}
+	if *daily != "" {
+		spec.Kind, spec.DailyAt = models.ScheduleDaily, *daily
+		given++
+	}
+	if *cron != "" {
+		spec.Kind, spec.Cron = models.ScheduleCron, *cron
+		given++
+	}
+	if given != 1 {
+		return fmt.Errorf("give exactly one of --every, --daily or --cron")
+	}
+
+	schedule, err := c.service.AddSchedule(*game, spec)
+	if err != nil {
+		return fmt.Errorf("failed to add schedule: %w", err)
+	}
+
+	fmt.Printf("✓ Schedule added\n")
+	fmt.Printf("  ID:       %s\n", schedule.ID[:8])
+	fmt.Printf("  Game:     %s\n", schedule.GameID)
+	fmt.Printf("  When:     %s\n", schedule.Describe())
+	fmt.Printf("  Next run: %s\n", formatTime(schedule.NextRun()))
+	fmt.Printf("\nSchedules run while 'gamekeep schedule run' or the GUI is running.\n")
+
+	return nil
+}
+
+// listSchedules handles the schedule list command
+func (c *CLI) listSchedules(args []string) error {
+	fs := flag.NewFlagSet("schedule list", flag.ExitOnError)
+	game := fs.String("game", "", "Only list this game's schedules")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	schedules, err := c.service.ListSchedules(*game)
+	if err != nil {
+		return fmt.Errorf("failed to list schedules: %w", err)
+	}
+
+	if len(schedules) == 0 {
+		fmt.Println("No schedules. Add one with 'gamekeep schedule add'.")
+		return nil
+	}
+
+	fmt.Printf("Schedules (%d):\n\n", len(schedules))
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "ID\tGAME\tWHEN\tLAST RUN\tNEXT RUN\tSTATUS")
+	fmt.Fprintln(w, "──\t────\t────\t────────\t────────\t──────")
+
+	for _, schedule := range schedules {
+		lastRun := "-"
+		if schedule.LastRunAt != nil {
+			lastRun = formatTime(*schedule.LastRunAt)
+		}
+
+		next, status := formatTime(schedule.NextRun()), "active"
+		switch {
+		case schedule.Paused:
+			next, status = "-", "paused"
+		case schedule.LastError != "":
+			status = "failed: " + schedule.LastError
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", schedule.ID[:8], schedule.GameID, schedule.Describe(), lastRun, next, status)
+	}
+
+	w.Flush()
+	return nil
+}
+
+// pauseSchedule handles the schedule pause and resume commands
+func (c *CLI) pauseSchedule(args []string, paused bool) error {
+	fs := flag.NewFlagSet("schedule", flag.ExitOnError)
+	id := fs.String("schedule", "", "Schedule ID (required)")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *id == "" {
+		return fmt.Errorf("--schedule is required")
+	}
+
+	schedule, err := c.service.SetSchedulePaused(*id, paused)
+	if err != nil {
+		return fmt.Errorf("failed to update schedule: %w", err)
+	}
+
+	if paused {
+		fmt.Printf("✓ Schedule paused: %s %s\n", schedule.GameID, schedule.Describe())
+	} else {
+		fmt.Printf("✓ Schedule resumed: %s %s\n", schedule.GameID, schedule.Describe())
+	}
+	return nil
+}
+
+// removeSchedule handles the schedule remove command
+func (c *CLI) removeSchedule(args []string) error {
+	fs := flag.NewFlagSet("schedule remove", flag.ExitOnError)
+	id := fs.String("schedule", "", "Schedule ID (required)")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *id == "" {
+		return fmt.Errorf("--schedule is required")
+	}
+
+	if err := c.service.RemoveSchedule(*id); err != nil {
+		return fmt.Errorf("failed to remove schedule: %w", err)
+	}
+
+	fmt.Printf("✓ Schedule removed (its checkpoints are kept)\n")
+	return nil
+}
+
+// runScheduler handles the schedule run command
+func (c *CLI) runScheduler(args []string) error {
+	fs := flag.NewFlagSet("schedule run", flag.ExitOnError)
+	once := fs.Bool("once", false, "Run the schedules that are due, then exit")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	c.service.SetOrigin(models.OriginDaemon)
+	scheduler := c.service.NewScheduler(models.SchedulerOptions{OnRun: printScheduleRun})
+
+	if *once {
+		runs, err := scheduler.RunDue(time.Now())
+		if err != nil {
+			return fmt.Errorf("failed to run schedules: %w", err)
+		}
+		if len(runs) == 0 {
+			fmt.Println("No schedules are due.")
+		}
+		return nil
+	}
+
+	stop, cancel := interruptChannel()
+	defer cancel()
+
+	fmt.Printf("Running schedules, press Ctrl-C to stop...\n")
+	return scheduler.Run(stop)
+}
+
+// printScheduleRun prints what a schedule run did
+func printScheduleRun(run models.ScheduleRun) {
+	at := run.At.Local().Format("15:04:05")
+	switch {
+	case run.ScheduleID == "":
+		fmt.Fprintf(os.Stderr, "[%s] Warning: %v\n", at, run.Err)
+	case run.Err != nil:
+		fmt.Fprintf(os.Stderr, "[%s] ✗ %s: %v\n", at, orDash(run.GameName), run.Err)
+	case run.Skipped:
+		fmt.Printf("[%s] - %s: saves unchanged, skipped\n", at, run.GameName)
+	default:
+		fmt.Printf("[%s] ✓ %s: checkpoint %s\n", at, run.GameName, run.CheckpointID[:8])
+	}
+}
+
+// hook handles the hook command
+func (c *CLI) hook(args []string) error {
+	if len(args) < 1 {
+		return fmt.Errorf("usage: gamekeep hook <add|list|remove> [options]")
+	}
+
+	switch args[0] {
+	case "add":
+		return c.addHook(args[1:])
+	case "list":
+		return c.listHooks(args[1:])
+	case "remove":
+		return c.removeHook(args[1:])
+	default:
+		return fmt.Errorf("unknown hook command: %s", args[0])
+	}
+}
+
+// addHook handles the hook add command
+func (c *CLI) addHook(args []string) error {
+	fs := flag.NewFlagSet("hook add", flag.ExitOnError)
+	game := fs.String("game", "", "Only run the hook for this game (default: every game)")
+	event := fs.String("event", "", "When to run: pre-/post-checkpoint, pre-/post-restore or pre-/post-delete (required)")
+	command := fs.String("command", "", "Shell command to run (required)")
+	timeout := fs.Duration("timeout", models.DefaultHookTimeoutSeconds*time.Second, "How long the hook may run")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *event == "" || *command == "" {
+		return fmt.Errorf("--event and --command are required")
+	}
+
+	hook := models.Hook{
+		Event:          models.HookEvent(*event),
+		Command:        *command,
+		TimeoutSeconds: int(timeout.Round(time.Second) / time.Second),
+	}
+	if err := c.service.AddHook(*game, hook); err != nil {
+		return fmt.Errorf("failed to add hook: %w", err)
+	}
+
+	fmt.Printf("✓ Hook added\n")
+	fmt.Printf("  Event:   %s\n", hook.Event)
+	fmt.Printf("  Command: %s\n", hook.Command)
+	fmt.Printf("  Timeout: %s\n", hook.Timeout())
+	if *game != "" {
+		fmt.Printf("  Game:    %s\n", *game)
+	}
+	return nil
+}
+
+// listHooks handles the hook list command
+func (c *CLI) listHooks(args []string) error {
+	fs := flag.NewFlagSet("hook list", flag.ExitOnError)
+	game := fs.String("game", "", "List this game's hooks instead of the global ones")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	hooks, err := c.service.ListHooks(*game)
+	if err != nil {
+		return fmt.Errorf("failed to list hooks: %w", err)
+	}
+
+	scope := "Global hooks"
+	if *game != "" {
+		scope = fmt.Sprintf("Hooks for %s", *game)
+	}
+	if len(hooks) == 0 {
+		fmt.Printf("%s: none. Add one with 'gamekeep hook add'.\n", scope)
+		return nil
+	}
+
+	fmt.Printf("%s (%d):\n\n", scope, len(hooks))
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "#\tEVENT\tTIMEOUT\tCOMMAND")
+	fmt.Fprintln(w, "─\t─────\t───────\t───────")
+	for i, hook := range hooks {
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", i+1, hook.Event, hook.Timeout(), hook.Command)
+	}
+	w.Flush()
+
+	return nil
+}
+
+// removeHook handles the hook remove command
+func (c *CLI) removeHook(args []string) error {
+	fs := flag.NewFlagSet("hook remove", flag.ExitOnError)
+	game := fs.String("game", "", "Remove one of this game's hooks instead of a global one")
+	number := fs.Int("number", 0, "Hook number from 'gamekeep hook list' (required)")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *number < 1 {
+		return fmt.Errorf("--number is required")
+	}
+
+	if err := c.service.RemoveHook(*game, *number-1); err != nil {
+		return fmt.Errorf("failed to remove hook: %w", err)
+	}
+
+	fmt.Printf("✓ Hook %d removed\n", *number)
+	return nil
+}
+
+// settings handles the settings command
+func (c *CLI) settings(args []string) error {
+	fs := flag.NewFlagSet("settings", flag.ExitOnError)
+	trashDays := fs.Int("trash-days", -1, "Days to keep deleted checkpoints (0 = until emptied)")
+	watchGames := fs.Bool("watch-games", true, "Watch for running games while the GUI is open")
+	watchInterval := fs.Int("watch-interval", 0, "Seconds between looks for running games")
+	exitCheckpoint := fs.Bool("checkpoint-on-exit", true, "Have the watcher checkpoint saves when a game exits")
+	cacheVerification := fs.Bool("cache-verification", true, "Skip re-reading unchanged archives to verify them before a restore")
+	workers := fs.Int("compression-workers", 0, "Files to compress at once when checkpointing (0 = one per CPU)")
+	maxSize := fs.Int64("max-restore-size", 0, "Most MB a restored or imported archive may unpack to (0 = no limit)")
+	maxEntries := fs.Int("max-entries", 0, "Most entries a restored or imported archive may hold (0 = no limit)")
+	maxRatio := fs.Int("max-ratio", 0, "Most a file in a restored or imported archive may compress, as N:1; zstd files 16 times that (0 = no limit)")
+	maxDepth := fs.Int("max-depth", 0, "Most directories deep a restored or imported archive may nest files (0 = no limit)")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	settings, err := c.service.GetSettings()
+	if err != nil {
+		return fmt.Errorf("failed to load settings: %w", err)
+	}
+
+	changed := false
+	if *trashDays >= 0 {
+		settings.TrashRetentionDays = *trashDays
+		changed = true
+	}
+	fs.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "watch-games":
+			settings.WatchGames = *watchGames
+			changed = true
+		case "watch-interval":
+			settings.WatchIntervalSeconds = *watchInterval
+			changed = true
+		case "checkpoint-on-exit":
+			settings.CheckpointOnExit = *exitCheckpoint
+			changed = true
+		case "cache-verification":
+			settings.CacheVerification = *cacheVerification
+			changed = true
+		case "compression-workers":
+			settings.CompressionWorkers = *workers
+			changed = true
+		case "max-restore-size":
+			settings.ArchiveLimits.MaxSizeMB = *maxSize
+			changed = true
+		case "max-entries":
+			settings.ArchiveLimits.MaxEntries = *maxEntries
+			changed = true
+		case "max-ratio":
+			settings.ArchiveLimits.MaxRatio = *maxRatio
+			changed = true
+		case "max-depth":
+			settings.ArchiveLimits.MaxDepth = *maxDepth
+			changed = true
+		}
+	})
+
+	if changed {
+		if err := c.service.UpdateSettings(settings); err != nil {
+			return fmt.Errorf("failed to update settings: %w", err)
+		}
+		fmt.Printf("✓ Settings updated\n")
+	}
+
+	fmt.Printf("Settings:\n")
+	fmt.Printf("  Trash retention:    %d day(s)\n", settings.TrashRetentionDays)
+	fmt.Printf("  Watch games:        %t\n", settings.WatchGames)
+	fmt.Printf("  Watch interval:     %d second(s)\n", settings.WatchIntervalSeconds)
+	fmt.Printf("  Checkpoint on exit: %t\n", settings.CheckpointOnExit)
+	fmt.Printf("  Cache verification: %t\n", settings.CacheVerification)
+	if settings.CompressionWorkers == 0 {
+		fmt.Printf("  Compression:        one worker per CPU\n")
+	} else {
+		fmt.Printf("  Compression:        %d worker(s)\n", settings.CompressionWorkers)
+	}
+	limits := settings.ArchiveLimits
+	fmt.Printf("  Max restore size:   %s\n", limitText(limits.MaxSizeMB, " MB"))
+	fmt.Printf("  Max entries:        %s\n", limitText(int64(limits.MaxEntries), ""))
+	fmt.Printf("  Max ratio:          %s\n", limitText(int64(limits.MaxRatio), ":1"))
+	fmt.Printf("  Max depth:          %s\n", limitText(int64(limits.MaxDepth), " directories"))
+
+	return nil
+}
+
+// limitText formats an archive limit, which is off at zero
+func limitText(n int64, unit string) string {
+	if n == 0 {
+		return "off"
+	}
+	return fmt.Sprintf("%d%s", n, unit)
+}
+
+// showLog handles the log command
+func (c *CLI) showLog(args []string) error {
+	fs := flag.NewFlagSet("log", flag.ExitOnError)
+	game := fs.String("game", "", "Only show operations on this game")
+	op := fs.String("op", "", "Only show this operation (e.g. restore-checkpoint)")
+	origin := fs.String("origin", "", "Only show operations from cli, gui or daemon")
+	since := fs.String("since", "", "Only show operations since a duration ago (e.g. 24h) or a date (YYYY-MM-DD)")
+	failed := fs.Bool("failed", false, "Only show failed operations")
+	limit := fs.Int("limit", 50, "Maximum number of entries to show (0 = all)")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	filter := models.JournalFilter{
+		Operation:  models.Operation(*op),
+		Origin:     models.Origin(*origin),
+		FailedOnly: *failed,
+		Limit:      *limit,
+	}
+
+	if *game != "" {
+		// Games may have been removed since, so fall back to the raw ID
+		filter.GameID = *game
+		if g, err := c.service.GetGame(*game); err == nil {
+			filter.GameID = g.ID
+		}
+	}
+
+	if *since != "" {
+		t, err := parseSince(*since)
+		if err != nil {
+			return err
+		}
+		filter.Since = t
+	}
+
+	entries, err := c.service.ListJournal(filter)
+	if err != nil {
+		return fmt.Errorf("failed to read log: %w", err)
+	}
+
+	if len(entries) == 0 {
+		fmt.Println("No matching operations found.")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "TIME\tOPERATION\tGAME\tCHECKPOINT\tDETAIL\tORIGIN\tHOST\tRESULT")
+	fmt.Fprintln(w, "────\t─────────\t────\t──────────\t──────\t──────\t────\t──────")
+
+	for _, entry := range entries {
+		shortID := entry.CheckpointID
+		if len(shortID) > 8 {
+			shortID = shortID[:8]
+		}
+
+		result := "✓"
+		if entry.Outcome == models.OutcomeFailure {
+			result = "✗ " + entry.Error
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			entry.Timestamp.Local().Format("2006-01-02 15:04:05"),
+			entry.Operation,
+			orDash(entry.GameID),
+			orDash(shortID),
+			orDash(entry.Detail),
+			entry.Origin,
+			entry.Hostname,
+			result,
+		)
+	}
+
+	w.Flush()
+	return nil
+}
+
+// parseSince parses a --since value as a duration ago or a date
+func parseSince(value string) (time.Time, error) {
+	if d, err := time.ParseDuration(value); err == nil {
+		return time.Now().Add(-d), nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid --since value %q: use a duration like 24h or a date like 2024-01-31", value)
+}
+
+// printCandidates lists the matches behind an ambiguous identifier error
+func printCandidates(err error) {
+	var ambiguous *models.AmbiguousError
+	if !errors.As(err, &ambiguous) {
+		return
+	}
+
+	fmt.Fprintf(os.Stderr, "\nDid you mean one of these?\n\n")
+
+	w := tabwriter.NewWriter(os.Stderr, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "ID\tNAME\tDETAILS")
+	fmt.Fprintln(w, "──\t────\t───────")
+	for _, candidate := range ambiguous.Candidates {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", candidate.ID, candidate.Name, candidate.Detail)
+	}
+	w.Flush()
+
+	fmt.Fprintf(os.Stderr, "\nUse the full %s ID, or enough of it to match only one.\n", ambiguous.Kind)
+}
+
+// formatBytes formats a byte count for display
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+// listFlag collects the values of a repeatable flag
+type listFlag []string
+
+func (l *listFlag) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *listFlag) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
+// parseFieldValues parses key=value pairs from --field flags
+func parseFieldValues(pairs []string) (map[string]string, error) {
+	values := make(map[string]string, len(pairs))
+	for _, pair := range pairs {
+		key, value, ok := strings.Cut(pair, "=")
+		key = strings.ToLower(strings.TrimSpace(key))
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid --field %q: use key=value", pair)
+		}
+		values[key] = value
+	}
+	return values, nil
+}
+
+// changeFieldDefs adds or retypes field definitions and drops others
+func changeFieldDefs(current []models.FieldDef, define, drop []string) ([]models.FieldDef, error) {
+	defs := append([]models.FieldDef{}, current...)
+
+	for _, spec := range define {
+		def, err := models.ParseFieldDef(spec)
+		if err != nil {
+			return nil, err
+		}
+		if existing := models.FindField(defs, def.Key); existing != nil {
+			existing.Type = def.Type
+		} else {
+			defs = append(defs, def)
+		}
+	}
+
+	for _, key := range drop {
+		key = strings.ToLower(strings.TrimSpace(key))
+		if models.FindField(defs, key) == nil {
+			return nil, fmt.Errorf("field %s is not defined", key)
+		}
+		kept := defs[:0]
+		for _, def := range defs {
+			if def.Key != key {
+				kept = append(kept, def)
+			}
+		}
+		defs = kept
+	}
+
+	return defs, nil
+}
+
+// splitList splits a comma-separated flag value
+func splitList(value string) []string {
+	return strings.Split(value, ",")
+}
+
+// orDash returns s, or "-" when s is empty
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+// formatTime formats a timestamp in local time, or "-" if it is unknown
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Local().Format("2006-01-02 15:04")
+}
+
+// printUsage prints usage information
+func (c *CLI) printUsage() {
+	fmt.Printf(`GameKeep v%s - Game Save Manager (CLI)
+
+USAGE:
+    gamekeep <command> [options]
+
+COMMANDS:
+    add-game          Register a new game
+    list-games        List all registered games
+    show-game         Show a game's details and checkpoint stats
+    edit-game         Change a game's name, save directory or details
+    remove-game       Remove a game, keeping, deleting or exporting its checkpoints
+    export            Export a game and its checkpoints to a bundle file
+    import            Import a bundle file written by export
+    checkpoint        Create a checkpoint for a game
+    list              List checkpoints for a game
+    tree              Show how a game's checkpoints branch
+    inspect           Show what can be read from a game's save files
+    run               Launch a game, checkpointing before and after playing
+    watch             Notice games as they run and checkpoint them when they exit
+    schedule          Add, list, pause or remove automatic checkpoint schedules
+    hook              Add, list or remove scripts run around checkpoints, restores and deletes
+    restore           Restore a checkpoint
+    edit-checkpoint   Rename, re-note or protect a checkpoint
+    tag               Add or remove checkpoint tags
+    delete            Move a checkpoint to the trash
+    trash             List, restore or empty deleted checkpoints
+    settings          Show or change settings
+    log               Show the history of operations
+    version           Show version information
+    help              Show this help message
+
+EXAMPLES:
+    # Register a game
+    gamekeep add-game --name "The Witcher 3" --path "C:/Users/You/Documents/The Witcher 3"
+
+    # Create a checkpoint
+    gamekeep checkpoint --game witcher3 --name "Before Boss Fight" --note "Level 25, fire build"
+
+    # List checkpoints
+    gamekeep list --game witcher3
+
+    # Restore a checkpoint
+    gamekeep restore --checkpoint abc12345
+
+    # See which checkpoints branched from which
+    gamekeep tree --game witcher3
+
+    # Record where a game comes from and give it cover art
+    gamekeep edit-game --game witcher3 --platform GOG --cover ~/Pictures/witcher3.jpg
+    gamekeep show-game --game witcher3
+
+    # Checkpoint around every play session (works as a Steam launch option)
+    gamekeep run --game witcher3 -- %%command%%
+
+    # Or leave the watcher running to catch games however they're launched
+    gamekeep edit-game --game witcher3 --exe "C:/Games/The Witcher 3/bin/x64/witcher3.exe"
+    gamekeep watch
+
+    # Checkpoint every 30 minutes while the saves keep changing, and nightly
+    gamekeep schedule add --game witcher3 --every 30m
+    gamekeep schedule add --game witcher3 --cron "0 3 * * *"
+    gamekeep schedule run
+
+    # Pause cloud sync while restoring, and stop if it can't be paused
+    gamekeep hook add --event pre-restore --command "syncctl pause" --timeout 10s
+    gamekeep hook add --event post-restore --command "syncctl resume"
+    gamekeep hook add --game witcher3 --event post-checkpoint --command ./notify.sh
+
+    # Point a game at a new save directory (checkpoints stay attached)
+    gamekeep edit-game --game witcher3 --path "D:/Saves/The Witcher 3"
+
+    # Remove a game, exporting its checkpoints first
+    gamekeep remove-game --game witcher3 --checkpoints export --out witcher3.zip
+
+    # Preview removing a game and its checkpoints for good
+    gamekeep remove-game --game witcher3 --checkpoints delete --permanent --dry-run
+
+    # Move a game to another machine
+    gamekeep export --game witcher3 --out witcher3.zip
+    gamekeep import --file witcher3.zip --path "D:/Saves/The Witcher 3"
+
+    # Rename a checkpoint
+    gamekeep edit-checkpoint --checkpoint abc12345 --name "Before Final Boss"
+
+    # Protect a checkpoint from deletion and cleanup
+    gamekeep edit-checkpoint --checkpoint abc12345 --protected
+
+    # Track level and chapter on checkpoints, then find late-game ones
+    gamekeep edit-game --game witcher3 --field-def level:number --field-def chapter:string
+    gamekeep checkpoint --game witcher3 --name "Act 3" --field level=30 --field chapter=3
+    gamekeep list --game witcher3 --where "level>=25" --sort level --desc
+
+    # See what the save files record; matching custom fields fill in automatically
+    gamekeep inspect --game witcher3
+    gamekeep edit-game --game witcher3 --inspector json
+    gamekeep edit-game --game witcher3 --compression zstd:3
+
+    # Tag a checkpoint, then list only checkpoints with that tag
+    gamekeep tag --checkpoint abc12345 --add boss,pre-dlc
+    gamekeep list --game witcher3 --tag boss
+
+    # Delete a checkpoint (moves it to the trash)
+    gamekeep delete --checkpoint abc12345
+
+    # Undo a delete, or list and empty the trash
+    gamekeep trash restore --checkpoint abc12345
+    gamekeep trash list
+    gamekeep trash empty --expired
+
+    # Show failed operations from the last day
+    gamekeep log --failed --since 24h
+
+    # Keep deleted checkpoints for 7 days
+    gamekeep settings --trash-days 7
+
+    # List all games
+    gamekeep list-games
+
+NOTE: For GUI interface, run 'gamekeep-gui' instead.
+
+For more information, visit: https://github.com/adrielfilipedesign/gamekeep
+`, version)
+}
 
 func main() {
 	// Get home directory
 	homeDir, err := os.UserHomeDir()
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error: failed to get home directory: %v\n", err)
-		os.Exit(1)
+		initFailed(fmt.Errorf("failed to get home directory: %w", err))
 	}
 
 	// Setup directories
@@ -314,26 +1999,56 @@ func main() {
 	// Initialize storage
 	store, err := storage.NewJSONStore(configDir)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error: failed to initialize storage: %v\n", err)
-		os.Exit(1)
+		initFailed(fmt.Errorf("failed to initialize storage: %w", err))
 	}
 
 	// Initialize vault manager
 	vaultMgr, err := vault.NewManager(vaultDir)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error: failed to initialize vault: %v\n", err)
-		os.Exit(1)
+		initFailed(fmt.Errorf("failed to initialize vault: %w", err))
 	}
 
 	// Initialize service
 	service := core.NewService(store, vaultMgr)
 
+	// Fix up game IDs written by older versions
+	if _, err := service.MigrateGameIDs(); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to migrate game IDs: %v\n", err)
+	}
+
+	// Post hooks can't fail the operation, and restores leave out links
+	// pointing outside the saves, so just report them
+	service.Subscribe(func(event models.Event) {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", event.Err)
+	}, models.EventHookFailed, models.EventLinkSkipped)
+
 	// Initialize CLI
 	cli := NewCLI(service)
 
 	// Run
-	if err := cli.Run(os.Args[1:]); err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(1)
+	exitWith(cli.Run(os.Args[1:]))
+}
+
+// initFailed exits after GameKeep itself failed to start. A game launched
+// with the run command still runs, just without its checkpoints.
+func initFailed(err error) {
+	if len(os.Args) > 1 && os.Args[1] == "run" {
+		exitWith(runUntracked(os.Args[2:], err))
+	}
+	fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+	os.Exit(1)
+}
+
+// exitWith exits with the status a command's error calls for
+func exitWith(err error) {
+	if err == nil {
+		os.Exit(0)
+	}
+	var exitErr *exitCodeError
+	if errors.As(err, &exitErr) {
+		os.Exit(exitErr.code)
 	}
+	fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+	printCandidates(err)
+	os.Exit(1)
 }
@@ -0,0 +1,427 @@
+package core
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/adrielfilipedesign/gamekeep/internal/models"
+	"github.com/adrielfilipedesign/gamekeep/internal/vault"
+)
+
+const (
+	// bundleVersion is the export bundle format version written by ExportGame
+	bundleVersion = 1
+
+	// bundleManifestName is the bundle entry holding game and checkpoint metadata
+	bundleManifestName = "manifest.json"
+
+	// bundleCheckpointDir is the bundle directory holding checkpoint archives
+	bundleCheckpointDir = "checkpoints"
+
+	// bundleCoverName is the bundle entry holding the game's cover image,
+	// followed by the image's extension
+	bundleCoverName = "cover"
+)
+
+// bundleManifest describes the contents of an export bundle
+type bundleManifest struct {
+	Version     int                 `json:"version"`
+	ExportedAt  time.Time           `json:"exported_at"`
+	Game        models.Game         `json:"game"`
+	Checkpoints []models.Checkpoint `json:"checkpoints"`
+}
+
+// ExportGame writes a game and all of its checkpoints to a bundle file that
+// ImportBundle can read back, and returns how many checkpoints were exported
+func (s *Service) ExportGame(identifier, destPath string) (count int, err error) {
+	entry := s.newEntry(models.OpExportGame)
+	entry.GameID = identifier
+	entry.Detail = destPath
+	defer func() { s.record(entry, err) }()
+
+	game, err := s.GetGame(identifier)
+	if err != nil {
+		return 0, err
+	}
+	entry.GameID = game.ID
+
+	checkpoints, err := s.ListCheckpoints(game.ID)
+	if err != nil {
+		return 0, err
+	}
+
+	manifest := bundleManifest{
+		Version:     bundleVersion,
+		ExportedAt:  time.Now().UTC(),
+		Game:        *game,
+		Checkpoints: checkpoints,
+	}
+	manifest.Game.Archived = false
+
+	// Write to a temp file so a failed export never leaves a truncated bundle
+	fs := s.vaultMgr.FS()
+	tmpPath := destPath + ".tmp"
+	if err := s.writeBundle(tmpPath, &manifest); err != nil {
+		fs.Remove(tmpPath)
+		return 0, fmt.Errorf("failed to write bundle: %w", err)
+	}
+
+	if err := fs.Rename(tmpPath, destPath); err != nil {
+		fs.Remove(tmpPath)
+		return 0, fmt.Errorf("failed to write bundle: %w", err)
+	}
+
+	return len(checkpoints), nil
+}
+
+// ImportBundle imports a bundle written by ExportGame. Checkpoints are added
+// to the existing game with the same name, or to a newly registered game;
+// savePath overrides the bundle's save path for a new game when not empty.
+// Checkpoints that already exist are skipped.
+func (s *Service) ImportBundle(bundlePath, savePath string) (_ *models.Game, _ int, err error) {
+	entry := s.newEntry(models.OpImportBundle)
+	entry.Detail = bundlePath
+	defer func() { s.record(entry, err) }()
+
+	file, err := s.vaultMgr.FS().Open(bundlePath)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to open bundle: %w", err)
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to open bundle: %w", err)
+	}
+
+	// Bundles come from other people, so they and every checkpoint in
+	// them are held to the archive limits before anything is kept
+	limits := s.archiveLimits()
+	reader, err := vault.OpenArchive(file, info.Size(), limits)
+	if errors.Is(err, models.ErrArchiveLimit) {
+		return nil, 0, err
+	}
+	if err != nil {
+		return nil, 0, fmt.Errorf("%w: %v", models.ErrInvalidBundle, err)
+	}
+
+	manifest, err := readBundleManifest(reader)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	game, isNew, err := s.resolveImportGame(&manifest.Game, savePath)
+	if err != nil {
+		return nil, 0, err
+	}
+	entry.GameID = game.ID
+
+	existing, err := s.store.LoadCheckpoints()
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to load checkpoints: %w", err)
+	}
+
+	known := make(map[string]bool, len(existing))
+	for _, cp := range existing {
+		known[cp.ID] = true
+	}
+
+	// Copy archives into the vault, verifying each against the manifest
+	var added []models.Checkpoint
+	defer func() {
+		if err != nil {
+			for _, cp := range added {
+				s.vaultMgr.DeleteCheckpoint(cp.VaultFile)
+			}
+		}
+	}()
+
+	for i, cp := range manifest.Checkpoints {
+		s.publishProgress(models.OpImportBundle, game.ID, i, len(manifest.Checkpoints), cp.Name)
+		if known[cp.ID] {
+			continue
+		}
+
+		copied, err := s.importBundleCheckpoint(reader, cp, game.ID, limits)
+		if errors.Is(err, models.ErrHashMismatch) {
+			s.publish(models.Event{Type: models.EventVerifyFailed, GameID: game.ID, CheckpointID: cp.ID, Err: err})
+		}
+		if err != nil {
+			return nil, 0, err
+		}
+		added = append(added, *copied)
+		known[cp.ID] = true
+	}
+	s.publishProgress(models.OpImportBundle, game.ID, len(manifest.Checkpoints), len(manifest.Checkpoints), "")
+
+	if isNew {
+		// The cover is a nicety: a bundle with a bad one still imports
+		game.CoverImage = s.importBundleCover(reader, &manifest.Game, game.ID)
+
+		games, err := s.store.LoadGames()
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to load games: %w", err)
+		}
+		if err := s.store.SaveGames(append(games, *game)); err != nil {
+			return nil, 0, fmt.Errorf("failed to save games: %w", err)
+		}
+	}
+
+	if err := s.store.SaveCheckpoints(append(existing, added...)); err != nil {
+		return nil, 0, fmt.Errorf("failed to save checkpoints: %w", err)
+	}
+
+	if isNew {
+		s.publish(models.Event{Type: models.EventGameAdded, GameID: game.ID, Game: game})
+	}
+	for i := range added {
+		s.publish(models.Event{
+			Type:         models.EventCheckpointCreated,
+			GameID:       game.ID,
+			CheckpointID: added[i].ID,
+			Checkpoint:   &added[i],
+		})
+	}
+
+	entry.Detail = fmt.Sprintf("%s (%d checkpoint(s))", bundlePath, len(added))
+	return game, len(added), nil
+}
+
+// writeBundle writes the manifest and checkpoint archives to a new zip file
+func (s *Service) writeBundle(bundlePath string, manifest *bundleManifest) (err error) {
+	file, err := s.vaultMgr.FS().Create(bundlePath)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := file.Close(); err == nil {
+			err = cerr
+		}
+	}()
+
+	archive := zip.NewWriter(file)
+	defer func() {
+		if cerr := archive.Close(); err == nil {
+			err = cerr
+		}
+	}()
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	writer, err := archive.Create(bundleManifestName)
+	if err != nil {
+		return err
+	}
+	if _, err := writer.Write(data); err != nil {
+		return err
+	}
+
+	for i, cp := range manifest.Checkpoints {
+		s.publishProgress(models.OpExportGame, manifest.Game.ID, i, len(manifest.Checkpoints), cp.Name)
+		if err := s.copyCheckpointToBundle(archive, cp); err != nil {
+			return fmt.Errorf("checkpoint %s: %w", cp.ID, err)
+		}
+	}
+	s.publishProgress(models.OpExportGame, manifest.Game.ID, len(manifest.Checkpoints), len(manifest.Checkpoints), "")
+
+	if manifest.Game.CoverImage != "" {
+		if err := s.copyCoverToBundle(archive, manifest.Game.CoverImage); err != nil {
+			return fmt.Errorf("cover image: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// copyCoverToBundle adds a game's cover image to a bundle
+func (s *Service) copyCoverToBundle(archive *zip.Writer, coverFile string) error {
+	src, err := s.vaultMgr.OpenCover(coverFile)
+	if errors.Is(err, os.ErrNotExist) {
+		// The import simply goes without a cover
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	writer, err := archive.Create(bundleCoverName + filepath.Ext(coverFile))
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(writer, src)
+	return err
+}
+
+// importBundleCover copies a bundle's cover image into the vault for game
+// and returns its vault path, or "" if the bundle has no usable cover
+func (s *Service) importBundleCover(reader *zip.Reader, bundled *models.Game, gameID string) string {
+	ext := strings.ToLower(filepath.Ext(bundled.CoverImage))
+	if bundled.CoverImage == "" || !coverExtensions[ext] {
+		return ""
+	}
+
+	src, err := reader.Open(bundleCoverName + ext)
+	if err != nil {
+		return ""
+	}
+	defer src.Close()
+
+	coverFile, err := s.vaultMgr.SaveCover(gameID, ext, src)
+	if err != nil {
+		return ""
+	}
+	return coverFile
+}
+
+// copyCheckpointToBundle adds one checkpoint archive to a bundle, stored
+// uncompressed since it is already a zip
+func (s *Service) copyCheckpointToBundle(archive *zip.Writer, cp models.Checkpoint) error {
+	src, err := s.vaultMgr.OpenCheckpoint(cp.VaultFile)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	writer, err := archive.CreateHeader(&zip.FileHeader{
+		Name:     bundleEntryName(cp.ID),
+		Method:   zip.Store,
+		Modified: cp.CreatedAt,
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(writer, src)
+	return err
+}
+
+// importBundleCheckpoint copies one checkpoint archive from a bundle into the vault,
+// keeping it only if it matches the manifest and is within limits
+func (s *Service) importBundleCheckpoint(reader *zip.Reader, cp models.Checkpoint, gameID string, limits models.ArchiveLimits) (*models.Checkpoint, error) {
+	// IDs become vault file names, so only accept real UUIDs
+	if _, err := uuid.Parse(cp.ID); err != nil {
+		return nil, fmt.Errorf("%w: bad checkpoint ID %q", models.ErrInvalidBundle, cp.ID)
+	}
+
+	archive, err := reader.Open(bundleEntryName(cp.ID))
+	if err != nil {
+		return nil, fmt.Errorf("%w: missing archive for checkpoint %s", models.ErrInvalidBundle, cp.ID)
+	}
+	defer archive.Close()
+
+	vaultFile, hash, err := s.vaultMgr.ImportCheckpoint(gameID, cp.ID, archive)
+	if err != nil {
+		return nil, err
+	}
+
+	if hash != cp.Hash {
+		s.vaultMgr.DeleteCheckpoint(vaultFile)
+		return nil, fmt.Errorf("checkpoint %s: %w", cp.ID, models.ErrHashMismatch)
+	}
+
+	if err := s.vaultMgr.CheckCheckpoint(vaultFile, limits); err != nil {
+		s.vaultMgr.DeleteCheckpoint(vaultFile)
+		return nil, fmt.Errorf("checkpoint %s: %w", cp.ID, err)
+	}
+
+	cp.GameID = gameID
+	cp.VaultFile = vaultFile
+	if err := cp.Validate(); err != nil {
+		s.vaultMgr.DeleteCheckpoint(vaultFile)
+		return nil, err
+	}
+
+	return &cp, nil
+}
+
+// resolveImportGame finds the registered game a bundle belongs to, or
+// prepares a new one. The returned flag reports whether it is new.
+func (s *Service) resolveImportGame(bundled *models.Game, savePath string) (*models.Game, bool, error) {
+	games, err := s.store.LoadGames()
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to load games: %w", err)
+	}
+
+	for _, g := range games {
+		if strings.EqualFold(g.Name, bundled.Name) {
+			if g.Archived {
+				return nil, false, models.ErrGameArchived
+			}
+			return &g, false, nil
+		}
+	}
+
+	// Keep the bundled details; the cover is copied separately. Bundles
+	// come from other people, so nothing that runs commands or points at
+	// programs on this machine is taken from them.
+	game := &models.Game{}
+	*game = *bundled
+	game.Archived = false
+	game.CoverImage = ""
+	game.Hooks = nil
+	game.LaunchCommand = ""
+	game.Executable = ""
+	game.ProcessNames = nil
+	if savePath != "" {
+		game.SavePath = filepath.Clean(savePath)
+	}
+
+	// The bundled ID names the vault directory, so only keep a safe one
+	if !isSafeGameID(game.ID) {
+		game.ID = baseGameID(game.Name)
+	}
+	taken, err := s.takenGameIDs(games)
+	if err != nil {
+		return nil, false, err
+	}
+	game.ID = uniqueGameID(game.ID, taken)
+
+	if err := game.Validate(); err != nil {
+		return nil, false, err
+	}
+
+	return game, true, nil
+}
+
+// readBundleManifest reads and validates a bundle's manifest
+func readBundleManifest(reader *zip.Reader) (*bundleManifest, error) {
+	file, err := reader.Open(bundleManifestName)
+	if err != nil {
+		return nil, fmt.Errorf("%w: missing %s", models.ErrInvalidBundle, bundleManifestName)
+	}
+	defer file.Close()
+
+	var manifest bundleManifest
+	if err := json.NewDecoder(file).Decode(&manifest); err != nil {
+		return nil, fmt.Errorf("%w: %v", models.ErrInvalidBundle, err)
+	}
+
+	if manifest.Version < 1 || manifest.Version > bundleVersion {
+		return nil, fmt.Errorf("%w: unsupported version %d", models.ErrInvalidBundle, manifest.Version)
+	}
+	if manifest.Game.Name == "" {
+		return nil, fmt.Errorf("%w: %v", models.ErrInvalidBundle, models.ErrEmptyGameName)
+	}
+
+	return &manifest, nil
+}
+
+// bundleEntryName returns the bundle entry name for a checkpoint archive
+func bundleEntryName(checkpointID string) string {
+	return path.Join(bundleCheckpointDir, checkpointID+".zip")
+}
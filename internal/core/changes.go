@@ -0,0 +1,139 @@
+package core
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/adrielfilipedesign/gamekeep/internal/fsys"
+	"github.com/adrielfilipedesign/gamekeep/internal/models"
+	"github.com/adrielfilipedesign/gamekeep/internal/vault"
+)
+
+// saveFingerprint summarizes the names, sizes, modes and modification times
+// of the files under a save path, so changes can be spotted without
+// reading file contents
+func saveFingerprint(fs fsys.FS, savePath string) (string, error) {
+	hasher := sha256.New()
+	err := fsys.Walk(fs, savePath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(savePath, path)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(hasher, "%s\x00%d\x00%o\x00%d\n", filepath.ToSlash(rel), info.Size(), info.Mode(), info.ModTime().UnixNano())
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hasher.Sum(nil)), nil
+}
+
+// saveContentHash hashes the names and contents of the files under a save
+// path, and the targets of its symlinks, as archiving them with
+// vault.ArchiveOptions.Contents would
+func saveContentHash(fs fsys.FS, savePath string) (string, error) {
+	contents := vault.NewContentHasher()
+	err := fsys.Walk(fs, savePath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if path == savePath {
+			return nil
+		}
+		rel, err := filepath.Rel(savePath, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+
+		if info.IsDir() {
+			contents.Dir(rel)
+			return nil
+		}
+		if info.Mode()&os.ModeSymlink != 0 {
+			target, err := fs.Readlink(path)
+			if err != nil {
+				return err
+			}
+			contents.Link(rel, target)
+			return nil
+		}
+
+		file, err := fs.Open(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		hasher := sha256.New()
+		size, err := io.Copy(hasher, file)
+		if err != nil {
+			return err
+		}
+		contents.File(rel, size, hasher.Sum(nil))
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return contents.Sum(), nil
+}
+
+// latestCheckpoint returns a game's most recently created checkpoint, or
+// nil if it has none
+func (s *Service) latestCheckpoint(gameID string) (*models.Checkpoint, error) {
+	checkpoints, err := s.store.LoadCheckpoints()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load checkpoints: %w", err)
+	}
+
+	var latest *models.Checkpoint
+	for i := range checkpoints {
+		cp := &checkpoints[i]
+		if cp.GameID == gameID && (latest == nil || cp.CreatedAt.After(latest.CreatedAt)) {
+			latest = cp
+		}
+	}
+	return latest, nil
+}
+
+// readSaveState returns the fingerprint of a game's saves and, unless
+// force is set, a NoChangesError if they match the game's latest
+// checkpoint. Matching sizes and modification times are taken as
+// unchanged straight away; otherwise the contents decide, if the latest
+// checkpoint has a content hash to compare them with. The saves' own
+// content hash comes from archiving them, so they are only read here when
+// that comparison is needed.
+func (s *Service) readSaveState(game *models.Game, force bool) (fingerprint string, err error) {
+	fs := s.vaultMgr.FS()
+
+	// Saves that can't be read leave the fingerprint and hash empty, which
+	// match nothing: they count as changed, and the archiving reports the
+	// problem
+	fingerprint, _ = saveFingerprint(fs, game.SavePath)
+	if force {
+		return fingerprint, nil
+	}
+
+	latest, err := s.latestCheckpoint(game.ID)
+	if err != nil || latest == nil {
+		return fingerprint, err
+	}
+	if fingerprint != "" && fingerprint == latest.SaveFingerprint {
+		return fingerprint, &models.NoChangesError{Checkpoint: *latest}
+	}
+	if latest.ContentHash == "" {
+		return fingerprint, nil
+	}
+
+	if contentHash, _ := saveContentHash(fs, game.SavePath); contentHash != "" && contentHash == latest.ContentHash {
+		return fingerprint, &models.NoChangesError{Checkpoint: *latest}
+	}
+	return fingerprint, nil
+}
@@ -0,0 +1,105 @@
+package core
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/adrielfilipedesign/gamekeep/internal/models"
+)
+
+func TestCreateCheckpointSkipsUnchangedSaves(t *testing.T) {
+	service, fs, game := newTestService(t)
+
+	first, err := service.CreateCheckpoint(game.ID, "First", "")
+	if err != nil {
+		t.Fatalf("CreateCheckpoint: %v", err)
+	}
+	if first.SaveFingerprint == "" || first.ContentHash == "" {
+		t.Fatalf("save state not recorded: %+v", first)
+	}
+
+	journal, _ := service.store.LoadJournal()
+
+	// Untouched saves match by size and modification time
+	_, err = service.CreateCheckpoint(game.ID, "Again", "")
+	var noChanges *models.NoChangesError
+	if !errors.As(err, &noChanges) || !errors.Is(err, models.ErrNoChanges) || noChanges.Checkpoint.ID != first.ID {
+		t.Fatalf("CreateCheckpoint with untouched saves = %v, want NoChangesError for %s", err, first.ID)
+	}
+
+	// Saves written back with the same contents match by content
+	writeSave(t, fs, "/saves/slot1.sav", "level=12")
+	if _, err := service.CreateCheckpoint(game.ID, "Rewritten", ""); !errors.Is(err, models.ErrNoChanges) {
+		t.Fatalf("CreateCheckpoint with rewritten saves = %v, want ErrNoChanges", err)
+	}
+
+	if after, _ := service.store.LoadJournal(); len(after) != len(journal) {
+		t.Fatalf("skipped checkpoints were journaled: %+v", after[len(journal):])
+	}
+
+	if _, err := service.CreateCheckpointWithOptions(game.ID, "Forced", "", models.CheckpointOptions{Force: true}); err != nil {
+		t.Fatalf("forced CreateCheckpoint: %v", err)
+	}
+
+	writeSave(t, fs, "/saves/slot1.sav", "level=13")
+	if _, err := service.CreateCheckpoint(game.ID, "Changed", ""); err != nil {
+		t.Fatalf("CreateCheckpoint with changed saves: %v", err)
+	}
+
+	checkpoints, _ := service.ListCheckpoints(game.ID)
+	if len(checkpoints) != 3 {
+		t.Fatalf("checkpoints = %d, want 3", len(checkpoints))
+	}
+}
+
+func TestSaveContentHashKeepsLinksAsLinks(t *testing.T) {
+	_, fs, game := newTestService(t)
+	writeSave(t, fs, "/shared/settings.ini", "vsync=on")
+	if err := fs.Symlink("/shared/settings.ini", "/saves/settings.ini"); err != nil {
+		t.Fatalf("Symlink: %v", err)
+	}
+
+	before, err := saveContentHash(fs, game.SavePath)
+	if err != nil {
+		t.Fatalf("saveContentHash: %v", err)
+	}
+
+	// Checkpoints don't hold what links point to, so it can't change them
+	writeSave(t, fs, "/shared/settings.ini", "vsync=off")
+	if after, _ := saveContentHash(fs, game.SavePath); after != before {
+		t.Fatal("content hash followed a link out of the saves")
+	}
+
+	fs.Remove("/saves/settings.ini")
+	fs.Symlink("/shared/other.ini", "/saves/settings.ini")
+	if after, _ := saveContentHash(fs, game.SavePath); after == before {
+		t.Fatal("content hash missed a link pointing elsewhere")
+	}
+}
+
+func TestCheckpointContentHashMatchesSaves(t *testing.T) {
+	service, fs, game := newTestService(t)
+	writeSave(t, fs, "/saves/profiles/main.cfg", "difficulty=hard")
+	if err := fs.Symlink("profiles/main.cfg", "/saves/current.cfg"); err != nil {
+		t.Fatalf("Symlink: %v", err)
+	}
+
+	// The hash comes from archiving the saves, so it must be the one
+	// reading them gives
+	cp, err := service.CreateCheckpoint(game.ID, "First", "")
+	if err != nil {
+		t.Fatalf("CreateCheckpoint: %v", err)
+	}
+	want, err := saveContentHash(fs, game.SavePath)
+	if err != nil {
+		t.Fatalf("saveContentHash: %v", err)
+	}
+	if cp.ContentHash != want {
+		t.Fatalf("checkpoint content hash = %s, want %s", cp.ContentHash, want)
+	}
+
+	forced, err := service.CreateCheckpointWithOptions(game.ID, "Forced", "", models.CheckpointOptions{Force: true})
+	if err != nil || forced.ContentHash != want {
+		t.Fatalf("forced checkpoint = %+v, %v; want content hash %s", forced, err, want)
+	}
+}
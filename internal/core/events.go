@@ -0,0 +1,211 @@
+package core
+
+import (
+	"sync"
+	"time"
+
+	"github.com/adrielfilipedesign/gamekeep/internal/models"
+)
+
+// EventBus delivers events to subscribers in this process. Handlers run on
+// the publishing goroutine, in the order they subscribed, so slow work
+// belongs on a goroutine of the handler's own.
+type EventBus struct {
+	mu          sync.Mutex
+	nextID      int
+	subscribers []subscriber
+}
+
+// subscriber is one handler and the event types it wants
+type subscriber struct {
+	id      int
+	types   []models.EventType // Empty means every type
+	handler func(models.Event)
+}
+
+// NewEventBus creates an event bus with no subscribers
+func NewEventBus() *EventBus {
+	return &EventBus{}
+}
+
+// Subscribe calls handler for each event of the given types, or for every
+// event if none are given, and returns a function that unsubscribes it
+func (b *EventBus) Subscribe(handler func(models.Event), types ...models.EventType) (unsubscribe func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.nextID++
+	id := b.nextID
+	b.subscribers = append(b.subscribers, subscriber{id: id, types: types, handler: handler})
+
+	return func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+
+		for i, sub := range b.subscribers {
+			if sub.id == id {
+				b.subscribers = append(b.subscribers[:i:i], b.subscribers[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// Publish delivers an event to its subscribers. Handlers may subscribe,
+// unsubscribe and publish themselves.
+func (b *EventBus) Publish(event models.Event) {
+	b.mu.Lock()
+	subscribers := b.subscribers
+	b.mu.Unlock()
+
+	for _, sub := range subscribers {
+		if sub.wants(event.Type) {
+			sub.handler(event)
+		}
+	}
+}
+
+// wants reports whether the subscriber asked for events of a type
+func (sub *subscriber) wants(eventType models.EventType) bool {
+	if len(sub.types) == 0 {
+		return true
+	}
+	for _, t := range sub.types {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// Subscribe calls handler for the service's events of the given types, or
+// for all of them if none are given, and returns a function that
+// unsubscribes it
+func (s *Service) Subscribe(handler func(models.Event), types ...models.EventType) (unsubscribe func()) {
+	return s.events.Subscribe(handler, types...)
+}
+
+// publish stamps an event with the time and this service's origin and
+// delivers it
+func (s *Service) publish(event models.Event) {
+	if event.At.IsZero() {
+		event.At = time.Now().UTC()
+	}
+	if event.Origin == "" {
+		event.Origin = s.origin
+	}
+	s.events.Publish(event)
+}
+
+// progressFunc returns a ProgressFunc for an operation on a game that
+// publishes each report as a Progress event and passes it on to report, if
+// it is not nil
+func (s *Service) progressFunc(op models.Operation, gameID string, report models.ProgressFunc) models.ProgressFunc {
+	return func(progress models.Progress) {
+		progress.Operation = op
+		s.publish(models.Event{Type: models.EventProgress, GameID: gameID, Progress: &progress})
+		if report != nil {
+			report(progress)
+		}
+	}
+}
+
+// publishProgress reports how many of an operation's items are done
+func (s *Service) publishProgress(op models.Operation, gameID string, done, total int, item string) {
+	s.progressFunc(op, gameID, nil)(models.Progress{
+		Item:       item,
+		FilesDone:  done,
+		FilesTotal: total,
+	})
+}
+
+// appendJournal writes this process's operations to the journal
+func (s *Service) appendJournal(event models.Event) {
+	if event.External || event.Entry == nil {
+		return
+	}
+	s.store.AppendJournal(*event.Entry)
+}
+
+// FollowJournal publishes the operations that other processes, such as the
+// CLI, write to the journal, until stop is closed. It checks every interval
+// and publishes each new entry as an external Operation event, along with
+// the game and checkpoint event it stands for.
+func (s *Service) FollowJournal(stop <-chan struct{}, interval time.Duration) {
+	// The journal is append-only, so whatever was written past where the
+	// last read stopped is new; start from its end
+	_, offset, err := s.store.ReadJournalFrom(-1)
+	if err != nil {
+		offset = -1
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+
+		// A journal that can't be read now is tried again next time. One
+		// that was cleared or replaced starts over from its end.
+		entries, next, err := s.store.ReadJournalFrom(offset)
+		if err != nil {
+			continue
+		}
+		offset = next
+
+		for i := range entries {
+			entry := &entries[i]
+			if entry.Hostname == s.hostname && entry.PID == s.pid {
+				continue
+			}
+			for _, event := range journalEvents(entry) {
+				s.events.Publish(event)
+			}
+		}
+	}
+}
+
+// journalEvents returns the events a journal entry from another process
+// stands for
+func journalEvents(entry *models.JournalEntry) []models.Event {
+	base := models.Event{
+		At:           entry.Timestamp,
+		Origin:       entry.Origin,
+		GameID:       entry.GameID,
+		CheckpointID: entry.CheckpointID,
+		External:     true,
+	}
+
+	operation := base
+	operation.Type = models.EventOperation
+	operation.Entry = entry
+	events := []models.Event{operation}
+
+	if entry.Outcome != models.OutcomeSuccess {
+		return events
+	}
+
+	var eventType models.EventType
+	switch entry.Operation {
+	case models.OpAddGame:
+		eventType = models.EventGameAdded
+	case models.OpCreateCheckpoint:
+		eventType = models.EventCheckpointCreated
+	case models.OpRestoreCheckpoint:
+		eventType = models.EventCheckpointRestored
+	case models.OpDeleteCheckpoint:
+		eventType = models.EventCheckpointDeleted
+	default:
+		return events
+	}
+
+	// Subscribers hear about the change before the operation that made it,
+	// as they do for operations in this process
+	change := base
+	change.Type = eventType
+	return append([]models.Event{change}, events...)
+}
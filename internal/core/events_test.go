@@ -0,0 +1,178 @@
+package core
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/adrielfilipedesign/gamekeep/internal/models"
+)
+
+func TestEventsPublished(t *testing.T) {
+	service, fs, game := newTestService(t)
+
+	var types []models.EventType
+	unsubscribe := service.Subscribe(func(event models.Event) {
+		types = append(types, event.Type)
+	}, models.EventCheckpointCreated, models.EventCheckpointRestored, models.EventCheckpointDeleted, models.EventVerifyFailed)
+
+	var operations []models.Operation
+	service.Subscribe(func(event models.Event) {
+		// The journal is written before other subscribers hear about it
+		entries, _ := service.ListJournal(models.JournalFilter{Limit: 1})
+		if len(entries) == 0 || entries[0].Operation != event.Entry.Operation {
+			t.Errorf("%s published before it was journaled", event.Entry.Operation)
+		}
+		operations = append(operations, event.Entry.Operation)
+	}, models.EventOperation)
+
+	cp, err := service.CreateCheckpoint(game.ID, "First", "")
+	if err != nil {
+		t.Fatalf("CreateCheckpoint: %v", err)
+	}
+	if err := service.RestoreCheckpoint(cp.ID); err != nil {
+		t.Fatalf("RestoreCheckpoint: %v", err)
+	}
+
+	writeSave(t, fs, "/saves/slot1.sav", "level=13")
+	second, err := service.CreateCheckpoint(game.ID, "Second", "")
+	if err != nil {
+		t.Fatalf("CreateCheckpoint: %v", err)
+	}
+	writeSave(t, fs, filepath.Join("/home/.gamekeep/vault", second.VaultFile), "corrupted")
+	if err := service.RestoreCheckpoint(second.ID); err == nil {
+		t.Fatal("RestoreCheckpoint of a corrupted archive succeeded")
+	}
+	if err := service.DeleteCheckpoint(cp.ID, false); err != nil {
+		t.Fatalf("DeleteCheckpoint: %v", err)
+	}
+
+	want := []models.EventType{
+		models.EventCheckpointCreated,
+		models.EventCheckpointRestored,
+		models.EventCheckpointCreated,
+		models.EventVerifyFailed,
+		models.EventCheckpointDeleted,
+	}
+	if fmt.Sprint(types) != fmt.Sprint(want) {
+		t.Fatalf("events = %v, want %v", types, want)
+	}
+	if len(operations) != 5 {
+		t.Fatalf("operation events = %v, want 5", operations)
+	}
+
+	unsubscribe()
+	if _, err := service.AddGame("Other Game", "/other"); err != nil {
+		t.Fatalf("AddGame: %v", err)
+	}
+	if len(types) != len(want) {
+		t.Fatalf("unsubscribed handler still called: %v", types)
+	}
+}
+
+func TestFollowJournal(t *testing.T) {
+	service, _, game := newTestService(t)
+
+	events := make(chan models.Event, 10)
+	service.Subscribe(func(event models.Event) {
+		events <- event
+	}, models.EventCheckpointCreated, models.EventOperation)
+
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		service.FollowJournal(stop, 10*time.Millisecond)
+		close(done)
+	}()
+	defer func() {
+		close(stop)
+		<-done
+	}()
+
+	// Give the follower time to note where the journal ends
+	time.Sleep(30 * time.Millisecond)
+
+	// Another process checkpointing the game
+	service.store.AppendJournal(models.JournalEntry{
+		Timestamp:    time.Now().UTC(),
+		Operation:    models.OpCreateCheckpoint,
+		GameID:       game.ID,
+		CheckpointID: "from-cli",
+		Outcome:      models.OutcomeSuccess,
+		Origin:       models.OriginCLI,
+		Hostname:     service.hostname,
+		PID:          service.pid + 1,
+	})
+
+	for _, want := range []models.EventType{models.EventCheckpointCreated, models.EventOperation} {
+		select {
+		case event := <-events:
+			if event.Type != want || !event.External || event.CheckpointID != "from-cli" {
+				t.Fatalf("event = %+v, want external %s", event, want)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatalf("no %s event for the other process's checkpoint", want)
+		}
+	}
+
+	// Operations in this process aren't published twice
+	if _, err := service.CreateCheckpoint(game.ID, "Local", ""); err != nil {
+		t.Fatalf("CreateCheckpoint: %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		<-events
+	}
+	select {
+	case event := <-events:
+		t.Fatalf("local operation republished: %+v", event)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	if entries, _ := service.ListJournal(models.JournalFilter{}); len(entries) != 3 {
+		t.Fatalf("journal has %d entries, want 3", len(entries))
+	}
+}
+
+func TestReadJournalFrom(t *testing.T) {
+	service, fs, game := newTestService(t)
+	const journalPath = "/home/.gamekeep/config/journal.jsonl"
+
+	_, end, err := service.store.ReadJournalFrom(-1)
+	if err != nil || end == 0 {
+		t.Fatalf("ReadJournalFrom(-1) = %d, %v; want the end of the add-game entry", end, err)
+	}
+
+	entry := models.JournalEntry{Operation: models.OpCreateCheckpoint, GameID: game.ID, Outcome: models.OutcomeSuccess}
+	service.store.AppendJournal(entry)
+	entries, next, err := service.store.ReadJournalFrom(end)
+	if err != nil || len(entries) != 1 || entries[0].Operation != models.OpCreateCheckpoint {
+		t.Fatalf("ReadJournalFrom = %+v, %v; want the new entry", entries, err)
+	}
+
+	// An entry still being written is left for next time
+	data := readSave(t, fs, journalPath)
+	writeSave(t, fs, journalPath, data+`{"operation":"delete-che`)
+	if entries, again, _ := service.store.ReadJournalFrom(next); len(entries) != 0 || again != next {
+		t.Fatalf("partial entry read: %+v, offset %d, want none at %d", entries, again, next)
+	}
+
+	// A journal replaced by a shorter one, or one no longer lining up,
+	// starts over from its end
+	writeSave(t, fs, journalPath, "")
+	service.store.AppendJournal(entry)
+	entries, restart, err := service.store.ReadJournalFrom(next)
+	if err != nil || len(entries) != 0 || restart == 0 || restart >= next {
+		t.Fatalf("truncated journal = %+v, offset %d, %v; want none at its end", entries, restart, err)
+	}
+	writeSave(t, fs, journalPath, strings.Repeat("x", int(next)+10)+"\n")
+	entries, restart, _ = service.store.ReadJournalFrom(next)
+	if len(entries) != 0 {
+		t.Fatalf("replaced journal read from the old offset: %+v", entries)
+	}
+	service.store.AppendJournal(entry)
+	if entries, _, _ := service.store.ReadJournalFrom(restart); len(entries) != 1 {
+		t.Fatalf("entries after a restart = %+v, want 1", entries)
+	}
+}
@@ -0,0 +1,132 @@
+package core
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/adrielfilipedesign/gamekeep/internal/models"
+)
+
+// QueryCheckpoints lists a game's checkpoints that match a query's tag and
+// field filters, in the query's sort order
+func (s *Service) QueryCheckpoints(gameIdentifier string, query models.CheckpointQuery) ([]models.Checkpoint, error) {
+	game, err := s.GetGame(gameIdentifier)
+	if err != nil {
+		return nil, err
+	}
+
+	checkpoints, err := s.ListCheckpoints(game.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Resolve field definitions up front so typos fail loudly
+	defs := make([]*models.FieldDef, len(query.Filters))
+	for i, filter := range query.Filters {
+		if defs[i] = models.FindField(game.Fields, filter.Key); defs[i] == nil {
+			return nil, fmt.Errorf("%w: %s is not defined for this game", models.ErrInvalidField, filter.Key)
+		}
+	}
+
+	less, err := checkpointOrder(game, query.SortBy)
+	if err != nil {
+		return nil, err
+	}
+
+	matched := []models.Checkpoint{}
+	for _, cp := range checkpoints {
+		if query.Tag != "" && !cp.HasTag(query.Tag) {
+			continue
+		}
+
+		ok := true
+		for i := range query.Filters {
+			if !query.Filters[i].Matches(defs[i], &cp) {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			matched = append(matched, cp)
+		}
+	}
+
+	if less != nil {
+		sort.SliceStable(matched, func(i, j int) bool {
+			if query.Descending {
+				return less(&matched[j], &matched[i])
+			}
+			return less(&matched[i], &matched[j])
+		})
+	}
+
+	// Checkpoints without the sort field go last in either direction
+	if def := models.FindField(game.Fields, query.SortBy); def != nil {
+		sort.SliceStable(matched, func(i, j int) bool {
+			_, okA := matched[i].Fields[def.Key]
+			_, okB := matched[j].Fields[def.Key]
+			return okA && !okB
+		})
+	}
+
+	return matched, nil
+}
+
+// checkpointOrder returns the comparison for a sort key, or nil to keep
+// creation order
+func checkpointOrder(game *models.Game, sortBy string) (func(a, b *models.Checkpoint) bool, error) {
+	switch sortBy {
+	case "":
+		return nil, nil
+	case "created":
+		return func(a, b *models.Checkpoint) bool { return a.CreatedAt.Before(b.CreatedAt) }, nil
+	case "name":
+		return func(a, b *models.Checkpoint) bool { return strings.ToLower(a.Name) < strings.ToLower(b.Name) }, nil
+	}
+
+	def := models.FindField(game.Fields, sortBy)
+	if def == nil {
+		return nil, fmt.Errorf("%w: cannot sort by %s: not defined for this game", models.ErrInvalidField, sortBy)
+	}
+
+	return func(a, b *models.Checkpoint) bool {
+		return def.Compare(a.Fields[def.Key], b.Fields[def.Key]) < 0
+	}, nil
+}
+
+// mergeFields applies field changes to a copy of a checkpoint's values; an
+// empty value removes the field
+func (s *Service) mergeFields(checkpoint *models.Checkpoint, changes map[string]string) (map[string]string, error) {
+	game, err := s.GetGame(checkpoint.GameID)
+	if err != nil {
+		return nil, err
+	}
+
+	merged := make(map[string]string, len(checkpoint.Fields)+len(changes))
+	for key, value := range checkpoint.Fields {
+		merged[key] = value
+	}
+
+	for key, value := range changes {
+		if strings.TrimSpace(value) == "" {
+			delete(merged, key)
+			continue
+		}
+
+		def := models.FindField(game.Fields, key)
+		if def == nil {
+			return nil, fmt.Errorf("%w: %s is not defined for this game", models.ErrInvalidField, key)
+		}
+		normalized, err := def.Normalize(value)
+		if err != nil {
+			return nil, err
+		}
+		merged[key] = normalized
+	}
+
+	if len(merged) == 0 {
+		return nil, nil
+	}
+	return merged, nil
+}
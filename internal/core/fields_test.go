@@ -0,0 +1,78 @@
+package core
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/adrielfilipedesign/gamekeep/internal/models"
+)
+
+func TestCheckpointFields(t *testing.T) {
+	service, _, game := newTestService(t)
+
+	// The test save has a level the INI inspector would fill in
+	defs := []models.FieldDef{{Key: "level", Type: models.FieldNumber}, {Key: "chapter", Type: models.FieldString}}
+	off := models.InspectorNone
+	if _, err := service.UpdateGame(game.ID, models.GameUpdate{Fields: &defs, Inspector: &off}); err != nil {
+		t.Fatalf("UpdateGame: %v", err)
+	}
+
+	create := func(name string, fields map[string]string) *models.Checkpoint {
+		t.Helper()
+		cp, err := service.CreateCheckpointWithOptions(game.ID, name, "", models.CheckpointOptions{Fields: fields, Force: true})
+		if err != nil {
+			t.Fatalf("CreateCheckpointWithOptions(%s): %v", name, err)
+		}
+		return cp
+	}
+
+	low := create("Low", map[string]string{"level": "9.0", "chapter": "1"})
+	if low.Fields["level"] != "9" {
+		t.Fatalf("level = %q, want normalized 9", low.Fields["level"])
+	}
+	high := create("High", map[string]string{"level": "12"})
+	none := create("None", nil)
+
+	opts := models.CheckpointOptions{Fields: map[string]string{"level": "ten"}}
+	if _, err := service.CreateCheckpointWithOptions(game.ID, "Bad", "", opts); !errors.Is(err, models.ErrInvalidField) {
+		t.Fatalf("expected ErrInvalidField for bad number, got %v", err)
+	}
+	opts = models.CheckpointOptions{Fields: map[string]string{"boss": "x"}}
+	if _, err := service.CreateCheckpointWithOptions(game.ID, "Bad", "", opts); !errors.Is(err, models.ErrInvalidField) {
+		t.Fatalf("expected ErrInvalidField for unknown key, got %v", err)
+	}
+
+	// Numbers compare numerically, so 9 < 12
+	filter, _ := models.ParseFieldFilter("level>=10")
+	matched, err := service.QueryCheckpoints(game.ID, models.CheckpointQuery{Filters: []models.FieldFilter{filter}})
+	if err != nil || len(matched) != 1 || matched[0].ID != high.ID {
+		t.Fatalf("level>=10 = %+v, %v", matched, err)
+	}
+
+	for _, desc := range []bool{false, true} {
+		sorted, err := service.QueryCheckpoints(game.ID, models.CheckpointQuery{SortBy: "level", Descending: desc})
+		if err != nil || len(sorted) != 3 {
+			t.Fatalf("sort by level: %+v, %v", sorted, err)
+		}
+		first, second := low.ID, high.ID
+		if desc {
+			first, second = high.ID, low.ID
+		}
+		if sorted[0].ID != first || sorted[1].ID != second || sorted[2].ID != none.ID {
+			t.Fatalf("sort by level (desc=%v) = %s, %s, %s", desc, sorted[0].Name, sorted[1].Name, sorted[2].Name)
+		}
+	}
+
+	bad := models.FieldFilter{Key: "boss", Op: models.FieldEq, Value: "x"}
+	if _, err := service.QueryCheckpoints(game.ID, models.CheckpointQuery{Filters: []models.FieldFilter{bad}}); !errors.Is(err, models.ErrInvalidField) {
+		t.Fatalf("expected ErrInvalidField for unknown filter key, got %v", err)
+	}
+
+	updated, err := service.UpdateCheckpoint(low.ID, models.CheckpointUpdate{Fields: map[string]string{"chapter": ""}})
+	if err != nil {
+		t.Fatalf("UpdateCheckpoint: %v", err)
+	}
+	if _, ok := updated.Fields["chapter"]; ok || updated.Fields["level"] != "9" {
+		t.Fatalf("fields after clearing chapter = %v", updated.Fields)
+	}
+}
@@ -0,0 +1,228 @@
+package core
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+
+	"github.com/adrielfilipedesign/gamekeep/internal/models"
+)
+
+const (
+	// maxGameIDLength limits game IDs, which also name vault directories
+	maxGameIDLength = 50
+
+	// gameIDHashLength is the number of hex digits of the name hash used
+	// for names that cannot be fully transliterated
+	gameIDHashLength = 8
+)
+
+// transliterations covers letters that don't decompose into a base letter
+// plus combining marks under NFKD
+var transliterations = map[rune]string{
+	'ß': "ss", 'æ': "ae", 'œ': "oe", 'ø': "o", 'ł': "l", 'đ': "d",
+	'ð': "d", 'þ': "th", 'ı': "i", 'ħ': "h", 'ŧ': "t", 'ŋ': "ng",
+}
+
+// baseGameID derives the preferred ID for a game name. Accented Latin
+// letters are transliterated; if anything else remains (e.g. CJK), a short
+// hash of the name is appended so different names still get different IDs.
+// The result is stable for a given name.
+func baseGameID(name string) string {
+	var b strings.Builder
+	lossy := false
+	for _, r := range norm.NFKD.String(strings.ToLower(name)) {
+		switch {
+		case (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9'):
+			b.WriteRune(r)
+		case unicode.Is(unicode.Mn, r):
+			// Drop combining marks left over from decomposition
+		case transliterations[r] != "":
+			b.WriteString(transliterations[r])
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			lossy = true
+			b.WriteRune('_')
+		default:
+			b.WriteRune('_')
+		}
+	}
+
+	id := collapseUnderscores(b.String())
+
+	if !lossy && id != "" {
+		return truncateID(id, maxGameIDLength)
+	}
+
+	sum := sha256.Sum256([]byte(name))
+	hash := hex.EncodeToString(sum[:])[:gameIDHashLength]
+	if id == "" {
+		return "game_" + hash
+	}
+	return truncateID(id, maxGameIDLength-len(hash)-1) + "_" + hash
+}
+
+// uniqueGameID returns base, or base with a numeric suffix if base is taken
+func uniqueGameID(base string, taken map[string]bool) string {
+	if !taken[base] {
+		return base
+	}
+	for n := 2; ; n++ {
+		suffix := fmt.Sprintf("_%d", n)
+		id := truncateID(base, maxGameIDLength-len(suffix)) + suffix
+		if !taken[id] {
+			return id
+		}
+	}
+}
+
+// isSafeGameID reports whether id could have been generated by baseGameID
+// or uniqueGameID, so it is safe to use as a vault directory name
+func isSafeGameID(id string) bool {
+	if id == "" || len(id) > maxGameIDLength {
+		return false
+	}
+	for _, r := range id {
+		if !((r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '_') {
+			return false
+		}
+	}
+	return id == collapseUnderscores(id)
+}
+
+// collapseUnderscores merges runs of underscores and trims them from the edges
+func collapseUnderscores(id string) string {
+	for strings.Contains(id, "__") {
+		id = strings.ReplaceAll(id, "__", "_")
+	}
+	return strings.Trim(id, "_")
+}
+
+// truncateID shortens an ID to at most n bytes without a trailing underscore
+func truncateID(id string, n int) string {
+	if len(id) > n {
+		id = strings.TrimRight(id[:n], "_")
+	}
+	return id
+}
+
+// takenGameIDs returns the IDs in use by registered games and by games
+// waiting in the trash to be registered again
+func (s *Service) takenGameIDs(games []models.Game) (map[string]bool, error) {
+	trash, err := s.store.LoadTrash()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load trash: %w", err)
+	}
+
+	taken := make(map[string]bool, len(games))
+	for _, g := range games {
+		taken[g.ID] = true
+	}
+	for _, entry := range trash {
+		if entry.Game != nil {
+			taken[entry.Game.ID] = true
+		}
+	}
+	return taken, nil
+}
+
+// MigrateGameIDs gives a new unique ID to every game whose ID is empty or
+// shared with an earlier game, and returns how many games were changed.
+// Checkpoints with an empty game ID follow the first such game to its new
+// ID; with a duplicated ID the first game keeps the ID and its checkpoints,
+// since there is no way to tell whose they were.
+func (s *Service) MigrateGameIDs() (count int, err error) {
+	games, err := s.store.LoadGames()
+	if err != nil {
+		return 0, fmt.Errorf("failed to load games: %w", err)
+	}
+
+	taken, err := s.takenGameIDs(games)
+	if err != nil {
+		return 0, err
+	}
+
+	seen := make(map[string]bool, len(games))
+	var migrated []string
+	emptyID := ""
+	for i := range games {
+		game := &games[i]
+		if game.ID != "" && !seen[game.ID] {
+			seen[game.ID] = true
+			continue
+		}
+
+		oldID := game.ID
+		game.ID = uniqueGameID(baseGameID(game.Name), taken)
+		taken[game.ID] = true
+		seen[game.ID] = true
+		if oldID == "" && emptyID == "" {
+			emptyID = game.ID
+		}
+		migrated = append(migrated, fmt.Sprintf("%q -> %s", oldID, game.ID))
+	}
+
+	if len(migrated) == 0 {
+		return 0, nil
+	}
+
+	entry := s.newEntry(models.OpMigrateGameIDs)
+	entry.Detail = strings.Join(migrated, ", ")
+	defer func() { s.record(entry, err) }()
+
+	// Checkpoints go first: if saving the games then fails, the next run
+	// picks the same new ID, so the checkpoints still end up attached
+	if emptyID != "" {
+		if err := s.reassignCheckpoints("", emptyID); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := s.store.SaveGames(games); err != nil {
+		return 0, fmt.Errorf("failed to save games: %w", err)
+	}
+
+	return len(migrated), nil
+}
+
+// reassignCheckpoints moves live and trashed checkpoints from one game ID to
+// another. Archives stay where they are, since VaultFile records their path.
+func (s *Service) reassignCheckpoints(fromID, toID string) error {
+	checkpoints, err := s.store.LoadCheckpoints()
+	if err != nil {
+		return fmt.Errorf("failed to load checkpoints: %w", err)
+	}
+
+	for i := range checkpoints {
+		if checkpoints[i].GameID == fromID {
+			checkpoints[i].GameID = toID
+		}
+	}
+
+	if err := s.store.SaveCheckpoints(checkpoints); err != nil {
+		return fmt.Errorf("failed to save checkpoints: %w", err)
+	}
+
+	entries, err := s.store.LoadTrash()
+	if err != nil {
+		return fmt.Errorf("failed to load trash: %w", err)
+	}
+
+	for i := range entries {
+		if entries[i].Checkpoint.GameID == fromID {
+			entries[i].Checkpoint.GameID = toID
+		}
+		if entries[i].Game != nil && entries[i].Game.ID == fromID {
+			entries[i].Game.ID = toID
+		}
+	}
+
+	if err := s.store.SaveTrash(entries); err != nil {
+		return fmt.Errorf("failed to save trash: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,120 @@
+package core
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/adrielfilipedesign/gamekeep/internal/models"
+)
+
+func TestBaseGameID(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"The Witcher 3", "the_witcher_3"},
+		{"Half-Life 2", "half_life_2"},
+		{"Ōkami", "okami"},
+		{"Pokémon Café", "pokemon_cafe"},
+		{"Straße", "strasse"},
+		{"  --  ", "game_"},
+		{"ファイナルファンタジー", "game_"},
+		{"ファイナルファンタジー 7", "7_"},
+	}
+
+	for _, tt := range tests {
+		got := baseGameID(tt.name)
+		if strings.HasSuffix(tt.want, "_") {
+			if !strings.HasPrefix(got, tt.want) || len(got) != len(tt.want)+gameIDHashLength {
+				t.Errorf("baseGameID(%q) = %q, want %q followed by a hash", tt.name, got, tt.want)
+			}
+		} else if got != tt.want {
+			t.Errorf("baseGameID(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+		if !isSafeGameID(got) {
+			t.Errorf("baseGameID(%q) = %q is not a safe ID", tt.name, got)
+		}
+		if again := baseGameID(tt.name); again != got {
+			t.Errorf("baseGameID(%q) is not stable: %q then %q", tt.name, got, again)
+		}
+	}
+
+	if baseGameID("ファイナルファンタジー") == baseGameID("ドラゴンクエスト") {
+		t.Error("different non-Latin names produced the same ID")
+	}
+	if got := baseGameID(strings.Repeat("long name ", 20)); len(got) > maxGameIDLength {
+		t.Errorf("ID longer than %d bytes: %q", maxGameIDLength, got)
+	}
+}
+
+func TestAddGameSuffixesCollidingIDs(t *testing.T) {
+	service, _, _ := newTestService(t)
+
+	first, err := service.AddGame("Half-Life 2", "/hl2")
+	if err != nil {
+		t.Fatalf("AddGame: %v", err)
+	}
+	second, err := service.AddGame("Half Life 2", "/hl2-other")
+	if err != nil {
+		t.Fatalf("AddGame: %v", err)
+	}
+
+	if first.ID != "half_life_2" || second.ID != "half_life_2_2" {
+		t.Fatalf("IDs = %q, %q; want half_life_2, half_life_2_2", first.ID, second.ID)
+	}
+}
+
+func TestMigrateGameIDs(t *testing.T) {
+	service, _, game := newTestService(t)
+
+	cp, err := service.CreateCheckpoint(game.ID, "One", "")
+	if err != nil {
+		t.Fatalf("CreateCheckpoint: %v", err)
+	}
+
+	// Simulate metadata written by older versions
+	games, _ := service.store.LoadGames()
+	games = append(games,
+		models.Game{ID: "", Name: "ファイナルファンタジー", SavePath: "/ff"},
+		models.Game{ID: game.ID, Name: "Test-Game", SavePath: "/dup"},
+	)
+	service.store.SaveGames(games)
+
+	checkpoints, _ := service.store.LoadCheckpoints()
+	orphan := checkpoints[0]
+	orphan.ID = "00000000-0000-0000-0000-000000000001"
+	orphan.GameID = ""
+	service.store.SaveCheckpoints(append(checkpoints, orphan))
+
+	count, err := service.MigrateGameIDs()
+	if err != nil {
+		t.Fatalf("MigrateGameIDs: %v", err)
+	}
+	if count != 2 {
+		t.Fatalf("migrated %d games, want 2", count)
+	}
+
+	games, _ = service.store.LoadGames()
+	seen := map[string]bool{}
+	for _, g := range games {
+		if !isSafeGameID(g.ID) || seen[g.ID] {
+			t.Fatalf("bad or duplicate ID after migration: %+v", games)
+		}
+		seen[g.ID] = true
+	}
+	if games[0].ID != game.ID {
+		t.Errorf("first game lost its ID: %q", games[0].ID)
+	}
+
+	// The original game keeps its checkpoint, the orphan follows the renamed game
+	if got, _ := service.GetCheckpoint(cp.ID); got.GameID != game.ID {
+		t.Errorf("checkpoint moved to %q", got.GameID)
+	}
+	if got, _ := service.GetCheckpoint(orphan.ID); got.GameID != games[1].ID {
+		t.Errorf("orphaned checkpoint game = %q, want %q", got.GameID, games[1].ID)
+	}
+
+	if count, err := service.MigrateGameIDs(); err != nil || count != 0 {
+		t.Fatalf("second migration = %d, %v; want 0, nil", count, err)
+	}
+}
@@ -0,0 +1,292 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/adrielfilipedesign/gamekeep/internal/models"
+)
+
+// ListArchivedGames returns games that were removed with their checkpoints kept
+func (s *Service) ListArchivedGames() ([]models.Game, error) {
+	games, err := s.store.LoadGames()
+	if err != nil {
+		return nil, err
+	}
+
+	archived := []models.Game{}
+	for _, g := range games {
+		if g.Archived {
+			archived = append(archived, g)
+		}
+	}
+	return archived, nil
+}
+
+// PlanRemoveGame reports what RemoveGame would affect without changing anything
+func (s *Service) PlanRemoveGame(identifier string, opts models.RemoveGameOptions) (*models.RemoveGameSummary, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+
+	game, err := s.GetGame(identifier)
+	if err != nil {
+		return nil, err
+	}
+
+	checkpoints, err := s.ListCheckpoints(game.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.summarizeRemoval(game, checkpoints, opts), nil
+}
+
+// RemoveGame removes a game, handling its checkpoints according to opts.Mode:
+// RemoveKeep archives the game and keeps its checkpoints, RemoveDelete moves
+// them to the trash (or deletes them with opts.Permanent) and removes the
+// game's vault directory, and RemoveExport writes them to a bundle first.
+// Deleting protected checkpoints requires opts.Force.
+func (s *Service) RemoveGame(identifier string, opts models.RemoveGameOptions) (_ *models.RemoveGameSummary, err error) {
+	entry := s.newEntry(models.OpRemoveGame)
+	entry.GameID = identifier
+	entry.Detail = string(opts.Mode)
+	defer func() { s.record(entry, err) }()
+
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+
+	game, err := s.GetGame(identifier)
+	if err != nil {
+		return nil, err
+	}
+	entry.GameID = game.ID
+
+	checkpoints, err := s.ListCheckpoints(game.ID)
+	if err != nil {
+		return nil, err
+	}
+	summary := s.summarizeRemoval(game, checkpoints, opts)
+
+	if opts.Mode == models.RemoveKeep {
+		if err := s.archiveGame(game.ID); err != nil {
+			return nil, err
+		}
+		return summary, nil
+	}
+
+	if summary.ProtectedCount > 0 && !opts.Force {
+		return nil, fmt.Errorf("%w: %d checkpoint(s) of %s", models.ErrCheckpointProtected, summary.ProtectedCount, game.Name)
+	}
+
+	if opts.Mode == models.RemoveExport {
+		if _, err := s.ExportGame(game.ID, opts.ExportPath); err != nil {
+			return nil, err
+		}
+	}
+
+	trashed, err := s.deleteGameCheckpoints(game, checkpoints, opts.Permanent)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unregister the game
+	games, err := s.store.LoadGames()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load games: %w", err)
+	}
+
+	remaining := []models.Game{}
+	for _, g := range games {
+		if g.ID != game.ID {
+			remaining = append(remaining, g)
+		}
+	}
+
+	if err := s.store.SaveGames(remaining); err != nil {
+		return nil, fmt.Errorf("failed to save games: %w", err)
+	}
+
+	// Anything still in the game's vault directory is unreferenced now
+	if err := s.vaultMgr.RemoveGameDir(game.ID); err != nil {
+		return nil, fmt.Errorf("failed to remove game vault directory: %w", err)
+	}
+
+	// Trash entries keep the game record, cover included, so that restoring
+	// a checkpoint can register the game again; otherwise nothing needs it.
+	// Best effort, like any other stale cover.
+	if game.CoverImage != "" && trashed == 0 {
+		s.vaultMgr.DeleteCover(game.CoverImage)
+	}
+
+	// Best effort: a leftover schedule only fails to find its game
+	s.removeGameSchedules(game.ID)
+
+	return summary, nil
+}
+
+// archiveGame hides a game from the game list, keeping its checkpoints.
+// RemoveGame journals it, so it doesn't go through UpdateGame.
+func (s *Service) archiveGame(gameID string) error {
+	games, err := s.store.LoadGames()
+	if err != nil {
+		return fmt.Errorf("failed to load games: %w", err)
+	}
+	for i := range games {
+		if games[i].ID == gameID {
+			games[i].Archived = true
+		}
+	}
+	if err := s.store.SaveGames(games); err != nil {
+		return fmt.Errorf("failed to save games: %w", err)
+	}
+	return nil
+}
+
+// gameExistsError reports that g already has a name. Archived games aren't
+// listed, so the error says which one is in the way.
+func gameExistsError(g *models.Game) error {
+	if g.Archived {
+		return fmt.Errorf("%w: %q is archived; unarchive or remove it first", models.ErrGameExists, g.Name)
+	}
+	return models.ErrGameExists
+}
+
+// deleteGameCheckpoints trashes or permanently deletes all of a game's
+// checkpoints, and returns how many went to the trash
+func (s *Service) deleteGameCheckpoints(game *models.Game, checkpoints []models.Checkpoint, permanent bool) (trashed int, err error) {
+	// Skip archives that are already missing; there is nothing to keep
+	var present []models.Checkpoint
+	for _, cp := range checkpoints {
+		if _, err := s.vaultMgr.CheckpointSize(cp.VaultFile); err == nil {
+			present = append(present, cp)
+		}
+	}
+
+	if !permanent {
+		if err := s.moveToTrash(present, game); err != nil {
+			return 0, err
+		}
+	}
+
+	allCheckpoints, err := s.store.LoadCheckpoints()
+	if err != nil {
+		if !permanent {
+			s.undoMoveToTrash(present)
+		}
+		return 0, fmt.Errorf("failed to load checkpoints: %w", err)
+	}
+
+	remaining := []models.Checkpoint{}
+	for _, cp := range allCheckpoints {
+		if cp.GameID != game.ID {
+			remaining = append(remaining, cp)
+		}
+	}
+
+	if err := s.store.SaveCheckpoints(remaining); err != nil {
+		if !permanent {
+			s.undoMoveToTrash(present)
+		}
+		return 0, fmt.Errorf("failed to save checkpoints: %w", err)
+	}
+
+	for i := range checkpoints {
+		s.publish(models.Event{
+			Type:         models.EventCheckpointDeleted,
+			GameID:       game.ID,
+			CheckpointID: checkpoints[i].ID,
+			Checkpoint:   &checkpoints[i],
+		})
+	}
+
+	if permanent {
+		for _, cp := range present {
+			if err := s.vaultMgr.DeleteCheckpoint(cp.VaultFile); err != nil && !errors.Is(err, os.ErrNotExist) {
+				return 0, fmt.Errorf("failed to delete checkpoint file: %w", err)
+			}
+		}
+		return 0, nil
+	}
+
+	return len(present), nil
+}
+
+// summarizeRemoval computes the space affected by removing a game
+func (s *Service) summarizeRemoval(game *models.Game, checkpoints []models.Checkpoint, opts models.RemoveGameOptions) *models.RemoveGameSummary {
+	summary := &models.RemoveGameSummary{
+		Game:            *game,
+		CheckpointCount: len(checkpoints),
+	}
+
+	for _, cp := range checkpoints {
+		if size, err := s.vaultMgr.CheckpointSize(cp.VaultFile); err == nil {
+			summary.VaultBytes += size
+		}
+		if cp.Protected {
+			summary.ProtectedCount++
+		}
+	}
+
+	// Trashed checkpoints only free space once the trash is emptied
+	if opts.Mode != models.RemoveKeep && opts.Permanent {
+		summary.FreedBytes = summary.VaultBytes
+	}
+
+	return summary
+}
+
+// coverExtensions lists the image types accepted as cover art
+var coverExtensions = map[string]bool{
+	".png": true, ".jpg": true, ".jpeg": true, ".gif": true, ".webp": true,
+}
+
+// copyCover copies an image into the vault as a game's cover and returns
+// its vault path
+func (s *Service) copyCover(gameID, imagePath string) (string, error) {
+	ext := strings.ToLower(filepath.Ext(imagePath))
+	if !coverExtensions[ext] {
+		return "", models.ErrInvalidCover
+	}
+
+	src, err := s.vaultMgr.FS().Open(imagePath)
+	if err != nil {
+		return "", fmt.Errorf("failed to open cover image: %w", err)
+	}
+	defer src.Close()
+
+	return s.vaultMgr.SaveCover(gameID, ext, src)
+}
+
+// CoverPath returns the full path of a game's cover image, or "" if the
+// game has none
+func (s *Service) CoverPath(game *models.Game) string {
+	if game.CoverImage == "" {
+		return ""
+	}
+	return s.vaultMgr.CoverPath(game.CoverImage)
+}
+
+// GameStats summarizes a game's checkpoints. Archives missing from the
+// vault count toward the checkpoint total but not the size.
+func (s *Service) GameStats(gameIdentifier string) (*models.GameStats, error) {
+	checkpoints, err := s.ListCheckpoints(gameIdentifier)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &models.GameStats{CheckpointCount: len(checkpoints)}
+	for _, cp := range checkpoints {
+		if size, err := s.vaultMgr.CheckpointSize(cp.VaultFile); err == nil {
+			stats.TotalSize += size
+		}
+		if cp.CreatedAt.After(stats.LastCheckpointAt) {
+			stats.LastCheckpointAt = cp.CreatedAt
+		}
+	}
+	return stats, nil
+}
@@ -0,0 +1,328 @@
+package core
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/adrielfilipedesign/gamekeep/internal/fsys"
+	"github.com/adrielfilipedesign/gamekeep/internal/models"
+)
+
+func TestRemoveGameKeepArchives(t *testing.T) {
+	service, _, game := newTestService(t)
+
+	if _, err := service.CreateCheckpoint(game.ID, "One", ""); err != nil {
+		t.Fatalf("CreateCheckpoint: %v", err)
+	}
+
+	summary, err := service.RemoveGame(game.ID, models.RemoveGameOptions{Mode: models.RemoveKeep})
+	if err != nil {
+		t.Fatalf("RemoveGame: %v", err)
+	}
+	if summary.CheckpointCount != 1 || summary.VaultBytes == 0 || summary.FreedBytes != 0 {
+		t.Fatalf("unexpected summary: %+v", summary)
+	}
+
+	games, _ := service.ListGames()
+	if len(games) != 0 {
+		t.Fatalf("archived game still listed: %+v", games)
+	}
+
+	checkpoints, err := service.ListCheckpoints(game.ID)
+	if err != nil || len(checkpoints) != 1 {
+		t.Fatalf("archived checkpoints = %+v, %v", checkpoints, err)
+	}
+	if _, err := service.CreateCheckpoint(game.ID, "Two", ""); !errors.Is(err, models.ErrGameArchived) {
+		t.Fatalf("expected ErrGameArchived, got %v", err)
+	}
+
+	// Archiving is part of the removal, not an edit of its own
+	for op, want := range map[models.Operation]int{models.OpRemoveGame: 1, models.OpUpdateGame: 0} {
+		if entries, _ := service.ListJournal(models.JournalFilter{Operation: op}); len(entries) != want {
+			t.Fatalf("journal has %d %s entries, want %d", len(entries), op, want)
+		}
+	}
+
+	// The hidden game still holds its name, and the error says so
+	_, err = service.AddGame("test game", "/other")
+	if !errors.Is(err, models.ErrGameExists) || !strings.Contains(err.Error(), `"Test Game" is archived`) {
+		t.Fatalf("AddGame over an archived game: %v", err)
+	}
+}
+
+func TestRemoveGameDeleteMovesToTrash(t *testing.T) {
+	service, fs, game := newTestService(t)
+
+	cp, err := service.CreateCheckpoint(game.ID, "One", "")
+	if err != nil {
+		t.Fatalf("CreateCheckpoint: %v", err)
+	}
+
+	if _, err := service.RemoveGame(game.ID, models.RemoveGameOptions{Mode: models.RemoveDelete}); err != nil {
+		t.Fatalf("RemoveGame: %v", err)
+	}
+
+	if _, err := service.GetGame(game.ID); !errors.Is(err, models.ErrGameNotFound) {
+		t.Fatalf("game still registered: %v", err)
+	}
+	if _, err := fs.Stat("/home/.gamekeep/vault/" + game.ID); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("vault directory still exists: %v", err)
+	}
+
+	// Restoring the checkpoint from the trash brings the game back
+	if _, err := service.RestoreFromTrash(cp.ID); err != nil {
+		t.Fatalf("RestoreFromTrash: %v", err)
+	}
+	restored, err := service.GetGame(game.ID)
+	if err != nil || restored.Name != game.Name {
+		t.Fatalf("game not re-registered: %+v, %v", restored, err)
+	}
+	if err := service.RestoreCheckpoint(cp.ID); err != nil {
+		t.Fatalf("RestoreCheckpoint: %v", err)
+	}
+}
+
+func TestRemoveGameExportThenImport(t *testing.T) {
+	service, fs, game := newTestService(t)
+
+	cp, err := service.CreateCheckpoint(game.ID, "One", "keep me")
+	if err != nil {
+		t.Fatalf("CreateCheckpoint: %v", err)
+	}
+
+	opts := models.RemoveGameOptions{
+		Mode:       models.RemoveExport,
+		ExportPath: "/backup.zip",
+		Permanent:  true,
+	}
+	summary, err := service.RemoveGame(game.ID, opts)
+	if err != nil {
+		t.Fatalf("RemoveGame: %v", err)
+	}
+	if summary.FreedBytes != summary.VaultBytes || summary.FreedBytes == 0 {
+		t.Fatalf("unexpected summary: %+v", summary)
+	}
+	if entries, _ := service.ListTrash(); len(entries) != 0 {
+		t.Fatalf("permanent removal left trash entries: %+v", entries)
+	}
+
+	imported, count, err := service.ImportBundle("/backup.zip", "/restored-saves")
+	if err != nil {
+		t.Fatalf("ImportBundle: %v", err)
+	}
+	if count != 1 || imported.Name != game.Name || imported.SavePath != "/restored-saves" {
+		t.Fatalf("unexpected import: %+v, %d", imported, count)
+	}
+
+	got, err := service.GetCheckpoint(cp.ID)
+	if err != nil || got.Note != "keep me" {
+		t.Fatalf("imported checkpoint = %+v, %v", got, err)
+	}
+	if err := service.RestoreCheckpoint(cp.ID); err != nil {
+		t.Fatalf("RestoreCheckpoint: %v", err)
+	}
+	if got := readSave(t, fs, "/restored-saves/slot1.sav"); got != "level=12" {
+		t.Errorf("restored save = %q, want %q", got, "level=12")
+	}
+
+	// Importing again skips existing checkpoints
+	if _, count, err := service.ImportBundle("/backup.zip", ""); err != nil || count != 0 {
+		t.Fatalf("re-import = %d, %v; want 0, nil", count, err)
+	}
+}
+
+func TestArchiveLimitsGuardRestoreAndImport(t *testing.T) {
+	service, fs, game := newTestService(t)
+
+	// A save that compresses far better than the limit allows
+	writeSave(t, fs, "/saves/world.dat", strings.Repeat("\x00", 2<<20))
+	cp, err := service.CreateCheckpoint(game.ID, "Bomb", "")
+	if err != nil {
+		t.Fatalf("CreateCheckpoint: %v", err)
+	}
+	if _, err := service.ExportGame(game.ID, "/bundle.zip"); err != nil {
+		t.Fatalf("ExportGame: %v", err)
+	}
+
+	settings, err := service.GetSettings()
+	if err != nil {
+		t.Fatalf("GetSettings: %v", err)
+	}
+	settings.ArchiveLimits.MaxRatio = 100
+	if err := service.UpdateSettings(settings); err != nil {
+		t.Fatalf("UpdateSettings: %v", err)
+	}
+
+	writeSave(t, fs, "/saves/slot1.sav", "level=13")
+	if err := service.RestoreCheckpoint(cp.ID); !errors.Is(err, models.ErrArchiveLimit) {
+		t.Fatalf("RestoreCheckpoint = %v, want ErrArchiveLimit", err)
+	}
+	if got := readSave(t, fs, "/saves/slot1.sav"); got != "level=13" {
+		t.Fatalf("refused restore changed the saves to %q", got)
+	}
+
+	if _, err := service.RemoveGame(game.ID, models.RemoveGameOptions{Mode: models.RemoveDelete, Permanent: true}); err != nil {
+		t.Fatalf("RemoveGame: %v", err)
+	}
+	if _, _, err := service.ImportBundle("/bundle.zip", "/imported"); !errors.Is(err, models.ErrArchiveLimit) {
+		t.Fatalf("ImportBundle = %v, want ErrArchiveLimit", err)
+	}
+	if games, _ := service.ListGames(); len(games) != 0 {
+		t.Fatalf("refused import registered %+v", games)
+	}
+	fsys.Walk(fs, "/home/.gamekeep/vault", func(path string, info os.FileInfo, err error) error {
+		if err == nil && strings.HasSuffix(path, ".zip") {
+			t.Errorf("refused import left %s in the vault", path)
+		}
+		return nil
+	})
+}
+
+func TestPurgingTrashDeletesRemovedGameCover(t *testing.T) {
+	service, fs, _ := newTestService(t)
+	writeSave(t, fs, "/images/cover.png", "png")
+
+	addGame := func(name, savePath string) (*models.Game, []string) {
+		t.Helper()
+		writeSave(t, fs, savePath+"/slot1.sav", "level=1")
+		game, err := service.AddGameWithOptions(name, savePath, models.GameOptions{CoverImage: "/images/cover.png"})
+		if err != nil {
+			t.Fatalf("AddGameWithOptions: %v", err)
+		}
+		var ids []string
+		for _, cp := range []string{"One", "Two"} {
+			checkpoint, err := service.CreateCheckpointWithOptions(game.ID, cp, "", models.CheckpointOptions{Force: true})
+			if err != nil {
+				t.Fatalf("CreateCheckpoint: %v", err)
+			}
+			ids = append(ids, checkpoint.ID)
+		}
+		if _, err := service.RemoveGame(game.ID, models.RemoveGameOptions{Mode: models.RemoveDelete}); err != nil {
+			t.Fatalf("RemoveGame: %v", err)
+		}
+		return game, ids
+	}
+	coverExists := func(game *models.Game) bool {
+		_, err := fs.Stat(service.CoverPath(game))
+		return err == nil
+	}
+
+	// Kept while any trashed checkpoint could register the game again
+	game, ids := addGame("Celeste", "/celeste")
+	if err := service.DeleteFromTrash(ids[0]); err != nil {
+		t.Fatalf("DeleteFromTrash: %v", err)
+	}
+	if !coverExists(game) {
+		t.Fatal("cover deleted while a trashed checkpoint still needs it")
+	}
+	if _, err := service.EmptyTrash(); err != nil {
+		t.Fatalf("EmptyTrash: %v", err)
+	}
+	if coverExists(game) {
+		t.Fatal("cover left behind once nothing could register the game again")
+	}
+
+	// Kept for a game registered again by restoring from the trash
+	game, ids = addGame("Hades", "/hades")
+	if _, err := service.RestoreFromTrash(ids[0]); err != nil {
+		t.Fatalf("RestoreFromTrash: %v", err)
+	}
+	if _, err := service.EmptyTrash(); err != nil {
+		t.Fatalf("EmptyTrash: %v", err)
+	}
+	if !coverExists(game) {
+		t.Fatal("cover of a registered game deleted with the trash")
+	}
+
+	// Deleted at once when none of the checkpoints' archives were left to
+	// go to the trash
+	writeSave(t, fs, "/inside/slot1.sav", "level=1")
+	game, err := service.AddGameWithOptions("Inside", "/inside", models.GameOptions{CoverImage: "/images/cover.png"})
+	if err != nil {
+		t.Fatalf("AddGameWithOptions: %v", err)
+	}
+	cp, err := service.CreateCheckpoint(game.ID, "One", "")
+	if err != nil {
+		t.Fatalf("CreateCheckpoint: %v", err)
+	}
+	if err := fs.Remove(filepath.Join("/home/.gamekeep/vault", cp.VaultFile)); err != nil {
+		t.Fatalf("Remove: %v", err)
+	}
+	if _, err := service.RemoveGame(game.ID, models.RemoveGameOptions{Mode: models.RemoveDelete}); err != nil {
+		t.Fatalf("RemoveGame: %v", err)
+	}
+	if coverExists(game) {
+		t.Fatal("cover left behind with nothing in the trash to need it")
+	}
+}
+
+func TestGameDetails(t *testing.T) {
+	service, fs, _ := newTestService(t)
+	writeSave(t, fs, "/images/cover.png", "png")
+	writeSave(t, fs, "/images/cover.jpg", "jpg")
+
+	opts := models.GameOptions{Platform: " Steam ", Notes: "100% run", CoverImage: "/images/cover.png"}
+	game, err := service.AddGameWithOptions("Hollow Knight", "/saves", opts)
+	if err != nil {
+		t.Fatalf("AddGameWithOptions: %v", err)
+	}
+	if game.Platform != "Steam" || game.CreatedAt.IsZero() || game.CoverImage == "" {
+		t.Fatalf("unexpected game: %+v", game)
+	}
+	if got := readSave(t, fs, service.CoverPath(game)); got != "png" {
+		t.Fatalf("cover = %q, want the copied image", got)
+	}
+
+	// Replacing the cover with another type removes the old file
+	jpg := "/images/cover.jpg"
+	updated, err := service.UpdateGame(game.ID, models.GameUpdate{CoverImage: &jpg})
+	if err != nil {
+		t.Fatalf("UpdateGame: %v", err)
+	}
+	if _, err := fs.Stat(service.CoverPath(game)); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("old cover still present: %v", err)
+	}
+
+	bad := "/images/cover.txt"
+	if _, err := service.UpdateGame(game.ID, models.GameUpdate{CoverImage: &bad}); !errors.Is(err, models.ErrInvalidCover) {
+		t.Fatalf("expected ErrInvalidCover, got %v", err)
+	}
+
+	playedAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	session := &models.Session{StartedAt: playedAt.Add(-time.Hour), EndedAt: playedAt}
+	if err := service.RecordSession(game.ID, session); err != nil {
+		t.Fatalf("RecordSession: %v", err)
+	}
+	if _, err := service.CreateCheckpoint(game.ID, "One", ""); err != nil {
+		t.Fatalf("CreateCheckpoint: %v", err)
+	}
+	stats, err := service.GameStats(game.ID)
+	if err != nil || stats.CheckpointCount != 1 || stats.TotalSize == 0 {
+		t.Fatalf("GameStats = %+v, %v", stats, err)
+	}
+
+	// Details and cover travel with an export
+	opts2 := models.RemoveGameOptions{Mode: models.RemoveExport, ExportPath: "/hk.zip", Permanent: true}
+	if _, err := service.RemoveGame(game.ID, opts2); err != nil {
+		t.Fatalf("RemoveGame: %v", err)
+	}
+	if _, err := fs.Stat(service.CoverPath(updated)); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("cover left behind after permanent removal: %v", err)
+	}
+
+	imported, _, err := service.ImportBundle("/hk.zip", "")
+	if err != nil {
+		t.Fatalf("ImportBundle: %v", err)
+	}
+	if imported.Platform != "Steam" || imported.Notes != "100% run" ||
+		imported.LastPlayedAt == nil || !imported.LastPlayedAt.Equal(playedAt) || imported.PlaytimeSeconds != 3600 {
+		t.Fatalf("details lost on import: %+v", imported)
+	}
+	if got := readSave(t, fs, service.CoverPath(imported)); got != "jpg" {
+		t.Fatalf("imported cover = %q, want jpg", got)
+	}
+}
@@ -0,0 +1,172 @@
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/adrielfilipedesign/gamekeep/internal/hooks"
+	"github.com/adrielfilipedesign/gamekeep/internal/models"
+)
+
+// runHook runs a hook command through the shell
+func runHook(hook models.Hook, hc models.HookContext) error {
+	input, err := json.Marshal(hc)
+	if err != nil {
+		return err
+	}
+
+	_, err = hooks.Run(hooks.Command{
+		Command: hook.Command,
+		Timeout: hook.Timeout(),
+		Env:     hc.Env(),
+		Stdin:   append(input, '\n'),
+	})
+	return err
+}
+
+// ListHooks returns the global hooks, or a game's own hooks if
+// gameIdentifier is set
+func (s *Service) ListHooks(gameIdentifier string) ([]models.Hook, error) {
+	if gameIdentifier == "" {
+		settings, err := s.GetSettings()
+		if err != nil {
+			return nil, fmt.Errorf("failed to load settings: %w", err)
+		}
+		return settings.Hooks, nil
+	}
+
+	game, err := s.GetGame(gameIdentifier)
+	if err != nil {
+		return nil, err
+	}
+	return game.Hooks, nil
+}
+
+// AddHook adds a global hook, or a game's hook if gameIdentifier is set.
+// Hooks for an event run in the order they were added.
+func (s *Service) AddHook(gameIdentifier string, hook models.Hook) error {
+	if err := hook.Validate(); err != nil {
+		return err
+	}
+	return s.changeHooks(gameIdentifier, func(current []models.Hook) ([]models.Hook, error) {
+		return append(append([]models.Hook{}, current...), hook), nil
+	})
+}
+
+// RemoveHook removes the hook at index in the list ListHooks returns
+func (s *Service) RemoveHook(gameIdentifier string, index int) error {
+	return s.changeHooks(gameIdentifier, func(current []models.Hook) ([]models.Hook, error) {
+		if index < 0 || index >= len(current) {
+			return nil, models.ErrHookNotFound
+		}
+		return append(append([]models.Hook{}, current[:index]...), current[index+1:]...), nil
+	})
+}
+
+// changeHooks replaces the global or a game's hooks
+func (s *Service) changeHooks(gameIdentifier string, change func([]models.Hook) ([]models.Hook, error)) error {
+	if gameIdentifier == "" {
+		settings, err := s.GetSettings()
+		if err != nil {
+			return fmt.Errorf("failed to load settings: %w", err)
+		}
+		if settings.Hooks, err = change(settings.Hooks); err != nil {
+			return err
+		}
+		return s.UpdateSettings(settings)
+	}
+
+	game, err := s.GetGame(gameIdentifier)
+	if err != nil {
+		return err
+	}
+	updated, err := change(game.Hooks)
+	if err != nil {
+		return err
+	}
+	_, err = s.UpdateGame(game.ID, models.GameUpdate{Hooks: &updated})
+	return err
+}
+
+// hooksFor returns the global hooks for an event followed by the game's
+func (s *Service) hooksFor(event models.HookEvent, game *models.Game) ([]models.Hook, error) {
+	settings, err := s.GetSettings()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load settings: %w", err)
+	}
+
+	var matched []models.Hook
+	for _, hook := range append(append([]models.Hook{}, settings.Hooks...), game.Hooks...) {
+		if hook.Event == event {
+			matched = append(matched, hook)
+		}
+	}
+	return matched, nil
+}
+
+// newHookContext describes an operation on a game, and optionally one of
+// its checkpoints, to hooks
+func (s *Service) newHookContext(game *models.Game, checkpoint *models.Checkpoint) models.HookContext {
+	hc := models.HookContext{
+		GameID:   game.ID,
+		GameName: game.Name,
+		SavePath: game.SavePath,
+		Origin:   s.origin,
+	}
+	if checkpoint != nil {
+		hc.CheckpointID = checkpoint.ID
+		hc.CheckpointName = checkpoint.Name
+	}
+	return hc
+}
+
+// runPreHooks runs the hooks for a pre event in order, stopping at the
+// first that fails, whose failure vetoes the operation
+func (s *Service) runPreHooks(event models.HookEvent, game *models.Game, hc models.HookContext) error {
+	matched, err := s.hooksFor(event, game)
+	if err != nil {
+		return err
+	}
+
+	hc.Event = event
+	for _, hook := range matched {
+		if err := s.runHook(hook, hc); err != nil {
+			return &models.HookVetoError{Event: event, Command: hook.Command, Err: err}
+		}
+	}
+	return nil
+}
+
+// runPostHooks runs every hook for a post event, telling them how the
+// operation went. Failures can't fail an operation that already happened,
+// so they are published as HookFailed events.
+func (s *Service) runPostHooks(event models.HookEvent, game *models.Game, hc models.HookContext, opErr error) {
+	matched, err := s.hooksFor(event, game)
+	if err != nil {
+		s.warnHook(game, err)
+		return
+	}
+
+	hc.Event = event
+	switch {
+	case opErr == nil:
+		hc.Outcome = models.HookOutcomeSuccess
+	case errors.Is(opErr, models.ErrNoChanges):
+		hc.Outcome = models.HookOutcomeUnchanged
+	default:
+		hc.Outcome = models.HookOutcomeFailure
+		hc.Error = opErr.Error()
+	}
+
+	for _, hook := range matched {
+		if err := s.runHook(hook, hc); err != nil {
+			s.warnHook(game, fmt.Errorf("%s hook %q failed: %w", event, hook.Command, err))
+		}
+	}
+}
+
+// warnHook reports a post hook problem to subscribers
+func (s *Service) warnHook(game *models.Game, err error) {
+	s.publish(models.Event{Type: models.EventHookFailed, GameID: game.ID, Err: err})
+}
@@ -0,0 +1,143 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"syscall"
+	"testing"
+
+	"github.com/adrielfilipedesign/gamekeep/internal/fsys"
+	"github.com/adrielfilipedesign/gamekeep/internal/models"
+)
+
+func TestHooks(t *testing.T) {
+	service, fs, game := newTestService(t)
+
+	var calls []models.HookContext
+	var commands []string
+	veto := ""
+	service.runHook = func(hook models.Hook, hc models.HookContext) error {
+		calls = append(calls, hc)
+		commands = append(commands, hook.Command)
+		if hook.Command == veto {
+			return fmt.Errorf("exit status 1")
+		}
+		return nil
+	}
+	var warnings []error
+	service.Subscribe(func(event models.Event) { warnings = append(warnings, event.Err) }, models.EventHookFailed)
+
+	if err := service.AddHook("", models.Hook{Event: "mid-checkpoint", Command: "x"}); !errors.Is(err, models.ErrInvalidHook) {
+		t.Fatalf("AddHook with unknown event = %v, want ErrInvalidHook", err)
+	}
+	for _, add := range []struct {
+		game string
+		hook models.Hook
+	}{
+		{game.ID, models.Hook{Event: models.HookPreCheckpoint, Command: "game-pre"}},
+		{"", models.Hook{Event: models.HookPreCheckpoint, Command: "global-pre"}},
+		{"", models.Hook{Event: models.HookPostCheckpoint, Command: "global-post"}},
+		{"", models.Hook{Event: models.HookPreRestore, Command: "restore-pre"}},
+	} {
+		if err := service.AddHook(add.game, add.hook); err != nil {
+			t.Fatalf("AddHook: %v", err)
+		}
+	}
+
+	cp, err := service.CreateCheckpoint(game.ID, "Hooked", "")
+	if err != nil {
+		t.Fatalf("CreateCheckpoint: %v", err)
+	}
+
+	// Global hooks run before the game's, and post hooks learn the outcome
+	if want := []string{"global-pre", "game-pre", "global-post"}; fmt.Sprint(commands) != fmt.Sprint(want) {
+		t.Fatalf("hooks run = %v, want %v", commands, want)
+	}
+	post := calls[2]
+	if post.Event != models.HookPostCheckpoint || post.CheckpointID != cp.ID || post.GameName != game.Name || post.Outcome != models.HookOutcomeSuccess {
+		t.Fatalf("post hook context = %+v", post)
+	}
+
+	// A failing pre hook stops the operation, and post hooks still run
+	calls, commands = nil, nil
+	veto = "game-pre"
+	_, err = service.CreateCheckpointWithOptions(game.ID, "Vetoed", "", models.CheckpointOptions{Force: true})
+	var vetoErr *models.HookVetoError
+	if !errors.As(err, &vetoErr) || !errors.Is(err, models.ErrHookVetoed) || vetoErr.Command != "game-pre" {
+		t.Fatalf("CreateCheckpoint with vetoing hook = %v", err)
+	}
+	if last := calls[len(calls)-1]; last.Event != models.HookPostCheckpoint || last.Outcome != models.HookOutcomeFailure {
+		t.Fatalf("post hook after veto = %+v", last)
+	}
+	if checkpoints, _ := service.ListCheckpoints(game.ID); len(checkpoints) != 1 {
+		t.Fatalf("vetoed checkpoint was created: %d checkpoints", len(checkpoints))
+	}
+
+	// A checkpoint that fails to save gives post hooks no ID to act on
+	veto = ""
+	fs.Inject(fsys.Fault{Op: fsys.OpCreate, Path: "/home/.gamekeep/vault", Err: syscall.ENOSPC})
+	_, err = service.CreateCheckpointWithOptions(game.ID, "Disk full", "", models.CheckpointOptions{Force: true})
+	fs.Reset()
+	if err == nil {
+		t.Fatal("CreateCheckpoint on a full disk succeeded")
+	}
+	if last := calls[len(calls)-1]; last.Outcome != models.HookOutcomeFailure || last.CheckpointID != "" || last.Error == "" {
+		t.Fatalf("post hook after a failed checkpoint = %+v", last)
+	}
+
+	veto = "restore-pre"
+	if err := service.RestoreCheckpoint(cp.ID); !errors.Is(err, models.ErrHookVetoed) {
+		t.Fatalf("RestoreCheckpoint with vetoing hook = %v", err)
+	}
+
+	// Post hook failures are warnings only
+	veto = "global-post"
+	if err := service.RemoveHook(game.ID, 0); err != nil {
+		t.Fatalf("RemoveHook: %v", err)
+	}
+	if _, err := service.CreateCheckpointWithOptions(game.ID, "Warned", "", models.CheckpointOptions{Force: true}); err != nil {
+		t.Fatalf("CreateCheckpoint with failing post hook: %v", err)
+	}
+	if len(warnings) != 1 {
+		t.Fatalf("warnings = %v, want one", warnings)
+	}
+
+	if err := service.RemoveHook(game.ID, 0); !errors.Is(err, models.ErrHookNotFound) {
+		t.Fatalf("RemoveHook past the end = %v, want ErrHookNotFound", err)
+	}
+}
+
+func TestImportBundleDropsCommands(t *testing.T) {
+	service, _, game := newTestService(t)
+	service.runHook = func(models.Hook, models.HookContext) error { return nil }
+
+	if err := service.AddHook(game.ID, models.Hook{Event: models.HookPreCheckpoint, Command: "touch /tmp/pwned"}); err != nil {
+		t.Fatalf("AddHook: %v", err)
+	}
+	executable, launch, processes := "/games/test/game.bin", "steam -applaunch 1", []string{"game.bin"}
+	if _, err := service.UpdateGame(game.ID, models.GameUpdate{Executable: &executable, LaunchCommand: &launch, ProcessNames: &processes}); err != nil {
+		t.Fatalf("UpdateGame: %v", err)
+	}
+	if _, err := service.CreateCheckpoint(game.ID, "One", ""); err != nil {
+		t.Fatalf("CreateCheckpoint: %v", err)
+	}
+	if _, err := service.ExportGame(game.ID, "/bundle.zip"); err != nil {
+		t.Fatalf("ExportGame: %v", err)
+	}
+	if _, err := service.RemoveGame(game.ID, models.RemoveGameOptions{Mode: models.RemoveDelete, Permanent: true}); err != nil {
+		t.Fatalf("RemoveGame: %v", err)
+	}
+
+	// A bundle from someone else must not bring commands that run here
+	imported, _, err := service.ImportBundle("/bundle.zip", "")
+	if err != nil {
+		t.Fatalf("ImportBundle: %v", err)
+	}
+	if len(imported.Hooks) != 0 || imported.LaunchCommand != "" || imported.Executable != "" || len(imported.ProcessNames) != 0 {
+		t.Fatalf("imported game kept commands: %+v", imported)
+	}
+	stored, err := service.GetGame(imported.ID)
+	if err != nil || len(stored.Hooks) != 0 || stored.LaunchCommand != "" {
+		t.Fatalf("registered game = %+v, %v; want no hooks or launch command", stored, err)
+	}
+}
@@ -0,0 +1,374 @@
+package core
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/adrielfilipedesign/gamekeep/internal/fsys"
+	"github.com/adrielfilipedesign/gamekeep/internal/models"
+)
+
+const (
+	// maxInspectDepth limits how deep inspectors look below the save path
+	maxInspectDepth = 4
+
+	// maxInspectFiles limits how many candidate files an inspector reads
+	maxInspectFiles = 50
+
+	// maxInspectFileSize skips files too large to be worth parsing
+	maxInspectFileSize = 8 << 20
+
+	// maxExtraValues limits how many values land in SaveMetadata.Extra
+	maxExtraValues = 100
+)
+
+// SaveInspector reads structured metadata from a game's save files
+type SaveInspector interface {
+	// Name identifies the inspector, e.g. in Game.Inspector
+	Name() string
+
+	// Inspect reads the saves under saveDir. It returns nil metadata and a
+	// nil error when the saves aren't in a format it understands.
+	Inspect(fs fsys.FS, game *models.Game, saveDir string) (*models.SaveMetadata, error)
+}
+
+// InspectorRegistry holds the save inspectors available to the service
+type InspectorRegistry struct {
+	inspectors []SaveInspector
+}
+
+// NewInspectorRegistry creates a registry; inspectors are tried in order
+func NewInspectorRegistry(inspectors ...SaveInspector) *InspectorRegistry {
+	return &InspectorRegistry{inspectors: inspectors}
+}
+
+// DefaultInspectors returns a registry with the built-in inspectors, most
+// specific format first
+func DefaultInspectors() *InspectorRegistry {
+	return NewInspectorRegistry(
+		renpyInspector{},
+		rpgMakerInspector{},
+		playerPrefsInspector{},
+		jsonInspector{},
+		iniInspector{},
+	)
+}
+
+// Register adds an inspector, replacing any with the same name
+func (r *InspectorRegistry) Register(inspector SaveInspector) {
+	for i, existing := range r.inspectors {
+		if existing.Name() == inspector.Name() {
+			r.inspectors[i] = inspector
+			return
+		}
+	}
+	r.inspectors = append(r.inspectors, inspector)
+}
+
+// Get returns the named inspector, or nil
+func (r *InspectorRegistry) Get(name string) SaveInspector {
+	for _, inspector := range r.inspectors {
+		if inspector.Name() == name {
+			return inspector
+		}
+	}
+	return nil
+}
+
+// Names returns the names of the registered inspectors in order
+func (r *InspectorRegistry) Names() []string {
+	names := make([]string, len(r.inspectors))
+	for i, inspector := range r.inspectors {
+		names[i] = inspector.Name()
+	}
+	return names
+}
+
+// Inspect runs the game's chosen inspector, or each inspector in turn until
+// one recognizes the saves. It returns nil metadata if none did.
+func (r *InspectorRegistry) Inspect(fs fsys.FS, game *models.Game) (*models.SaveMetadata, error) {
+	switch game.Inspector {
+	case models.InspectorNone:
+		return nil, nil
+	case "":
+		var firstErr error
+		for _, inspector := range r.inspectors {
+			meta, err := runInspector(inspector, fs, game)
+			if err != nil && firstErr == nil {
+				firstErr = err
+			}
+			if meta != nil {
+				return meta, nil
+			}
+		}
+		return nil, firstErr
+	}
+
+	inspector := r.Get(game.Inspector)
+	if inspector == nil {
+		return nil, fmt.Errorf("%w: %s", models.ErrUnknownInspector, game.Inspector)
+	}
+	return runInspector(inspector, fs, game)
+}
+
+// runInspector runs one inspector and labels its result
+func runInspector(inspector SaveInspector, fs fsys.FS, game *models.Game) (*models.SaveMetadata, error) {
+	meta, err := inspector.Inspect(fs, game, game.SavePath)
+	if err != nil {
+		return nil, fmt.Errorf("%s inspector: %w", inspector.Name(), err)
+	}
+	if meta == nil || meta.IsEmpty() {
+		return nil, nil
+	}
+	meta.Inspector = inspector.Name()
+	return meta, nil
+}
+
+// SetInspectors replaces the save inspectors used by the service
+func (s *Service) SetInspectors(inspectors *InspectorRegistry) {
+	s.inspectors = inspectors
+}
+
+// Inspectors returns the save inspectors used by the service
+func (s *Service) Inspectors() *InspectorRegistry {
+	return s.inspectors
+}
+
+// InspectSave reads metadata from a game's current saves without creating
+// a checkpoint. It returns nil metadata if no inspector recognized them.
+func (s *Service) InspectSave(gameIdentifier string) (*models.SaveMetadata, error) {
+	game, err := s.GetGame(gameIdentifier)
+	if err != nil {
+		return nil, err
+	}
+	return s.inspectors.Inspect(s.vaultMgr.FS(), game)
+}
+
+// inspectFields fills field values the caller didn't set from save
+// metadata. Values that don't fit a field's type are skipped.
+func inspectFields(defs []models.FieldDef, meta *models.SaveMetadata, fields map[string]string) map[string]string {
+	if meta == nil {
+		return fields
+	}
+
+	found := meta.Values()
+	filled := make(map[string]string, len(fields)+len(defs))
+	for key, value := range fields {
+		filled[key] = value
+	}
+	for _, def := range defs {
+		value, ok := found[def.Key]
+		if _, set := filled[def.Key]; set || !ok {
+			continue
+		}
+		if normalized, err := def.Normalize(value); err == nil {
+			filled[def.Key] = normalized
+		}
+	}
+	return filled
+}
+
+// saveFile is a candidate save file found by findSaveFiles
+type saveFile struct {
+	path string
+	info os.FileInfo
+}
+
+// slotName names a save slot after its file, without the extension
+func (f saveFile) slotName() string {
+	name := f.info.Name()
+	return strings.TrimSuffix(name, filepath.Ext(name))
+}
+
+// findSaveFiles lists regular files below dir accepted by match, newest
+// first. Deep directories and very large files are skipped.
+func findSaveFiles(fs fsys.FS, dir string, match func(name string) bool) ([]saveFile, error) {
+	var files []saveFile
+	err := fsys.Walk(fs, dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if rel, _ := filepath.Rel(dir, path); rel != "." && strings.Count(rel, string(filepath.Separator)) >= maxInspectDepth-1 {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if info.Mode().IsRegular() && info.Size() <= maxInspectFileSize && match(info.Name()) {
+			files = append(files, saveFile{path: path, info: info})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(files, func(i, j int) bool {
+		return files[i].info.ModTime().After(files[j].info.ModTime())
+	})
+	if len(files) > maxInspectFiles {
+		files = files[:maxInspectFiles]
+	}
+	return files, nil
+}
+
+// hasExt returns a matcher for file names with one of the extensions
+func hasExt(exts ...string) func(string) bool {
+	return func(name string) bool {
+		ext := strings.ToLower(filepath.Ext(name))
+		for _, e := range exts {
+			if ext == e {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// isSettingsFile reports whether a file name looks like game settings
+// rather than a save
+func isSettingsFile(name string) bool {
+	name = strings.ToLower(name)
+	for _, word := range []string{"config", "setting", "option", "keybind", "input", "graphics"} {
+		if strings.Contains(name, word) {
+			return true
+		}
+	}
+	return false
+}
+
+// metadataAliases maps the compact form of keys commonly used in saves to
+// the metadata they hold
+var metadataAliases = map[string]string{
+	"level": "level", "lvl": "level", "playerlevel": "level",
+	"charlevel": "level", "characterlevel": "level", "herolevel": "level",
+
+	"location": "location", "map": "location", "mapname": "location",
+	"scene": "location", "scenename": "location", "currentscene": "location",
+	"currentmap": "location", "area": "location", "zone": "location",
+	"region": "location", "room": "location",
+
+	"playtime": "playtime", "totalplaytime": "playtime", "timeplayed": "playtime",
+	"playtimeseconds": "playtime", "playedtime": "playtime", "gametime": "playtime",
+
+	"slot": "slot", "slotname": "slot", "savename": "slot", "savetitle": "slot",
+}
+
+// compactKey lowercases a key and drops everything but letters and digits
+func compactKey(key string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(key) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// extraKey turns a save's key into a custom field key, or "" if nothing
+// usable is left
+func extraKey(key string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(key) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '-' {
+			b.WriteRune(r)
+		} else {
+			b.WriteRune('_')
+		}
+	}
+	return collapseUnderscores(b.String())
+}
+
+// parsePlaytime reads a playtime given as seconds, "hh:mm:ss", "mm:ss" or a
+// Go duration such as "12h5m"
+func parsePlaytime(value string) (int64, bool) {
+	value = strings.TrimSpace(value)
+	if n, err := strconv.ParseFloat(value, 64); err == nil && n >= 0 {
+		return int64(n), true
+	}
+	if d, err := time.ParseDuration(value); err == nil && d >= 0 {
+		return int64(d.Seconds()), true
+	}
+
+	parts := strings.Split(value, ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return 0, false
+	}
+	var seconds int64
+	for _, part := range parts {
+		n, err := strconv.ParseInt(part, 10, 64)
+		if err != nil || n < 0 {
+			return 0, false
+		}
+		seconds = seconds*60 + n
+	}
+	return seconds, true
+}
+
+// metadataBuilder collects values read from a save into SaveMetadata
+type metadataBuilder struct {
+	meta models.SaveMetadata
+	slot string
+}
+
+// add records a value found under key. Top-level values are also kept in
+// Extra so custom fields can pick them up.
+func (b *metadataBuilder) add(key, value string, topLevel bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return
+	}
+
+	switch metadataAliases[compactKey(key)] {
+	case "level":
+		if b.meta.Level == "" {
+			b.meta.Level = value
+		}
+	case "location":
+		if b.meta.Location == "" {
+			b.meta.Location = value
+		}
+	case "playtime":
+		if seconds, ok := parsePlaytime(value); ok && b.meta.PlaytimeSeconds == 0 {
+			b.meta.PlaytimeSeconds = seconds
+		}
+	case "slot":
+		if b.slot == "" {
+			b.slot = value
+		}
+	}
+
+	if !topLevel || len(b.meta.Extra) >= maxExtraValues {
+		return
+	}
+	if k := extraKey(key); k != "" {
+		if b.meta.Extra == nil {
+			b.meta.Extra = make(map[string]string)
+		}
+		if _, exists := b.meta.Extra[k]; !exists {
+			b.meta.Extra[k] = value
+		}
+	}
+}
+
+// finish returns the metadata for the latest save, naming its slot from
+// the save's contents if it had one, or else fallback
+func (b *metadataBuilder) finish(fallback string, others []string) *models.SaveMetadata {
+	slot := b.slot
+	if slot == "" {
+		slot = fallback
+	}
+	meta := b.meta
+	if slot != "" {
+		meta.Slots = append([]string{slot}, others...)
+	} else {
+		meta.Slots = others
+	}
+	return &meta
+}
@@ -0,0 +1,164 @@
+package core
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/zlib"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/adrielfilipedesign/gamekeep/internal/fsys"
+	"github.com/adrielfilipedesign/gamekeep/internal/models"
+)
+
+func TestInspectors(t *testing.T) {
+	var renpy bytes.Buffer
+	zw := zip.NewWriter(&renpy)
+	for name, content := range map[string]string{
+		"log":        "pickled",
+		"extra_info": "Chapter 2",
+		"json":       `{"_save_name": "Before the ball", "_game_runtime": 3723.5, "_renpy_version": [8, 1, 0]}`,
+	} {
+		w, _ := zw.Create(name)
+		w.Write([]byte(content))
+	}
+	zw.Close()
+
+	var mz bytes.Buffer
+	zl := zlib.NewWriter(&mz)
+	zl.Write([]byte(`[{"title":"Quest","playtime":"00:05:00","timestamp":1},{"title":"Quest","playtime":"01:00:00","timestamp":2}]`))
+	zl.Close()
+
+	tests := []struct {
+		name      string
+		files     map[string]string
+		inspector string
+		want      models.SaveMetadata
+	}{
+		{
+			name: "json",
+			files: map[string]string{
+				"/saves/settings.json": `{"volume": 3}`,
+				"/saves/slot1.json":    `{"player": {"level": 12, "map": "Forest"}, "playTime": "01:30:00", "gold": 50}`,
+			},
+			inspector: "json",
+			want: models.SaveMetadata{
+				Level: "12", Location: "Forest", PlaytimeSeconds: 5400,
+				Slots: []string{"slot1"},
+				Extra: map[string]string{"playtime": "01:30:00", "gold": "50"},
+			},
+		},
+		{
+			name:      "ini",
+			files:     map[string]string{"/saves/save0.ini": "; comment\nSlotName = Harbor\n[Player]\nLevel=7\n"},
+			inspector: "ini",
+			want: models.SaveMetadata{
+				Level: "7", Slots: []string{"Harbor"},
+				Extra: map[string]string{"slotname": "Harbor", "player_level": "7"},
+			},
+		},
+		{
+			name: "unity playerprefs",
+			files: map[string]string{"/saves/prefs": `<unity_prefs version_major="1" version_minor="1">
+	<pref name="Level" type="int">4</pref>
+	<pref name="Scene" type="string">Q2F2ZXM=</pref>
+</unity_prefs>`},
+			inspector: "unity-playerprefs",
+			want: models.SaveMetadata{
+				Level: "4", Location: "Caves",
+				Extra: map[string]string{"level": "4", "scene": "Caves"},
+			},
+		},
+		{
+			name:      "renpy",
+			files:     map[string]string{"/saves/1-3-LT1.save": renpy.String(), "/saves/persistent": "x"},
+			inspector: "renpy",
+			want: models.SaveMetadata{
+				PlaytimeSeconds: 3723, Slots: []string{"Before the ball"},
+			},
+		},
+		{
+			name: "rpg maker mv",
+			files: map[string]string{
+				"/saves/global.rpgsave": "NoOwrgNhA0DeBEBzCB7ARgQwgSQCbwC54AlABQHEBZANXmngBcBLBiAU0PgAkBLgJxQACAIpg2AZwZ14ABwgYAnswC2HIgAYAjAXUAmHQGZpKiQwzKZhTQHZ1d+w/UBfOElSYc+ImSq16zVjVufiFRU2k5RRNOOwJNdR11YyZVSXNLOIA2RxynAF0gA=",
+			},
+			inspector: "rpgmaker",
+			want: models.SaveMetadata{
+				PlaytimeSeconds: 3723, Slots: []string{"File 1", "File 2"},
+				Extra: map[string]string{"title": "Héro Quest"},
+			},
+		},
+		{
+			name:      "rpg maker mz",
+			files:     map[string]string{"/saves/global.rmmzsave": mz.String()},
+			inspector: "rpgmaker",
+			want: models.SaveMetadata{
+				PlaytimeSeconds: 3600, Slots: []string{"File 1", "Autosave"},
+				Extra: map[string]string{"title": "Quest"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := fsys.NewMemFS()
+			for path, content := range tt.files {
+				writeSave(t, fs, path, content)
+			}
+
+			meta, err := DefaultInspectors().Inspect(fs, &models.Game{SavePath: "/saves"})
+			if err != nil {
+				t.Fatalf("Inspect: %v", err)
+			}
+			if meta == nil {
+				t.Fatal("no inspector recognized the saves")
+			}
+
+			tt.want.Inspector = tt.inspector
+			if !reflect.DeepEqual(*meta, tt.want) {
+				t.Fatalf("metadata =\n%+v\nwant\n%+v", *meta, tt.want)
+			}
+		})
+	}
+}
+
+func TestCreateCheckpointInspectsSaves(t *testing.T) {
+	service, fs, game := newTestService(t)
+	writeSave(t, fs, "/saves/slot1.sav", "level=12\nzone=Docks\n")
+
+	defs := []models.FieldDef{{Key: "level", Type: models.FieldNumber}, {Key: "location", Type: models.FieldString}}
+	if _, err := service.UpdateGame(game.ID, models.GameUpdate{Fields: &defs}); err != nil {
+		t.Fatalf("UpdateGame: %v", err)
+	}
+
+	opts := models.CheckpointOptions{Fields: map[string]string{"location": "Harbor"}}
+	cp, err := service.CreateCheckpointWithOptions(game.ID, "Docks", "", opts)
+	if err != nil {
+		t.Fatalf("CreateCheckpointWithOptions: %v", err)
+	}
+	if cp.SaveInfo == nil || cp.SaveInfo.Inspector != "ini" {
+		t.Fatalf("SaveInfo = %+v, want ini metadata", cp.SaveInfo)
+	}
+	// Inspected values fill gaps but never override what the user typed
+	if want := map[string]string{"level": "12", "location": "Harbor"}; !reflect.DeepEqual(cp.Fields, want) {
+		t.Fatalf("fields = %v, want %v", cp.Fields, want)
+	}
+
+	none := models.InspectorNone
+	if _, err := service.UpdateGame(game.ID, models.GameUpdate{Inspector: &none}); err != nil {
+		t.Fatalf("UpdateGame: %v", err)
+	}
+	cp, err = service.CreateCheckpointWithOptions(game.ID, "Uninspected", "", models.CheckpointOptions{Force: true})
+	if err != nil {
+		t.Fatalf("CreateCheckpoint: %v", err)
+	}
+	if cp.SaveInfo != nil || cp.Fields != nil {
+		t.Fatalf("inspection should be off, got %+v, %v", cp.SaveInfo, cp.Fields)
+	}
+
+	bogus := "bogus"
+	if _, err := service.UpdateGame(game.ID, models.GameUpdate{Inspector: &bogus}); !errors.Is(err, models.ErrUnknownInspector) {
+		t.Fatalf("expected ErrUnknownInspector, got %v", err)
+	}
+}
@@ -0,0 +1,506 @@
+package core
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/zlib"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/adrielfilipedesign/gamekeep/internal/fsys"
+	"github.com/adrielfilipedesign/gamekeep/internal/models"
+)
+
+// jsonInspector reads saves stored as JSON objects
+type jsonInspector struct{}
+
+func (jsonInspector) Name() string { return "json" }
+
+func (jsonInspector) Inspect(fs fsys.FS, game *models.Game, saveDir string) (*models.SaveMetadata, error) {
+	files, err := findSaveFiles(fs, saveDir, func(name string) bool {
+		return hasExt(".json", ".sav", ".save", ".dat")(name) && !isSettingsFile(name)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var latest *metadataBuilder
+	var latestFile saveFile
+	var others []string
+	for _, file := range files {
+		data, err := fsys.ReadFile(fs, file.path)
+		if err != nil {
+			return nil, err
+		}
+
+		var object map[string]interface{}
+		if json.Unmarshal(data, &object) != nil {
+			continue
+		}
+		if latest != nil {
+			others = append(others, file.slotName())
+			continue
+		}
+
+		latest, latestFile = &metadataBuilder{}, file
+		addJSON(latest, object, 0)
+	}
+
+	if latest == nil {
+		return nil, nil
+	}
+	return latest.finish(latestFile.slotName(), others), nil
+}
+
+// addJSON records the values of a JSON object, looking a few levels into
+// nested objects for well-known keys. Shallower values win.
+func addJSON(b *metadataBuilder, object map[string]interface{}, depth int) {
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var nested []map[string]interface{}
+	for _, key := range keys {
+		switch value := object[key].(type) {
+		case string:
+			b.add(key, value, depth == 0)
+		case float64:
+			b.add(key, strconv.FormatFloat(value, 'f', -1, 64), depth == 0)
+		case bool:
+			b.add(key, strconv.FormatBool(value), depth == 0)
+		case map[string]interface{}:
+			nested = append(nested, value)
+		}
+	}
+
+	if depth < 3 {
+		for _, child := range nested {
+			addJSON(b, child, depth+1)
+		}
+	}
+}
+
+// iniInspector reads saves stored as INI-style key=value text
+type iniInspector struct{}
+
+func (iniInspector) Name() string { return "ini" }
+
+func (iniInspector) Inspect(fs fsys.FS, game *models.Game, saveDir string) (*models.SaveMetadata, error) {
+	files, err := findSaveFiles(fs, saveDir, func(name string) bool {
+		return hasExt(".ini", ".cfg", ".sav", ".save", ".txt")(name) && !isSettingsFile(name)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var latest *metadataBuilder
+	var latestFile saveFile
+	var others []string
+	for _, file := range files {
+		data, err := fsys.ReadFile(fs, file.path)
+		if err != nil {
+			return nil, err
+		}
+
+		b := &metadataBuilder{}
+		if !parseINI(b, data) {
+			continue
+		}
+		if latest != nil {
+			others = append(others, file.slotName())
+			continue
+		}
+		latest, latestFile = b, file
+	}
+
+	if latest == nil {
+		return nil, nil
+	}
+	return latest.finish(latestFile.slotName(), others), nil
+}
+
+// parseINI records the values of INI text, reporting false if data isn't
+// INI. Keys outside the first section are kept in Extra as section_key.
+func parseINI(b *metadataBuilder, data []byte) bool {
+	if !utf8.Valid(data) {
+		return false
+	}
+
+	section := ""
+	found := false
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "" || strings.HasPrefix(line, ";") || strings.HasPrefix(line, "#"):
+			continue
+		case strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]"):
+			section = strings.TrimSpace(line[1 : len(line)-1])
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return false
+		}
+		value = strings.Trim(strings.TrimSpace(value), `"`)
+
+		if section != "" {
+			b.add(key, value, false)
+			b.add(section+"_"+key, value, true)
+		} else {
+			b.add(key, value, true)
+		}
+		found = true
+	}
+	return found && scanner.Err() == nil
+}
+
+// playerPrefsInspector reads Unity PlayerPrefs stored as XML, both the
+// Linux "prefs" file and Android shared preferences
+type playerPrefsInspector struct{}
+
+func (playerPrefsInspector) Name() string { return "unity-playerprefs" }
+
+func (playerPrefsInspector) Inspect(fs fsys.FS, game *models.Game, saveDir string) (*models.SaveMetadata, error) {
+	files, err := findSaveFiles(fs, saveDir, func(name string) bool {
+		return name == "prefs" || hasExt(".xml")(name)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, file := range files {
+		data, err := fsys.ReadFile(fs, file.path)
+		if err != nil {
+			return nil, err
+		}
+
+		b := &metadataBuilder{}
+		if parsePlayerPrefs(b, data) {
+			return b.finish("", nil), nil
+		}
+	}
+	return nil, nil
+}
+
+// parsePlayerPrefs records the values of a PlayerPrefs XML document,
+// reporting false if data isn't one
+func parsePlayerPrefs(b *metadataBuilder, data []byte) bool {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	depth := 0
+	var current *xml.StartElement
+	var text strings.Builder
+
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return depth == 0
+		}
+		if err != nil {
+			return false
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			depth++
+			if depth == 1 && t.Name.Local != "unity_prefs" && t.Name.Local != "map" {
+				return false
+			}
+			if depth == 2 {
+				el := t.Copy()
+				current = &el
+				text.Reset()
+			}
+		case xml.CharData:
+			if current != nil {
+				text.Write(t)
+			}
+		case xml.EndElement:
+			if depth == 2 && current != nil {
+				addPref(b, current, text.String())
+				current = nil
+			}
+			depth--
+		}
+	}
+}
+
+// addPref records one preference. Unity on Linux stores strings base64
+// encoded; Android keeps values in a value attribute.
+func addPref(b *metadataBuilder, el *xml.StartElement, text string) {
+	var name, typ, value string
+	hasValue := false
+	for _, attr := range el.Attr {
+		switch attr.Name.Local {
+		case "name":
+			name = attr.Value
+		case "type":
+			typ = attr.Value
+		case "value":
+			value, hasValue = attr.Value, true
+		}
+	}
+	if !hasValue {
+		value = strings.TrimSpace(text)
+	}
+
+	if typ == "string" {
+		if decoded, err := base64.StdEncoding.DecodeString(value); err == nil && isPrintable(decoded) {
+			value = string(decoded)
+		}
+	}
+	b.add(name, value, true)
+}
+
+// isPrintable reports whether data is non-empty UTF-8 text without
+// control characters
+func isPrintable(data []byte) bool {
+	if len(data) == 0 || !utf8.Valid(data) {
+		return false
+	}
+	for _, r := range string(data) {
+		if r < 0x20 || r == 0x7f {
+			return false
+		}
+	}
+	return true
+}
+
+// renpyInspector reads Ren'Py .save archives, which carry the save name
+// and playtime in a "json" entry
+type renpyInspector struct{}
+
+func (renpyInspector) Name() string { return "renpy" }
+
+func (renpyInspector) Inspect(fs fsys.FS, game *models.Game, saveDir string) (*models.SaveMetadata, error) {
+	files, err := findSaveFiles(fs, saveDir, hasExt(".save"))
+	if err != nil {
+		return nil, err
+	}
+
+	var latest *metadataBuilder
+	var latestFile saveFile
+	var others []string
+	for _, file := range files {
+		b := &metadataBuilder{}
+		ok, err := readRenpySave(fs, file, b)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+		if latest != nil {
+			others = append(others, renpySlot(file, b))
+			continue
+		}
+		latest, latestFile = b, file
+	}
+
+	if latest == nil {
+		return nil, nil
+	}
+	return latest.finish(renpySlot(latestFile, latest), others), nil
+}
+
+// readRenpySave records a Ren'Py save's metadata, reporting false if the
+// file isn't one
+func readRenpySave(fs fsys.FS, file saveFile, b *metadataBuilder) (bool, error) {
+	f, err := fs.Open(file.path)
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+
+	archive, err := zip.NewReader(f, file.info.Size())
+	if err != nil {
+		return false, nil
+	}
+
+	entries := make(map[string]*zip.File, len(archive.File))
+	for _, entry := range archive.File {
+		entries[entry.Name] = entry
+	}
+	if entries["log"] == nil {
+		return false, nil
+	}
+
+	if entry := entries["json"]; entry != nil {
+		data, err := readZipEntry(entry)
+		if err != nil {
+			return false, err
+		}
+		var object map[string]interface{}
+		if json.Unmarshal(data, &object) == nil {
+			if name, ok := object["_save_name"].(string); ok {
+				b.add("slot", name, false)
+			}
+			if runtime, ok := object["_game_runtime"].(float64); ok {
+				b.add("playtime", strconv.FormatInt(int64(runtime), 10), false)
+			}
+
+			// Keys starting with "_" belong to Ren'Py; the rest come from
+			// the game's own save_json_callbacks
+			for key := range object {
+				if strings.HasPrefix(key, "_") {
+					delete(object, key)
+				}
+			}
+			addJSON(b, object, 0)
+		}
+	}
+
+	if entry := entries["extra_info"]; entry != nil && b.slot == "" {
+		data, err := readZipEntry(entry)
+		if err != nil {
+			return false, err
+		}
+		b.add("slot", string(data), false)
+	}
+
+	return true, nil
+}
+
+// renpySlot names a Ren'Py save by its save name, or else by its page and
+// slot number (e.g. "1-3" for "1-3-LT1.save")
+func renpySlot(file saveFile, b *metadataBuilder) string {
+	if b.slot != "" {
+		return b.slot
+	}
+	parts := strings.SplitN(file.slotName(), "-", 3)
+	if len(parts) >= 2 {
+		return parts[0] + "-" + parts[1]
+	}
+	return file.slotName()
+}
+
+// readZipEntry reads a small archive entry
+func readZipEntry(entry *zip.File) ([]byte, error) {
+	r, err := entry.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	return io.ReadAll(io.LimitReader(r, maxInspectFileSize))
+}
+
+// rpgMakerInspector reads the slot list RPG Maker MV and MZ keep in
+// global.rpgsave and global.rmmzsave
+type rpgMakerInspector struct{}
+
+func (rpgMakerInspector) Name() string { return "rpgmaker" }
+
+// rpgMakerSlot is one entry of RPG Maker's global save info
+type rpgMakerSlot struct {
+	Title     string `json:"title"`
+	Playtime  string `json:"playtime"`
+	Timestamp int64  `json:"timestamp"`
+}
+
+func (rpgMakerInspector) Inspect(fs fsys.FS, game *models.Game, saveDir string) (*models.SaveMetadata, error) {
+	files, err := findSaveFiles(fs, saveDir, func(name string) bool {
+		return name == "global.rpgsave" || name == "global.rmmzsave"
+	})
+	if err != nil || len(files) == 0 {
+		return nil, err
+	}
+
+	data, err := fsys.ReadFile(fs, files[0].path)
+	if err != nil {
+		return nil, err
+	}
+
+	var text string
+	var ok bool
+	mz := strings.HasSuffix(files[0].path, ".rmmzsave")
+	if mz {
+		text, ok = inflateRPGMakerMZ(data)
+	} else {
+		text, ok = lzDecompressFromBase64(strings.TrimSpace(string(data)))
+	}
+	if !ok {
+		return nil, nil
+	}
+
+	var slots []*rpgMakerSlot
+	if json.Unmarshal([]byte(text), &slots) != nil {
+		return nil, nil
+	}
+
+	type namedSlot struct {
+		name string
+		slot *rpgMakerSlot
+	}
+	var found []namedSlot
+	for i, slot := range slots {
+		if slot == nil {
+			continue
+		}
+		name := "File " + strconv.Itoa(i)
+		if mz && i == 0 {
+			name = "Autosave"
+		}
+		found = append(found, namedSlot{name, slot})
+	}
+	if len(found) == 0 {
+		return nil, nil
+	}
+
+	sort.SliceStable(found, func(i, j int) bool {
+		return found[i].slot.Timestamp > found[j].slot.Timestamp
+	})
+
+	b := &metadataBuilder{}
+	b.add("playtime", found[0].slot.Playtime, false)
+	b.add("title", found[0].slot.Title, true)
+
+	others := make([]string, 0, len(found)-1)
+	for _, s := range found[1:] {
+		others = append(others, s.name)
+	}
+	return b.finish(found[0].name, others), nil
+}
+
+// inflateRPGMakerMZ decodes an MZ save: zlib-compressed JSON, which MZ
+// writes as a binary string encoded as UTF-8
+func inflateRPGMakerMZ(data []byte) (string, bool) {
+	if text, ok := inflate(data); ok {
+		return text, true
+	}
+
+	raw := make([]byte, 0, len(data))
+	for _, r := range string(data) {
+		if r > 0xff {
+			return "", false
+		}
+		raw = append(raw, byte(r))
+	}
+	return inflate(raw)
+}
+
+// inflate decompresses zlib data
+func inflate(data []byte) (string, bool) {
+	r, err := zlib.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return "", false
+	}
+	defer r.Close()
+
+	text, err := io.ReadAll(io.LimitReader(r, maxInspectFileSize))
+	if err != nil {
+		return "", false
+	}
+	return string(text), true
+}
@@ -0,0 +1,66 @@
+package core
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/adrielfilipedesign/gamekeep/internal/models"
+)
+
+// SetOrigin sets which front end is driving this service, for the journal
+func (s *Service) SetOrigin(origin models.Origin) {
+	s.origin = origin
+}
+
+// ListJournal returns journal entries matching the filter, newest first
+func (s *Service) ListJournal(filter models.JournalFilter) ([]models.JournalEntry, error) {
+	entries, err := s.store.LoadJournal()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load journal: %w", err)
+	}
+
+	matched := []models.JournalEntry{}
+	for i := len(entries) - 1; i >= 0; i-- {
+		if !filter.Matches(&entries[i]) {
+			continue
+		}
+		matched = append(matched, entries[i])
+		if filter.Limit > 0 && len(matched) >= filter.Limit {
+			break
+		}
+	}
+
+	return matched, nil
+}
+
+// newEntry starts a journal entry for an operation; fill in the IDs as they
+// become known and pass it to record when the operation finishes
+func (s *Service) newEntry(op models.Operation) *models.JournalEntry {
+	return &models.JournalEntry{
+		Operation: op,
+		Origin:    s.origin,
+		Hostname:  s.hostname,
+		PID:       s.pid,
+	}
+}
+
+// record completes a journal entry with the operation's outcome and
+// publishes it; the journal is written by a subscriber. Journal failures
+// never fail the operation itself.
+func (s *Service) record(entry *models.JournalEntry, err error) {
+	entry.Timestamp = time.Now().UTC()
+	entry.Outcome = models.OutcomeSuccess
+	if err != nil {
+		entry.Outcome = models.OutcomeFailure
+		entry.Error = err.Error()
+	}
+
+	s.publish(models.Event{
+		Type:         models.EventOperation,
+		At:           entry.Timestamp,
+		Origin:       entry.Origin,
+		GameID:       entry.GameID,
+		CheckpointID: entry.CheckpointID,
+		Entry:        entry,
+	})
+}
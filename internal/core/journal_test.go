@@ -0,0 +1,48 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/adrielfilipedesign/gamekeep/internal/models"
+)
+
+func TestJournalRecordsOutcomes(t *testing.T) {
+	service, _, game := newTestService(t)
+	service.SetOrigin(models.OriginDaemon)
+
+	cp, err := service.CreateCheckpoint(game.ID, "Auto", "")
+	if err != nil {
+		t.Fatalf("CreateCheckpoint: %v", err)
+	}
+	if err := service.RestoreCheckpoint("does-not-exist"); err == nil {
+		t.Fatal("expected restore of unknown checkpoint to fail")
+	}
+
+	entries, err := service.ListJournal(models.JournalFilter{})
+	if err != nil {
+		t.Fatalf("ListJournal: %v", err)
+	}
+
+	// Newest first: failed restore, checkpoint, add-game
+	if len(entries) != 3 {
+		t.Fatalf("got %d entries, want 3: %+v", len(entries), entries)
+	}
+
+	restore := entries[0]
+	if restore.Operation != models.OpRestoreCheckpoint || restore.Outcome != models.OutcomeFailure || restore.Error == "" {
+		t.Errorf("unexpected restore entry: %+v", restore)
+	}
+
+	created := entries[1]
+	if created.Operation != models.OpCreateCheckpoint || created.CheckpointID != cp.ID || created.GameID != game.ID {
+		t.Errorf("unexpected checkpoint entry: %+v", created)
+	}
+	if created.Origin != models.OriginDaemon {
+		t.Errorf("origin = %q, want %q", created.Origin, models.OriginDaemon)
+	}
+
+	failed, _ := service.ListJournal(models.JournalFilter{FailedOnly: true})
+	if len(failed) != 1 {
+		t.Errorf("got %d failed entries, want 1", len(failed))
+	}
+}
@@ -0,0 +1,104 @@
+package core
+
+import (
+	"sort"
+
+	"github.com/adrielfilipedesign/gamekeep/internal/models"
+)
+
+// CheckpointTree returns a game's checkpoints as a lineage tree, along with
+// the ID of the game's head checkpoint. Checkpoints whose parent is gone
+// (deleted, or created before lineage was recorded) become roots. Roots and
+// children are ordered oldest first.
+func (s *Service) CheckpointTree(gameIdentifier string) ([]*models.CheckpointNode, string, error) {
+	game, err := s.GetGame(gameIdentifier)
+	if err != nil {
+		return nil, "", err
+	}
+
+	checkpoints, err := s.ListCheckpoints(game.ID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	sort.SliceStable(checkpoints, func(i, j int) bool {
+		return checkpoints[i].CreatedAt.Before(checkpoints[j].CreatedAt)
+	})
+
+	nodes := make(map[string]*models.CheckpointNode, len(checkpoints))
+	for _, cp := range checkpoints {
+		nodes[cp.ID] = &models.CheckpointNode{Checkpoint: cp}
+	}
+
+	roots := []*models.CheckpointNode{}
+	for _, cp := range checkpoints {
+		node := nodes[cp.ID]
+		if parent, ok := nodes[cp.ParentID]; ok && cp.ParentID != cp.ID {
+			parent.Children = append(parent.Children, node)
+		} else {
+			roots = append(roots, node)
+		}
+	}
+
+	return roots, game.HeadCheckpointID, nil
+}
+
+// CheckpointLineage returns a checkpoint followed by its ancestors, nearest
+// first, stopping at the first one that no longer exists
+func (s *Service) CheckpointLineage(checkpointID string) ([]models.Checkpoint, error) {
+	checkpoint, err := s.GetCheckpoint(checkpointID)
+	if err != nil {
+		return nil, err
+	}
+
+	checkpoints, err := s.ListCheckpoints(checkpoint.GameID)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[string]models.Checkpoint, len(checkpoints))
+	for _, cp := range checkpoints {
+		byID[cp.ID] = cp
+	}
+
+	lineage := []models.Checkpoint{*checkpoint}
+	seen := map[string]bool{checkpoint.ID: true}
+	for parentID := checkpoint.ParentID; parentID != "" && !seen[parentID]; {
+		parent, ok := byID[parentID]
+		if !ok {
+			break
+		}
+		lineage = append(lineage, parent)
+		seen[parentID] = true
+		parentID = parent.ParentID
+	}
+
+	return lineage, nil
+}
+
+// setHead records the checkpoint a game's next checkpoint branches from.
+// Lineage is best effort: the checkpoint operation itself already succeeded,
+// so a failure here only leaves the next parent stale.
+func (s *Service) setHead(gameID, checkpointID string) {
+	games, err := s.store.LoadGames()
+	if err != nil {
+		return
+	}
+
+	for i := range games {
+		if games[i].ID == gameID {
+			games[i].HeadCheckpointID = checkpointID
+		}
+	}
+	s.store.SaveGames(games)
+}
+
+// moveHeadOff moves a game's head to the parent of a checkpoint being
+// deleted, so new checkpoints don't branch from one that is gone
+func (s *Service) moveHeadOff(checkpoint *models.Checkpoint) {
+	game, err := s.GetGame(checkpoint.GameID)
+	if err != nil || game.HeadCheckpointID != checkpoint.ID {
+		return
+	}
+	s.setHead(game.ID, checkpoint.ParentID)
+}
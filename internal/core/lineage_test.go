@@ -0,0 +1,59 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/adrielfilipedesign/gamekeep/internal/models"
+)
+
+func TestCheckpointLineageBranches(t *testing.T) {
+	service, _, game := newTestService(t)
+
+	create := func(name string) string {
+		t.Helper()
+		cp, err := service.CreateCheckpointWithOptions(game.ID, name, "", models.CheckpointOptions{Force: true})
+		if err != nil {
+			t.Fatalf("CreateCheckpoint: %v", err)
+		}
+		return cp.ID
+	}
+
+	a := create("a")
+	b := create("b")
+	if err := service.RestoreCheckpoint(a); err != nil {
+		t.Fatalf("RestoreCheckpoint: %v", err)
+	}
+	c := create("c")
+
+	roots, head, err := service.CheckpointTree(game.ID)
+	if err != nil {
+		t.Fatalf("CheckpointTree: %v", err)
+	}
+	if head != c {
+		t.Errorf("head = %s, want %s", head, c)
+	}
+	if len(roots) != 1 || roots[0].Checkpoint.ID != a {
+		t.Fatalf("expected a single root a, got %+v", roots)
+	}
+	children := roots[0].Children
+	if len(children) != 2 || children[0].Checkpoint.ID != b || children[1].Checkpoint.ID != c {
+		t.Fatalf("expected a to branch into b and c, got %+v", children)
+	}
+
+	lineage, err := service.CheckpointLineage(c)
+	if err != nil {
+		t.Fatalf("CheckpointLineage: %v", err)
+	}
+	if len(lineage) != 2 || lineage[0].ID != c || lineage[1].ID != a {
+		t.Fatalf("unexpected lineage: %+v", lineage)
+	}
+
+	// Deleting the head moves it back to the parent
+	if err := service.DeleteCheckpoint(c, false); err != nil {
+		t.Fatalf("DeleteCheckpoint: %v", err)
+	}
+	d := create("d")
+	if cp, _ := service.GetCheckpoint(d); cp.ParentID != a {
+		t.Errorf("d parent = %s, want %s", cp.ParentID, a)
+	}
+}
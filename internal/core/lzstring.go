@@ -0,0 +1,121 @@
+package core
+
+import (
+	"strings"
+	"unicode/utf16"
+)
+
+// lzBase64Alphabet is the alphabet lz-string uses for compressToBase64
+const lzBase64Alphabet = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789+/="
+
+// lzDecompressFromBase64 decodes lz-string's compressToBase64 output, the
+// format RPG Maker MV uses for save files
+func lzDecompressFromBase64(input string) (string, bool) {
+	if input == "" {
+		return "", false
+	}
+
+	values := make([]int, len(input))
+	for i := range input {
+		v := strings.IndexByte(lzBase64Alphabet, input[i])
+		if v < 0 {
+			return "", false
+		}
+		values[i] = v
+	}
+
+	r := &lzReader{values: values, position: 32, reset: 32}
+	r.val = values[0]
+	r.index = 1
+
+	dictionary := [][]uint16{{0}, {1}, {2}}
+	enlargeIn, numBits := 4, 3
+
+	var w []uint16
+	switch r.bits(2) {
+	case 0:
+		w = []uint16{uint16(r.bits(8))}
+	case 1:
+		w = []uint16{uint16(r.bits(16))}
+	default:
+		return "", true
+	}
+	dictionary = append(dictionary, w)
+	result := append([]uint16(nil), w...)
+
+	for {
+		if r.index > len(values) {
+			return "", false
+		}
+
+		c := r.bits(numBits)
+		switch c {
+		case 0, 1:
+			size := 8
+			if c == 1 {
+				size = 16
+			}
+			dictionary = append(dictionary, []uint16{uint16(r.bits(size))})
+			c = len(dictionary) - 1
+			enlargeIn--
+		case 2:
+			return string(utf16.Decode(result)), true
+		}
+
+		if enlargeIn == 0 {
+			enlargeIn = 1 << numBits
+			numBits++
+		}
+
+		var entry []uint16
+		switch {
+		case c < len(dictionary):
+			entry = dictionary[c]
+		case c == len(dictionary):
+			entry = append(append([]uint16(nil), w...), w[0])
+		default:
+			return "", false
+		}
+
+		result = append(result, entry...)
+		dictionary = append(dictionary, append(append([]uint16(nil), w...), entry[0]))
+		enlargeIn--
+		w = entry
+
+		if enlargeIn == 0 {
+			enlargeIn = 1 << numBits
+			numBits++
+		}
+	}
+}
+
+// lzReader reads lz-string's bit stream, six bits per base64 character
+type lzReader struct {
+	values   []int
+	val      int
+	position int
+	reset    int
+	index    int
+}
+
+// bits reads n bits, least significant first
+func (r *lzReader) bits(n int) int {
+	result := 0
+	for power := 1; power < 1<<n; power <<= 1 {
+		bit := r.val & r.position
+		r.position >>= 1
+		if r.position == 0 {
+			r.position = r.reset
+			if r.index < len(r.values) {
+				r.val = r.values[r.index]
+			} else {
+				r.val = 0
+			}
+			r.index++
+		}
+		if bit > 0 {
+			result |= power
+		}
+	}
+	return result
+}
@@ -0,0 +1,378 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/adrielfilipedesign/gamekeep/internal/models"
+)
+
+// scheduledTag marks the checkpoints schedules take
+const scheduledTag = "scheduled"
+
+// maxSchedulerSleep bounds how long the scheduler waits between looks at
+// the schedules, so ones added meanwhile are picked up
+const maxSchedulerSleep = time.Minute
+
+// AddSchedule adds a schedule for a game. Only the schedule's timing fields
+// are used; its first run is counted from now.
+func (s *Service) AddSchedule(gameIdentifier string, schedule models.Schedule) (_ *models.Schedule, err error) {
+	entry := s.newEntry(models.OpAddSchedule)
+	defer func() { s.record(entry, err) }()
+
+	game, err := s.GetGame(gameIdentifier)
+	if err != nil {
+		return nil, err
+	}
+	entry.GameID = game.ID
+
+	added := models.Schedule{
+		ID:              uuid.New().String(),
+		GameID:          game.ID,
+		Kind:            schedule.Kind,
+		IntervalMinutes: schedule.IntervalMinutes,
+		DailyAt:         strings.TrimSpace(schedule.DailyAt),
+		Cron:            strings.TrimSpace(schedule.Cron),
+		Paused:          schedule.Paused,
+		CreatedAt:       time.Now().UTC(),
+	}
+	if err := added.Validate(); err != nil {
+		return nil, err
+	}
+	entry.Detail = added.Describe()
+
+	schedules, err := s.store.LoadSchedules()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load schedules: %w", err)
+	}
+
+	schedules = append(schedules, added)
+	if err := s.store.SaveSchedules(schedules); err != nil {
+		return nil, fmt.Errorf("failed to save schedules: %w", err)
+	}
+
+	return &added, nil
+}
+
+// ListSchedules lists a game's schedules, or every schedule if
+// gameIdentifier is empty
+func (s *Service) ListSchedules(gameIdentifier string) ([]models.Schedule, error) {
+	schedules, err := s.store.LoadSchedules()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load schedules: %w", err)
+	}
+	if gameIdentifier == "" {
+		return schedules, nil
+	}
+
+	game, err := s.GetGame(gameIdentifier)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []models.Schedule{}
+	for _, schedule := range schedules {
+		if schedule.GameID == game.ID {
+			result = append(result, schedule)
+		}
+	}
+	return result, nil
+}
+
+// GetSchedule finds a schedule by ID or unique ID prefix
+func (s *Service) GetSchedule(scheduleID string) (*models.Schedule, error) {
+	schedules, err := s.store.LoadSchedules()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load schedules: %w", err)
+	}
+
+	i, err := findSchedule(schedules, scheduleID)
+	if err != nil {
+		return nil, err
+	}
+	return &schedules[i], nil
+}
+
+// SetSchedulePaused pauses or resumes a schedule
+func (s *Service) SetSchedulePaused(scheduleID string, paused bool) (_ *models.Schedule, err error) {
+	entry := s.newEntry(models.OpUpdateSchedule)
+	defer func() { s.record(entry, err) }()
+
+	schedules, err := s.store.LoadSchedules()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load schedules: %w", err)
+	}
+
+	i, err := findSchedule(schedules, scheduleID)
+	if err != nil {
+		return nil, err
+	}
+	schedule := &schedules[i]
+	entry.GameID = schedule.GameID
+	entry.Detail = "resumed " + schedule.Describe()
+	if paused {
+		entry.Detail = "paused " + schedule.Describe()
+	}
+
+	schedule.Paused = paused
+	if err := s.store.SaveSchedules(schedules); err != nil {
+		return nil, fmt.Errorf("failed to save schedules: %w", err)
+	}
+	return schedule, nil
+}
+
+// RemoveSchedule removes a schedule. Checkpoints it took are kept.
+func (s *Service) RemoveSchedule(scheduleID string) (err error) {
+	entry := s.newEntry(models.OpRemoveSchedule)
+	defer func() { s.record(entry, err) }()
+
+	schedules, err := s.store.LoadSchedules()
+	if err != nil {
+		return fmt.Errorf("failed to load schedules: %w", err)
+	}
+
+	i, err := findSchedule(schedules, scheduleID)
+	if err != nil {
+		return err
+	}
+	entry.GameID = schedules[i].GameID
+	entry.Detail = schedules[i].Describe()
+
+	schedules = append(schedules[:i], schedules[i+1:]...)
+	if err := s.store.SaveSchedules(schedules); err != nil {
+		return fmt.Errorf("failed to save schedules: %w", err)
+	}
+	return nil
+}
+
+// removeGameSchedules removes a game's schedules without journaling them,
+// as part of removing the game
+func (s *Service) removeGameSchedules(gameID string) error {
+	schedules, err := s.store.LoadSchedules()
+	if err != nil {
+		return fmt.Errorf("failed to load schedules: %w", err)
+	}
+
+	remaining := []models.Schedule{}
+	for _, schedule := range schedules {
+		if schedule.GameID != gameID {
+			remaining = append(remaining, schedule)
+		}
+	}
+	if len(remaining) == len(schedules) {
+		return nil
+	}
+
+	if err := s.store.SaveSchedules(remaining); err != nil {
+		return fmt.Errorf("failed to save schedules: %w", err)
+	}
+	return nil
+}
+
+// findSchedule returns the index of the schedule whose ID equals or
+// uniquely starts with query
+func findSchedule(schedules []models.Schedule, query string) (int, error) {
+	for i := range schedules {
+		if schedules[i].ID == query {
+			return i, nil
+		}
+	}
+	if len(query) < MinCheckpointIDPrefix {
+		return -1, models.ErrScheduleNotFound
+	}
+
+	var matches []int
+	for i := range schedules {
+		if strings.HasPrefix(schedules[i].ID, query) {
+			matches = append(matches, i)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return -1, models.ErrScheduleNotFound
+	case 1:
+		return matches[0], nil
+	}
+
+	ambiguous := &models.AmbiguousError{Kind: "schedule", Query: query}
+	for _, i := range matches {
+		ambiguous.Candidates = append(ambiguous.Candidates, models.Candidate{
+			ID:     schedules[i].ID,
+			Name:   schedules[i].Describe(),
+			Detail: schedules[i].GameID,
+		})
+	}
+	return -1, ambiguous
+}
+
+// Scheduler runs schedules as they come due. Create one with
+// Service.NewScheduler.
+type Scheduler struct {
+	service *Service
+	opts    models.SchedulerOptions
+}
+
+// NewScheduler creates a scheduler. Nothing runs until RunDue or Run is
+// called.
+func (s *Service) NewScheduler(opts models.SchedulerOptions) *Scheduler {
+	return &Scheduler{service: s, opts: opts}
+}
+
+// Run runs schedules as they come due until stop is closed. Schedules that
+// came due while nothing was running run once, straight away.
+func (sch *Scheduler) Run(stop <-chan struct{}) error {
+	for {
+		sleep := maxSchedulerSleep
+		if _, err := sch.RunDue(time.Now()); err != nil {
+			sch.emit(models.ScheduleRun{At: time.Now().UTC(), Err: err})
+		} else if next, err := sch.NextDue(); err == nil && !next.IsZero() {
+			if until := time.Until(next); until < sleep {
+				sleep = until
+			}
+		}
+		if sleep < time.Second {
+			sleep = time.Second
+		}
+
+		select {
+		case <-stop:
+			return nil
+		case <-time.After(sleep):
+		}
+	}
+}
+
+// NextDue returns when the next unpaused schedule comes due; zero if none will
+func (sch *Scheduler) NextDue() (time.Time, error) {
+	schedules, err := sch.service.store.LoadSchedules()
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to load schedules: %w", err)
+	}
+
+	var next time.Time
+	for i := range schedules {
+		if schedules[i].Paused {
+			continue
+		}
+		at := schedules[i].NextRun()
+		if !at.IsZero() && (next.IsZero() || at.Before(next)) {
+			next = at
+		}
+	}
+	return next, nil
+}
+
+// RunDue runs every unpaused schedule that is due at now and returns what
+// each run did. A run's own failure is reported on its result, not as the
+// error.
+func (sch *Scheduler) RunDue(now time.Time) ([]models.ScheduleRun, error) {
+	schedules, err := sch.service.store.LoadSchedules()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load schedules: %w", err)
+	}
+
+	var runs []models.ScheduleRun
+	for i := range schedules {
+		schedule := &schedules[i]
+		next := schedule.NextRun()
+		if schedule.Paused || next.IsZero() || next.After(now) {
+			continue
+		}
+
+		run := sch.runSchedule(schedule, now)
+		runs = append(runs, run)
+		sch.emit(run)
+	}
+	return runs, nil
+}
+
+// runSchedule checkpoints a schedule's game unless its saves match the
+// game's latest checkpoint, then saves the schedule's last run state
+func (sch *Scheduler) runSchedule(schedule *models.Schedule, now time.Time) models.ScheduleRun {
+	s := sch.service
+	run := models.ScheduleRun{
+		ScheduleID: schedule.ID,
+		GameID:     schedule.GameID,
+		At:         now.UTC(),
+	}
+
+	game, err := s.GetGame(schedule.GameID)
+	if err == nil {
+		run.GameName = game.Name
+		if game.Archived {
+			// Archived games' saves are no longer being played
+			run.Skipped = true
+		} else if cp, cpErr := s.createScheduledCheckpoint(game, schedule); errors.Is(cpErr, models.ErrNoChanges) {
+			run.Skipped = true
+		} else if cpErr != nil {
+			err = cpErr
+		} else {
+			run.CheckpointID = cp.ID
+		}
+	}
+	run.Err = err
+
+	// Record the run on the schedule as it is stored now, in case it was
+	// changed or removed while the checkpoint was being taken
+	if err := s.updateScheduleState(schedule.ID, func(stored *models.Schedule) {
+		at := run.At
+		stored.LastRunAt = &at
+		stored.LastError = ""
+		if run.Err != nil {
+			stored.LastError = run.Err.Error()
+		}
+		if run.CheckpointID != "" {
+			stored.LastCheckpointID = run.CheckpointID
+		}
+	}); err != nil && run.Err == nil {
+		run.Err = err
+	}
+
+	return run
+}
+
+// createScheduledCheckpoint takes a checkpoint tagged as scheduled
+func (s *Service) createScheduledCheckpoint(game *models.Game, schedule *models.Schedule) (*models.Checkpoint, error) {
+	cp, err := s.CreateCheckpoint(game.ID, "Scheduled checkpoint", fmt.Sprintf("Taken automatically, %s", schedule.Describe()))
+	if err != nil {
+		return nil, err
+	}
+
+	// Best effort: the checkpoint is what matters, not the tag
+	if tagged, err := s.AddCheckpointTags(cp.ID, scheduledTag); err == nil {
+		cp = tagged
+	}
+	return cp, nil
+}
+
+// updateScheduleState changes a stored schedule's run state. A schedule
+// that no longer exists is left alone.
+func (s *Service) updateScheduleState(scheduleID string, update func(*models.Schedule)) error {
+	schedules, err := s.store.LoadSchedules()
+	if err != nil {
+		return fmt.Errorf("failed to load schedules: %w", err)
+	}
+
+	for i := range schedules {
+		if schedules[i].ID == scheduleID {
+			update(&schedules[i])
+			if err := s.store.SaveSchedules(schedules); err != nil {
+				return fmt.Errorf("failed to save schedules: %w", err)
+			}
+			return nil
+		}
+	}
+	return nil
+}
+
+// emit passes a run to the OnRun callback, if any
+func (sch *Scheduler) emit(run models.ScheduleRun) {
+	if sch.opts.OnRun != nil {
+		sch.opts.OnRun(run)
+	}
+}
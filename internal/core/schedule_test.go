@@ -0,0 +1,102 @@
+package core
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/adrielfilipedesign/gamekeep/internal/models"
+)
+
+func TestScheduler(t *testing.T) {
+	service, fs, game := newTestService(t)
+
+	if _, err := service.AddSchedule(game.ID, models.Schedule{Kind: models.ScheduleInterval}); !errors.Is(err, models.ErrInvalidSchedule) {
+		t.Fatalf("AddSchedule without interval = %v, want ErrInvalidSchedule", err)
+	}
+
+	schedule, err := service.AddSchedule(game.ID, models.Schedule{Kind: models.ScheduleInterval, IntervalMinutes: 30})
+	if err != nil {
+		t.Fatalf("AddSchedule: %v", err)
+	}
+
+	scheduler := service.NewScheduler(models.SchedulerOptions{})
+	runDue := func(after time.Duration) []models.ScheduleRun {
+		t.Helper()
+		runs, err := scheduler.RunDue(schedule.CreatedAt.Add(after))
+		if err != nil {
+			t.Fatalf("RunDue: %v", err)
+		}
+		return runs
+	}
+
+	if runs := runDue(10 * time.Minute); len(runs) != 0 {
+		t.Fatalf("runs before due = %+v", runs)
+	}
+
+	runs := runDue(31 * time.Minute)
+	if len(runs) != 1 || runs[0].CheckpointID == "" || runs[0].Err != nil {
+		t.Fatalf("first run = %+v", runs)
+	}
+	cp, err := service.GetCheckpoint(runs[0].CheckpointID)
+	if err != nil || !cp.HasTag(scheduledTag) {
+		t.Fatalf("scheduled checkpoint = %+v, %v", cp, err)
+	}
+
+	// The last run is stored, so the next is counted from it
+	stored, err := service.GetSchedule(schedule.ID[:8])
+	if err != nil || stored.LastRunAt == nil || stored.LastCheckpointID != cp.ID {
+		t.Fatalf("stored schedule = %+v, %v", stored, err)
+	}
+	if runs := runDue(40 * time.Minute); len(runs) != 0 {
+		t.Fatalf("runs right after a run = %+v", runs)
+	}
+
+	// Unchanged saves are skipped
+	runs = runDue(62 * time.Minute)
+	if len(runs) != 1 || !runs[0].Skipped || runs[0].CheckpointID != "" {
+		t.Fatalf("run with unchanged saves = %+v", runs)
+	}
+
+	writeSave(t, fs, "/saves/slot1.sav", "level=13")
+	runs = runDue(93 * time.Minute)
+	if len(runs) != 1 || runs[0].Skipped || runs[0].CheckpointID == "" {
+		t.Fatalf("run with changed saves = %+v", runs)
+	}
+
+	// Paused schedules don't run
+	if _, err := service.SetSchedulePaused(schedule.ID, true); err != nil {
+		t.Fatalf("SetSchedulePaused: %v", err)
+	}
+	writeSave(t, fs, "/saves/slot1.sav", "level=14")
+	if runs := runDue(200 * time.Minute); len(runs) != 0 {
+		t.Fatalf("runs while paused = %+v", runs)
+	}
+
+	if err := service.RemoveSchedule(schedule.ID); err != nil {
+		t.Fatalf("RemoveSchedule: %v", err)
+	}
+	if _, err := service.GetSchedule(schedule.ID); !errors.Is(err, models.ErrScheduleNotFound) {
+		t.Fatalf("GetSchedule after remove = %v, want ErrScheduleNotFound", err)
+	}
+}
+
+func TestFindScheduleAmbiguousPrefix(t *testing.T) {
+	schedules := []models.Schedule{
+		{ID: "5f3a9c01-aaaa", GameID: "hollow-knight", Kind: models.ScheduleInterval, IntervalMinutes: 30},
+		{ID: "5f3a9c02-bbbb", GameID: "celeste", Kind: models.ScheduleDaily, DailyAt: "21:30"},
+	}
+
+	if i, err := findSchedule(schedules, "5f3a9c02"); err != nil || i != 1 {
+		t.Fatalf("findSchedule unique prefix = %d, %v; want 1", i, err)
+	}
+
+	_, err := findSchedule(schedules, "5f3a9c0")
+	var ambiguous *models.AmbiguousError
+	if !errors.As(err, &ambiguous) || !errors.Is(err, models.ErrAmbiguous) {
+		t.Fatalf("findSchedule ambiguous prefix = %v, want AmbiguousError", err)
+	}
+	if ambiguous.Kind != "schedule" || len(ambiguous.Candidates) != 2 || ambiguous.Candidates[1].Name != "daily at 21:30" {
+		t.Fatalf("ambiguous = %+v", ambiguous)
+	}
+}
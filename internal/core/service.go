@@ -1,33 +1,84 @@
 package core
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"os"
 	"path/filepath"
 	"strings"
 	"time"
 
 	"github.com/google/uuid"
+
 	"github.com/adrielfilipedesign/gamekeep/internal/models"
+	"github.com/adrielfilipedesign/gamekeep/internal/procwatch"
 	"github.com/adrielfilipedesign/gamekeep/internal/storage"
 	"github.com/adrielfilipedesign/gamekeep/internal/vault"
 )
 
+// MinCheckpointIDPrefix is the shortest checkpoint ID prefix accepted in
+// place of a full ID
+const MinCheckpointIDPrefix = 4
+
+// errNoMatch reports that a lookup found nothing; callers map it to the
+// matching not-found error
+var errNoMatch = errors.New("no match")
+
 // Service handles core business logic
 type Service struct {
-	store       storage.MetadataStore
-	vaultMgr    *vault.Manager
+	store      storage.MetadataStore
+	vaultMgr   *vault.Manager
+	origin     models.Origin
+	hostname   string
+	pid        int
+	events     *EventBus
+	inspectors *InspectorRegistry
+	startGame  func(command []string) (gameProcess, error)
+
+	listProcesses func() ([]procwatch.Process, error)
+
+	runHook func(hook models.Hook, hc models.HookContext) error
 }
 
 // NewService creates a new service instance
 func NewService(store storage.MetadataStore, vaultMgr *vault.Manager) *Service {
-	return &Service{
-		store:    store,
-		vaultMgr: vaultMgr,
+	hostname, _ := os.Hostname()
+
+	s := &Service{
+		store:      store,
+		vaultMgr:   vaultMgr,
+		origin:     models.OriginCLI,
+		hostname:   hostname,
+		pid:        os.Getpid(),
+		events:     NewEventBus(),
+		inspectors: DefaultInspectors(),
+		startGame:  startProcess,
+
+		listProcesses: procwatch.List,
+
+		runHook: runHook,
 	}
+
+	// Write the journal first, so other subscribers can already read
+	// the operation they hear about
+	s.events.Subscribe(s.appendJournal, models.EventOperation)
+
+	return s
 }
 
 // AddGame registers a new game in the system
 func (s *Service) AddGame(name, savePath string) (*models.Game, error) {
+	return s.AddGameWithOptions(name, savePath, models.GameOptions{})
+}
+
+// AddGameWithOptions registers a new game with optional details such as its
+// platform and cover image
+func (s *Service) AddGameWithOptions(name, savePath string, opts models.GameOptions) (_ *models.Game, err error) {
+	entry := s.newEntry(models.OpAddGame)
+	entry.Detail = name
+	defer func() { s.record(entry, err) }()
+
 	// Validate input
 	if name == "" {
 		return nil, models.ErrEmptyGameName
@@ -48,29 +99,51 @@ func (s *Service) AddGame(name, savePath string) (*models.Game, error) {
 	// Check for duplicate name
 	for _, g := range games {
 		if strings.EqualFold(g.Name, name) {
-			return nil, models.ErrGameExists
+			return nil, gameExistsError(&g)
 		}
 	}
 
-	// Create game with sanitized ID
+	// IDs name vault directories, so they must be unique even when
+	// different names map to the same ID
+	taken, err := s.takenGameIDs(games)
+	if err != nil {
+		return nil, err
+	}
+
 	game := &models.Game{
-		ID:       s.sanitizeID(name),
-		Name:     name,
-		SavePath: cleanPath,
+		ID:            uniqueGameID(baseGameID(name), taken),
+		Name:          name,
+		SavePath:      cleanPath,
+		Platform:      strings.TrimSpace(opts.Platform),
+		Executable:    strings.TrimSpace(opts.Executable),
+		LaunchCommand: strings.TrimSpace(opts.LaunchCommand),
+		Notes:         opts.Notes,
+		CreatedAt:     time.Now().UTC(),
 	}
 
 	if err := game.Validate(); err != nil {
 		return nil, err
 	}
 
+	if opts.CoverImage != "" {
+		if game.CoverImage, err = s.copyCover(game.ID, opts.CoverImage); err != nil {
+			return nil, err
+		}
+	}
+
 	// Add to list
 	games = append(games, *game)
 
 	// Save
 	if err := s.store.SaveGames(games); err != nil {
+		if game.CoverImage != "" {
+			s.vaultMgr.DeleteCover(game.CoverImage)
+		}
 		return nil, fmt.Errorf("failed to save games: %w", err)
 	}
 
+	entry.GameID = game.ID
+	s.publish(models.Event{Type: models.EventGameAdded, GameID: game.ID, Game: game})
 	return game, nil
 }
 
@@ -95,34 +168,236 @@ func (s *Service) GetGame(identifier string) (*models.Game, error) {
 		}
 	}
 
-	// Try partial match
+	// Try partial match, which must be unambiguous
+	var matches []models.Game
 	for _, g := range games {
 		if strings.Contains(strings.ToLower(g.ID), strings.ToLower(identifier)) {
-			return &g, nil
+			matches = append(matches, g)
 		}
 	}
 
-	return nil, models.ErrGameNotFound
+	switch len(matches) {
+	case 0:
+		return nil, models.ErrGameNotFound
+	case 1:
+		return &matches[0], nil
+	}
+
+	ambiguous := &models.AmbiguousError{Kind: "game", Query: identifier}
+	for _, g := range matches {
+		ambiguous.Candidates = append(ambiguous.Candidates, models.Candidate{
+			ID:     g.ID,
+			Name:   g.Name,
+			Detail: g.SavePath,
+		})
+	}
+	return nil, ambiguous
 }
 
-// ListGames returns all registered games
+// UpdateGame changes a game's name and/or save path. The game ID never
+// changes, so existing checkpoints stay attached.
+func (s *Service) UpdateGame(identifier string, update models.GameUpdate) (_ *models.Game, err error) {
+	entry := s.newEntry(models.OpUpdateGame)
+	entry.GameID = identifier
+	defer func() { s.record(entry, err) }()
+
+	game, err := s.GetGame(identifier)
+	if err != nil {
+		return nil, err
+	}
+	entry.GameID = game.ID
+
+	games, err := s.store.LoadGames()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load games: %w", err)
+	}
+
+	if update.Name != nil {
+		name := strings.TrimSpace(*update.Name)
+		if name == "" {
+			return nil, models.ErrEmptyGameName
+		}
+
+		// Check for duplicate name among the other games
+		for _, g := range games {
+			if g.ID != game.ID && strings.EqualFold(g.Name, name) {
+				return nil, gameExistsError(&g)
+			}
+		}
+
+		entry.Detail = fmt.Sprintf("renamed %q to %q", game.Name, name)
+		game.Name = name
+	}
+
+	if update.SavePath != nil {
+		if strings.TrimSpace(*update.SavePath) == "" {
+			return nil, models.ErrEmptySavePath
+		}
+		game.SavePath = filepath.Clean(*update.SavePath)
+	}
+
+	if update.Archived != nil {
+		game.Archived = *update.Archived
+	}
+
+	if update.Fields != nil {
+		game.Fields = *update.Fields
+	}
+
+	if update.Inspector != nil {
+		name := strings.TrimSpace(*update.Inspector)
+		if name != "" && name != models.InspectorNone && s.inspectors.Get(name) == nil {
+			return nil, fmt.Errorf("%w: %s", models.ErrUnknownInspector, name)
+		}
+		game.Inspector = name
+	}
+
+	if update.Platform != nil {
+		game.Platform = strings.TrimSpace(*update.Platform)
+	}
+	if update.Executable != nil {
+		game.Executable = strings.TrimSpace(*update.Executable)
+	}
+	if update.LaunchCommand != nil {
+		game.LaunchCommand = strings.TrimSpace(*update.LaunchCommand)
+	}
+	if update.Notes != nil {
+		game.Notes = *update.Notes
+	}
+	if update.ProcessNames != nil {
+		game.ProcessNames = cleanProcessNames(*update.ProcessNames)
+	}
+	if update.Hooks != nil {
+		game.Hooks = *update.Hooks
+	}
+	if update.Compression != nil {
+		game.Compression = nil
+		if update.Compression.Codec != "" {
+			compression := *update.Compression
+			game.Compression = &compression
+		}
+	}
+
+	if err := game.Validate(); err != nil {
+		return nil, err
+	}
+
+	// Copy the cover last, once nothing else can fail validation
+	oldCover := game.CoverImage
+	if update.CoverImage != nil {
+		game.CoverImage = ""
+		if *update.CoverImage != "" {
+			if game.CoverImage, err = s.copyCover(game.ID, *update.CoverImage); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	for i := range games {
+		if games[i].ID == game.ID {
+			games[i] = *game
+		}
+	}
+
+	if err := s.store.SaveGames(games); err != nil {
+		return nil, fmt.Errorf("failed to save games: %w", err)
+	}
+
+	// Best effort: a stale cover only wastes a little space
+	if oldCover != "" && oldCover != game.CoverImage {
+		s.vaultMgr.DeleteCover(oldCover)
+	}
+
+	return game, nil
+}
+
+// ListGames returns all registered games that are not archived
 func (s *Service) ListGames() ([]models.Game, error) {
-	return s.store.LoadGames()
+	games, err := s.store.LoadGames()
+	if err != nil {
+		return nil, err
+	}
+
+	active := []models.Game{}
+	for _, g := range games {
+		if !g.Archived {
+			active = append(active, g)
+		}
+	}
+	return active, nil
 }
 
 // CreateCheckpoint creates a new checkpoint for a game
 func (s *Service) CreateCheckpoint(gameIdentifier, name, note string) (*models.Checkpoint, error) {
+	return s.CreateCheckpointWithOptions(gameIdentifier, name, note, models.CheckpointOptions{})
+}
+
+// CreateCheckpointWithOptions creates a checkpoint with custom field values
+// and other optional settings
+func (s *Service) CreateCheckpointWithOptions(gameIdentifier, name, note string, opts models.CheckpointOptions) (*models.Checkpoint, error) {
+	return s.CreateCheckpointContext(context.Background(), gameIdentifier, name, note, opts)
+}
+
+// CreateCheckpointContext creates a checkpoint like
+// CreateCheckpointWithOptions. If ctx is done while the saves are being
+// archived, the partial archive is removed and ctx's error returned.
+func (s *Service) CreateCheckpointContext(ctx context.Context, gameIdentifier, name, note string, opts models.CheckpointOptions) (_ *models.Checkpoint, err error) {
+	entry := s.newEntry(models.OpCreateCheckpoint)
+	entry.GameID = gameIdentifier
+	entry.Detail = name
+	defer func() {
+		// Declining to checkpoint unchanged saves isn't worth journaling;
+		// automation does it all the time
+		if !errors.Is(err, models.ErrNoChanges) {
+			s.record(entry, err)
+		}
+	}()
+
 	// Get game
 	game, err := s.GetGame(gameIdentifier)
 	if err != nil {
 		return nil, err
 	}
+	entry.GameID = game.ID
+	if game.Archived {
+		return nil, models.ErrGameArchived
+	}
+
+	hc := s.newHookContext(game, nil)
+	hc.CheckpointName = name
+	defer func() { s.runPostHooks(models.HookPostCheckpoint, game, hc, err) }()
+	if err := s.runPreHooks(models.HookPreCheckpoint, game, hc); err != nil {
+		return nil, err
+	}
+
+	// Check field values before doing any work
+	fields, err := models.NormalizeFields(game.Fields, opts.Fields)
+	if err != nil {
+		return nil, err
+	}
+
+	fingerprint, err := s.readSaveState(game, opts.Force)
+	if err != nil {
+		return nil, err
+	}
+
+	// Inspection is best effort: saves no inspector can read still get
+	// a checkpoint, just without metadata
+	saveInfo, _ := s.inspectors.Inspect(s.vaultMgr.FS(), game)
+	fields = inspectFields(game.Fields, saveInfo, fields)
+	if len(fields) == 0 {
+		fields = nil
+	}
 
 	// Generate checkpoint ID
 	checkpointID := uuid.New().String()
+	entry.CheckpointID = checkpointID
 
 	// Create vault archive
-	vaultFile, hash, err := s.vaultMgr.CreateCheckpoint(game.ID, checkpointID, game.SavePath)
+	progress := s.progressFunc(models.OpCreateCheckpoint, game.ID, opts.Progress)
+	archiveOpts := s.archiveOptions(game, progress)
+	archiveOpts.Contents = vault.NewContentHasher()
+	vaultFile, hash, err := s.vaultMgr.CreateCheckpointContext(ctx, game.ID, checkpointID, game.SavePath, archiveOpts)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create checkpoint archive: %w", err)
 	}
@@ -136,6 +411,13 @@ func (s *Service) CreateCheckpoint(gameIdentifier, name, note string) (*models.C
 		VaultFile: vaultFile,
 		Hash:      hash,
 		CreatedAt: time.Now().UTC(),
+		ParentID:  game.HeadCheckpointID,
+		Fields:    fields,
+		SaveInfo:  saveInfo,
+
+		SaveFingerprint: fingerprint,
+		ContentHash:     archiveOpts.Contents.Sum(),
+		Codec:           archiveOpts.Compression.Codec,
 	}
 
 	if err := checkpoint.Validate(); err != nil {
@@ -162,9 +444,42 @@ func (s *Service) CreateCheckpoint(gameIdentifier, name, note string) (*models.C
 		return nil, fmt.Errorf("failed to save checkpoints: %w", err)
 	}
 
+	// Only now is there a checkpoint for post hooks to look at
+	hc.CheckpointID = checkpoint.ID
+	s.setHead(game.ID, checkpoint.ID)
+
+	s.publish(models.Event{
+		Type:         models.EventCheckpointCreated,
+		GameID:       game.ID,
+		CheckpointID: checkpoint.ID,
+		Checkpoint:   checkpoint,
+	})
 	return checkpoint, nil
 }
 
+// archiveOptions returns how to create a game's archives under the current
+// settings; settings that can't be read just mean the defaults
+func (s *Service) archiveOptions(game *models.Game, progress models.ProgressFunc) vault.ArchiveOptions {
+	opts := vault.ArchiveOptions{
+		Compression: game.ArchiveCompression(),
+		Progress:    progress,
+	}
+	if settings, err := s.GetSettings(); err == nil {
+		opts.Workers = settings.CompressionWorkers
+	}
+	return opts
+}
+
+// archiveLimits returns the limits archives are held to when restored or
+// imported; settings that can't be read just mean the defaults
+func (s *Service) archiveLimits() models.ArchiveLimits {
+	settings, err := s.GetSettings()
+	if err != nil {
+		return models.DefaultArchiveLimits()
+	}
+	return settings.ArchiveLimits
+}
+
 // ListCheckpoints returns checkpoints for a specific game
 func (s *Service) ListCheckpoints(gameIdentifier string) ([]models.Checkpoint, error) {
 	// Get game to validate it exists
@@ -197,49 +512,252 @@ func (s *Service) GetCheckpoint(checkpointID string) (*models.Checkpoint, error)
 		return nil, fmt.Errorf("failed to load checkpoints: %w", err)
 	}
 
-	for _, cp := range checkpoints {
-		if cp.ID == checkpointID || strings.HasPrefix(cp.ID, checkpointID) {
-			return &cp, nil
+	index, err := matchCheckpointID(checkpointID, len(checkpoints), func(i int) models.Checkpoint {
+		return checkpoints[i]
+	})
+	if err != nil {
+		if errors.Is(err, errNoMatch) {
+			return nil, models.ErrCheckpointNotFound
+		}
+		return nil, err
+	}
+
+	return &checkpoints[index], nil
+}
+
+// matchCheckpointID finds the one checkpoint among n whose ID equals or
+// starts with query. It returns errNoMatch if there is none, and an
+// AmbiguousError listing the candidates if there are several.
+func matchCheckpointID(query string, n int, checkpoint func(i int) models.Checkpoint) (int, error) {
+	for i := 0; i < n; i++ {
+		if checkpoint(i).ID == query {
+			return i, nil
+		}
+	}
+
+	// Short prefixes match too much to be safe for restore and delete
+	if len(query) < MinCheckpointIDPrefix {
+		return -1, fmt.Errorf("%w: use at least %d characters", models.ErrCheckpointIDTooShort, MinCheckpointIDPrefix)
+	}
+
+	var matches []int
+	for i := 0; i < n; i++ {
+		if strings.HasPrefix(checkpoint(i).ID, query) {
+			matches = append(matches, i)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return -1, errNoMatch
+	case 1:
+		return matches[0], nil
+	}
+
+	ambiguous := &models.AmbiguousError{Kind: "checkpoint", Query: query}
+	for _, i := range matches {
+		cp := checkpoint(i)
+		ambiguous.Candidates = append(ambiguous.Candidates, models.Candidate{
+			ID:     cp.ID,
+			Name:   cp.Name,
+			Detail: fmt.Sprintf("%s, %s", cp.GameID, cp.CreatedAt.Local().Format("2006-01-02 15:04")),
+		})
+	}
+	return -1, ambiguous
+}
+
+// UpdateCheckpoint changes a checkpoint's name and/or note
+func (s *Service) UpdateCheckpoint(checkpointID string, update models.CheckpointUpdate) (_ *models.Checkpoint, err error) {
+	entry := s.newEntry(models.OpUpdateCheckpoint)
+	entry.CheckpointID = checkpointID
+	defer func() { s.record(entry, err) }()
+
+	checkpoint, err := s.GetCheckpoint(checkpointID)
+	if err != nil {
+		return nil, err
+	}
+	entry.CheckpointID = checkpoint.ID
+	entry.GameID = checkpoint.GameID
+
+	if update.Name != nil {
+		name := strings.TrimSpace(*update.Name)
+		if name == "" {
+			return nil, models.ErrEmptyCheckpointName
+		}
+		checkpoint.Name = name
+	}
+
+	if update.Note != nil {
+		checkpoint.Note = *update.Note
+	}
+
+	if update.Tags != nil {
+		tags, err := models.NormalizeTags(*update.Tags)
+		if err != nil {
+			return nil, err
+		}
+		checkpoint.Tags = tags
+	}
+
+	if update.Protected != nil {
+		checkpoint.Protected = *update.Protected
+	}
+
+	if len(update.Fields) > 0 {
+		fields, err := s.mergeFields(checkpoint, update.Fields)
+		if err != nil {
+			return nil, err
 		}
+		checkpoint.Fields = fields
+	}
+	entry.Detail = checkpoint.Name
+
+	if err := checkpoint.Validate(); err != nil {
+		return nil, err
+	}
+
+	checkpoints, err := s.store.LoadCheckpoints()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load checkpoints: %w", err)
 	}
 
-	return nil, models.ErrCheckpointNotFound
+	for i := range checkpoints {
+		if checkpoints[i].ID == checkpoint.ID {
+			checkpoints[i] = *checkpoint
+		}
+	}
+
+	if err := s.store.SaveCheckpoints(checkpoints); err != nil {
+		return nil, fmt.Errorf("failed to save checkpoints: %w", err)
+	}
+
+	return checkpoint, nil
 }
 
 // RestoreCheckpoint restores a checkpoint to the game's save directory
 func (s *Service) RestoreCheckpoint(checkpointID string) error {
+	return s.RestoreCheckpointContext(context.Background(), checkpointID, nil)
+}
+
+// RestoreCheckpointContext restores a checkpoint like RestoreCheckpoint,
+// reporting progress to report if it is not nil. If ctx is done before
+// the saves are swapped in, they are left untouched and ctx's error
+// returned.
+func (s *Service) RestoreCheckpointContext(ctx context.Context, checkpointID string, report models.ProgressFunc) (err error) {
+	entry := s.newEntry(models.OpRestoreCheckpoint)
+	entry.CheckpointID = checkpointID
+	defer func() { s.record(entry, err) }()
+
 	// Get checkpoint
 	checkpoint, err := s.GetCheckpoint(checkpointID)
 	if err != nil {
 		return err
 	}
+	entry.CheckpointID = checkpoint.ID
+	entry.GameID = checkpoint.GameID
+	entry.Detail = checkpoint.Name
 
 	// Get game
 	game, err := s.GetGame(checkpoint.GameID)
 	if err != nil {
 		return err
 	}
+	if game.Archived {
+		return models.ErrGameArchived
+	}
+	if !checkpoint.Codec.Known() {
+		return fmt.Errorf("%w: %s", models.ErrUnsupportedCodec, checkpoint.Codec)
+	}
+
+	hc := s.newHookContext(game, checkpoint)
+	defer func() { s.runPostHooks(models.HookPostRestore, game, hc, err) }()
+	if err := s.runPreHooks(models.HookPreRestore, game, hc); err != nil {
+		return err
+	}
 
-	// Verify checkpoint integrity
-	if err := s.vaultMgr.VerifyCheckpoint(checkpoint.VaultFile, checkpoint.Hash); err != nil {
+	// Verify checkpoint integrity; settings that can't be read just mean
+	// a full check
+	settings, settingsErr := s.GetSettings()
+	useCache := settingsErr == nil && settings.CacheVerification
+	if err := s.vaultMgr.VerifyCheckpointContext(ctx, checkpoint.VaultFile, checkpoint.Hash, useCache); err != nil {
+		if ctx.Err() != nil {
+			return err
+		}
+		s.publish(models.Event{
+			Type:         models.EventVerifyFailed,
+			GameID:       game.ID,
+			CheckpointID: checkpoint.ID,
+			Checkpoint:   checkpoint,
+			Err:          err,
+		})
 		return fmt.Errorf("checkpoint verification failed: %w", err)
 	}
 
 	// Restore
-	if err := s.vaultMgr.RestoreCheckpoint(checkpoint.VaultFile, game.SavePath); err != nil {
+	limits := s.archiveLimits()
+	opts := vault.RestoreOptions{
+		Limits:   &limits,
+		Progress: s.progressFunc(models.OpRestoreCheckpoint, game.ID, report),
+		SkippedLink: func(name, target string) {
+			s.publish(models.Event{
+				Type:         models.EventLinkSkipped,
+				GameID:       game.ID,
+				CheckpointID: checkpoint.ID,
+				Err:          fmt.Errorf("%w: skipped %s -> %s", models.ErrUnsafeLink, name, target),
+			})
+		},
+	}
+	if err := s.vaultMgr.RestoreCheckpointContext(ctx, checkpoint.VaultFile, game.SavePath, opts); err != nil {
 		return fmt.Errorf("failed to restore checkpoint: %w", err)
 	}
 
+	// Checkpoints made from here on branch off the restored one
+	s.setHead(game.ID, checkpoint.ID)
+
+	s.publish(models.Event{
+		Type:         models.EventCheckpointRestored,
+		GameID:       game.ID,
+		CheckpointID: checkpoint.ID,
+		Checkpoint:   checkpoint,
+	})
 	return nil
 }
 
-// DeleteCheckpoint removes a checkpoint
-func (s *Service) DeleteCheckpoint(checkpointID string) error {
+// DeleteCheckpoint moves a checkpoint to the trash. Protected checkpoints
+// are refused unless force is set.
+func (s *Service) DeleteCheckpoint(checkpointID string, force bool) (err error) {
+	entry := s.newEntry(models.OpDeleteCheckpoint)
+	entry.CheckpointID = checkpointID
+	defer func() { s.record(entry, err) }()
+
 	// Get checkpoint
 	checkpoint, err := s.GetCheckpoint(checkpointID)
 	if err != nil {
 		return err
 	}
+	entry.CheckpointID = checkpoint.ID
+	entry.GameID = checkpoint.GameID
+	entry.Detail = checkpoint.Name
+
+	if checkpoint.Protected && !force {
+		return models.ErrCheckpointProtected
+	}
+
+	// Global hooks still run for a checkpoint whose game can't be found
+	game, gameErr := s.GetGame(checkpoint.GameID)
+	if gameErr != nil {
+		game = &models.Game{ID: checkpoint.GameID}
+	}
+	hc := s.newHookContext(game, checkpoint)
+	defer func() { s.runPostHooks(models.HookPostDelete, game, hc, err) }()
+	if err := s.runPreHooks(models.HookPreDelete, game, hc); err != nil {
+		return err
+	}
+
+	// Drop anything past its expiry while we're here
+	if _, err := s.PurgeExpiredTrash(); err != nil {
+		return err
+	}
 
 	// Load all checkpoints
 	checkpoints, err := s.store.LoadCheckpoints()
@@ -247,8 +765,18 @@ func (s *Service) DeleteCheckpoint(checkpointID string) error {
 		return fmt.Errorf("failed to load checkpoints: %w", err)
 	}
 
+	// Move archive and metadata to the trash; a checkpoint whose archive is
+	// already gone has nothing to recover, so it is simply dropped
+	trashed := true
+	if err := s.moveToTrash([]models.Checkpoint{*checkpoint}, nil); err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		trashed = false
+	}
+
 	// Remove from list
-	var updatedCheckpoints []models.Checkpoint
+	updatedCheckpoints := []models.Checkpoint{}
 	for _, cp := range checkpoints {
 		if cp.ID != checkpoint.ID {
 			updatedCheckpoints = append(updatedCheckpoints, cp)
@@ -257,43 +785,19 @@ func (s *Service) DeleteCheckpoint(checkpointID string) error {
 
 	// Save updated list
 	if err := s.store.SaveCheckpoints(updatedCheckpoints); err != nil {
+		if trashed {
+			s.undoMoveToTrash([]models.Checkpoint{*checkpoint}) // Roll back
+		}
 		return fmt.Errorf("failed to save checkpoints: %w", err)
 	}
 
-	// Delete vault file
-	if err := s.vaultMgr.DeleteCheckpoint(checkpoint.VaultFile); err != nil {
-		// Log but don't fail if vault file doesn't exist
-		return fmt.Errorf("warning: failed to delete vault file: %w", err)
-	}
+	s.moveHeadOff(checkpoint)
 
+	s.publish(models.Event{
+		Type:         models.EventCheckpointDeleted,
+		GameID:       checkpoint.GameID,
+		CheckpointID: checkpoint.ID,
+		Checkpoint:   checkpoint,
+	})
 	return nil
 }
-
-// sanitizeID creates a safe ID from a name
-func (s *Service) sanitizeID(name string) string {
-	// Convert to lowercase
-	id := strings.ToLower(name)
-	
-	// Replace spaces and special chars with underscores
-	id = strings.Map(func(r rune) rune {
-		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
-			return r
-		}
-		return '_'
-	}, id)
-	
-	// Remove consecutive underscores
-	for strings.Contains(id, "__") {
-		id = strings.ReplaceAll(id, "__", "_")
-	}
-	
-	// Trim underscores from edges
-	id = strings.Trim(id, "_")
-	
-	// Limit length
-	if len(id) > 50 {
-		id = id[:50]
-	}
-	
-	return id
-}
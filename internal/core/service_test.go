@@ -0,0 +1,363 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+
+	"github.com/adrielfilipedesign/gamekeep/internal/fsys"
+	"github.com/adrielfilipedesign/gamekeep/internal/models"
+	"github.com/adrielfilipedesign/gamekeep/internal/storage"
+	"github.com/adrielfilipedesign/gamekeep/internal/vault"
+)
+
+// newTestService creates a service on a fault-injectable in-memory
+// filesystem with one registered game whose saves live in /saves
+func newTestService(t *testing.T) (*Service, *fsys.FaultFS, *models.Game) {
+	t.Helper()
+
+	fs := fsys.NewFaultFS(fsys.NewMemFS())
+	writeSave(t, fs, "/saves/slot1.sav", "level=12")
+
+	store, err := storage.NewJSONStoreWithFS(fs, "/home/.gamekeep/config")
+	if err != nil {
+		t.Fatalf("NewJSONStoreWithFS: %v", err)
+	}
+	vaultMgr, err := vault.NewManagerWithFS(fs, "/home/.gamekeep/vault")
+	if err != nil {
+		t.Fatalf("NewManagerWithFS: %v", err)
+	}
+
+	service := NewService(store, vaultMgr)
+	game, err := service.AddGame("Test Game", "/saves")
+	if err != nil {
+		t.Fatalf("AddGame: %v", err)
+	}
+
+	return service, fs, game
+}
+
+func writeSave(t *testing.T, fs fsys.FS, path, content string) {
+	t.Helper()
+
+	if err := fs.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("MkdirAll: %v", err)
+	}
+	if err := fsys.WriteFile(fs, path, []byte(content), 0644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+}
+
+func readSave(t *testing.T, fs fsys.FS, path string) string {
+	t.Helper()
+
+	data, err := fsys.ReadFile(fs, path)
+	if err != nil {
+		t.Fatalf("ReadFile %s: %v", path, err)
+	}
+	return string(data)
+}
+
+func TestCreateAndRestoreCheckpoint(t *testing.T) {
+	service, fs, game := newTestService(t)
+
+	cp, err := service.CreateCheckpoint(game.ID, "Before boss", "")
+	if err != nil {
+		t.Fatalf("CreateCheckpoint: %v", err)
+	}
+
+	writeSave(t, fs, "/saves/slot1.sav", "level=1")
+
+	if err := service.RestoreCheckpoint(cp.ID); err != nil {
+		t.Fatalf("RestoreCheckpoint: %v", err)
+	}
+	if got := readSave(t, fs, "/saves/slot1.sav"); got != "level=12" {
+		t.Errorf("slot1.sav = %q, want %q", got, "level=12")
+	}
+}
+
+func TestCreateCheckpointArchiveFailureSavesNoMetadata(t *testing.T) {
+	service, fs, game := newTestService(t)
+	fs.Inject(fsys.Fault{Op: fsys.OpWrite, Path: ".zip", After: 16, Err: syscall.ENOSPC})
+
+	if _, err := service.CreateCheckpoint(game.ID, "Doomed", ""); !errors.Is(err, syscall.ENOSPC) {
+		t.Fatalf("expected ENOSPC, got %v", err)
+	}
+
+	checkpoints, err := service.ListCheckpoints(game.ID)
+	if err != nil {
+		t.Fatalf("ListCheckpoints: %v", err)
+	}
+	if len(checkpoints) != 0 {
+		t.Fatalf("expected no checkpoints, got %d", len(checkpoints))
+	}
+}
+
+func TestCreateCheckpointMetadataFailureRemovesArchive(t *testing.T) {
+	service, fs, game := newTestService(t)
+	fs.Inject(fsys.Fault{Op: fsys.OpCreate, Path: "checkpoints.json", Err: syscall.ENOSPC})
+
+	if _, err := service.CreateCheckpoint(game.ID, "Doomed", ""); !errors.Is(err, syscall.ENOSPC) {
+		t.Fatalf("expected ENOSPC, got %v", err)
+	}
+
+	entries, err := fs.ReadDir(filepath.Join("/home/.gamekeep/vault", game.ID))
+	if err != nil {
+		t.Fatalf("ReadDir: %v", err)
+	}
+	if len(entries) != 0 {
+		t.Fatalf("expected vault to be cleaned up, found %d files", len(entries))
+	}
+}
+
+func TestRestoreRefusesCorruptedCheckpoint(t *testing.T) {
+	service, fs, game := newTestService(t)
+
+	cp, err := service.CreateCheckpoint(game.ID, "Before boss", "")
+	if err != nil {
+		t.Fatalf("CreateCheckpoint: %v", err)
+	}
+
+	// Corrupt the archive in the vault
+	archive := filepath.Join("/home/.gamekeep/vault", cp.VaultFile)
+	file, err := fs.OpenFile(archive, os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatalf("OpenFile: %v", err)
+	}
+	file.Write([]byte("corrupted"))
+	file.Close()
+
+	writeSave(t, fs, "/saves/slot1.sav", "level=50")
+
+	if err := service.RestoreCheckpoint(cp.ID); err == nil {
+		t.Fatal("expected verification to fail")
+	}
+	if got := readSave(t, fs, "/saves/slot1.sav"); got != "level=50" {
+		t.Errorf("save changed to %q despite failed verification", got)
+	}
+}
+
+func TestRestoreExtractionFailureKeepsSaves(t *testing.T) {
+	service, fs, game := newTestService(t)
+
+	cp, err := service.CreateCheckpoint(game.ID, "Before boss", "")
+	if err != nil {
+		t.Fatalf("CreateCheckpoint: %v", err)
+	}
+
+	writeSave(t, fs, "/saves/slot1.sav", "level=50")
+	fs.Inject(fsys.Fault{Op: fsys.OpWrite, Path: "gamekeep-restore", After: 0, Err: syscall.ENOSPC})
+
+	if err := service.RestoreCheckpoint(cp.ID); !errors.Is(err, syscall.ENOSPC) {
+		t.Fatalf("expected ENOSPC, got %v", err)
+	}
+	fs.Reset()
+
+	if got := readSave(t, fs, "/saves/slot1.sav"); got != "level=50" {
+		t.Errorf("save changed to %q after failed restore", got)
+	}
+}
+
+func TestUpdateGameKeepsCheckpointsAttached(t *testing.T) {
+	service, fs, game := newTestService(t)
+
+	cp, err := service.CreateCheckpoint(game.ID, "Before move", "")
+	if err != nil {
+		t.Fatalf("CreateCheckpoint: %v", err)
+	}
+
+	if _, err := service.AddGame("Other Game", "/other"); err != nil {
+		t.Fatalf("AddGame: %v", err)
+	}
+
+	taken := "other game"
+	if _, err := service.UpdateGame(game.ID, models.GameUpdate{Name: &taken}); !errors.Is(err, models.ErrGameExists) {
+		t.Fatalf("expected ErrGameExists, got %v", err)
+	}
+
+	name := "Renamed Game"
+	newPath := "/new-saves/"
+	updated, err := service.UpdateGame(game.ID, models.GameUpdate{Name: &name, SavePath: &newPath})
+	if err != nil {
+		t.Fatalf("UpdateGame: %v", err)
+	}
+	if updated.ID != game.ID || updated.SavePath != "/new-saves" {
+		t.Fatalf("unexpected game after update: %+v", updated)
+	}
+
+	checkpoints, err := service.ListCheckpoints(updated.ID)
+	if err != nil || len(checkpoints) != 1 || checkpoints[0].ID != cp.ID {
+		t.Fatalf("checkpoints not attached after update: %+v, %v", checkpoints, err)
+	}
+
+	// Restoring now targets the new save path
+	if err := service.RestoreCheckpoint(cp.ID); err != nil {
+		t.Fatalf("RestoreCheckpoint: %v", err)
+	}
+	if got := readSave(t, fs, "/new-saves/slot1.sav"); got != "level=12" {
+		t.Errorf("restored save = %q, want %q", got, "level=12")
+	}
+}
+
+func TestUpdateCheckpointValidates(t *testing.T) {
+	service, _, game := newTestService(t)
+
+	cp, err := service.CreateCheckpoint(game.ID, "Original", "note")
+	if err != nil {
+		t.Fatalf("CreateCheckpoint: %v", err)
+	}
+
+	blank := "  "
+	if _, err := service.UpdateCheckpoint(cp.ID, models.CheckpointUpdate{Name: &blank}); !errors.Is(err, models.ErrEmptyCheckpointName) {
+		t.Fatalf("expected ErrEmptyCheckpointName, got %v", err)
+	}
+
+	empty := ""
+	updated, err := service.UpdateCheckpoint(cp.ID, models.CheckpointUpdate{Note: &empty})
+	if err != nil {
+		t.Fatalf("UpdateCheckpoint: %v", err)
+	}
+	if updated.Name != "Original" || updated.Note != "" {
+		t.Fatalf("unexpected checkpoint after update: %+v", updated)
+	}
+}
+
+func TestGetGameAmbiguousMatch(t *testing.T) {
+	service, _, _ := newTestService(t)
+
+	if _, err := service.AddGame("Test Game Plus", "/plus"); err != nil {
+		t.Fatalf("AddGame: %v", err)
+	}
+
+	// Exact IDs still win over partial matches
+	if game, err := service.GetGame("test_game"); err != nil || game.ID != "test_game" {
+		t.Fatalf("GetGame exact = %+v, %v", game, err)
+	}
+
+	_, err := service.GetGame("game")
+	var ambiguous *models.AmbiguousError
+	if !errors.As(err, &ambiguous) || !errors.Is(err, models.ErrAmbiguous) {
+		t.Fatalf("expected AmbiguousError, got %v", err)
+	}
+	if len(ambiguous.Candidates) != 2 {
+		t.Fatalf("expected 2 candidates, got %+v", ambiguous.Candidates)
+	}
+
+	if game, err := service.GetGame("plus"); err != nil || game.Name != "Test Game Plus" {
+		t.Fatalf("GetGame unique partial = %+v, %v", game, err)
+	}
+}
+
+func TestGetCheckpointPrefix(t *testing.T) {
+	service, _, game := newTestService(t)
+
+	for _, name := range []string{"One", "Two"} {
+		if _, err := service.CreateCheckpointWithOptions(game.ID, name, "", models.CheckpointOptions{Force: true}); err != nil {
+			t.Fatalf("CreateCheckpoint: %v", err)
+		}
+	}
+
+	// Give both checkpoints IDs sharing a long prefix
+	checkpoints, _ := service.store.LoadCheckpoints()
+	checkpoints[0].ID = "abcdef00-0000-0000-0000-000000000001"
+	checkpoints[1].ID = "abcdef11-0000-0000-0000-000000000002"
+	service.store.SaveCheckpoints(checkpoints)
+
+	if _, err := service.GetCheckpoint("a"); !errors.Is(err, models.ErrCheckpointIDTooShort) {
+		t.Fatalf("expected ErrCheckpointIDTooShort, got %v", err)
+	}
+
+	_, err := service.GetCheckpoint("abcdef")
+	var ambiguous *models.AmbiguousError
+	if !errors.As(err, &ambiguous) || len(ambiguous.Candidates) != 2 {
+		t.Fatalf("expected AmbiguousError with 2 candidates, got %v", err)
+	}
+	if err := service.RestoreCheckpoint("abcdef"); !errors.Is(err, models.ErrAmbiguous) {
+		t.Fatalf("restore with ambiguous prefix: expected ErrAmbiguous, got %v", err)
+	}
+
+	if cp, err := service.GetCheckpoint("abcdef11"); err != nil || cp.Name != "Two" {
+		t.Fatalf("GetCheckpoint unique prefix = %+v, %v", cp, err)
+	}
+	if _, err := service.GetCheckpoint("ffff"); !errors.Is(err, models.ErrCheckpointNotFound) {
+		t.Fatalf("expected ErrCheckpointNotFound, got %v", err)
+	}
+}
+
+func TestCreateCheckpointContext(t *testing.T) {
+	service, _, game := newTestService(t)
+
+	var published []models.Progress
+	service.Subscribe(func(event models.Event) {
+		published = append(published, *event.Progress)
+	}, models.EventProgress)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := service.CreateCheckpointContext(ctx, game.ID, "Cancelled", "", models.CheckpointOptions{}); !errors.Is(err, context.Canceled) {
+		t.Fatalf("CreateCheckpointContext with cancelled context = %v, want context.Canceled", err)
+	}
+	if checkpoints, _ := service.ListCheckpoints(game.ID); len(checkpoints) != 0 {
+		t.Fatalf("cancelled checkpoint was created")
+	}
+
+	var reported []models.Progress
+	opts := models.CheckpointOptions{Progress: func(p models.Progress) { reported = append(reported, p) }}
+	if _, err := service.CreateCheckpointContext(context.Background(), game.ID, "Done", "", opts); err != nil {
+		t.Fatalf("CreateCheckpointContext: %v", err)
+	}
+	if len(reported) == 0 || len(published) != len(reported) {
+		t.Fatalf("reported %d progress updates and published %d", len(reported), len(published))
+	}
+	if last := reported[len(reported)-1]; last.Operation != models.OpCreateCheckpoint || last.Fraction() != 1 {
+		t.Fatalf("last progress = %+v, want create-checkpoint done", last)
+	}
+}
+
+func TestGameCompression(t *testing.T) {
+	service, fs, game := newTestService(t)
+
+	invalid := models.Compression{Codec: models.CodecDeflate, Level: 12}
+	if _, err := service.UpdateGame(game.ID, models.GameUpdate{Compression: &invalid}); !errors.Is(err, models.ErrInvalidCompression) {
+		t.Fatalf("UpdateGame with deflate level 12 = %v, want ErrInvalidCompression", err)
+	}
+
+	zstd := models.Compression{Codec: models.CodecZstd, Level: 3}
+	if _, err := service.UpdateGame(game.ID, models.GameUpdate{Compression: &zstd}); err != nil {
+		t.Fatalf("UpdateGame: %v", err)
+	}
+	cp, err := service.CreateCheckpoint(game.ID, "Zstd", "")
+	if err != nil {
+		t.Fatalf("CreateCheckpoint: %v", err)
+	}
+	if cp.Codec != models.CodecZstd {
+		t.Fatalf("checkpoint codec = %q, want zstd", cp.Codec)
+	}
+	writeSave(t, fs, "/saves/slot1.sav", "level=13")
+	if err := service.RestoreCheckpoint(cp.ID); err != nil {
+		t.Fatalf("RestoreCheckpoint: %v", err)
+	}
+
+	updated, err := service.UpdateGame(game.ID, models.GameUpdate{Compression: &models.Compression{}})
+	if err != nil {
+		t.Fatalf("UpdateGame: %v", err)
+	}
+	if updated.Compression != nil {
+		t.Fatalf("compression = %+v after going back to the default", updated.Compression)
+	}
+
+	// A checkpoint from a newer version with a codec this one can't read
+	checkpoints, _ := service.store.LoadCheckpoints()
+	checkpoints[0].Codec = "brotli"
+	service.store.SaveCheckpoints(checkpoints)
+	writeSave(t, fs, "/saves/slot1.sav", "level=14")
+	if err := service.RestoreCheckpoint(cp.ID); !errors.Is(err, models.ErrUnsupportedCodec) {
+		t.Fatalf("RestoreCheckpoint of a brotli checkpoint = %v, want ErrUnsupportedCodec", err)
+	}
+	if got := readSave(t, fs, "/saves/slot1.sav"); got != "level=14" {
+		t.Fatalf("slot1.sav = %q after a refused restore", got)
+	}
+}
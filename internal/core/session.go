@@ -0,0 +1,158 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/adrielfilipedesign/gamekeep/internal/launcher"
+	"github.com/adrielfilipedesign/gamekeep/internal/models"
+)
+
+// sessionTag marks the checkpoints RunGame takes
+const sessionTag = "session"
+
+// gameProcess is a started game that can be waited on
+type gameProcess interface {
+	Wait() (exitCode int, err error)
+}
+
+// startProcess starts a game with the launcher
+func startProcess(command []string) (gameProcess, error) {
+	return launcher.Start(command)
+}
+
+// RunGame runs a game's command, checkpointing the saves before launch and
+// again after exit if they changed, and records the session's playtime.
+// Checkpoint and bookkeeping problems go to opts.OnWarning and never keep
+// the game from running; even an unknown game still runs. The error is
+// only for a command that could not be started or waited on.
+func (s *Service) RunGame(gameIdentifier string, command []string, opts models.RunOptions) (_ *models.Session, err error) {
+	warn := func(format string, args ...interface{}) {
+		if opts.OnWarning != nil {
+			opts.OnWarning(fmt.Errorf(format, args...))
+		}
+	}
+
+	session := &models.Session{}
+
+	var game *models.Game
+	if gameIdentifier == "" {
+		warn("no game given, not tracking this session")
+	} else if game, err = s.GetGame(gameIdentifier); err != nil {
+		warn("not tracking this session: %w", err)
+		game = nil
+	} else {
+		session.GameID = game.ID
+	}
+
+	entry := s.newEntry(models.OpRunGame)
+	entry.GameID = session.GameID
+	defer func() {
+		if game != nil {
+			s.record(entry, err)
+		}
+	}()
+
+	var before string
+	if game != nil {
+		fingerprint, err := saveFingerprint(s.vaultMgr.FS(), game.SavePath)
+		if err != nil {
+			warn("failed to read saves: %w", err)
+		}
+		before = fingerprint
+
+		if !opts.SkipPreCheckpoint {
+			cp, err := s.createSessionCheckpoint(game, "Before playing", "Taken automatically before launch")
+			if errors.Is(err, models.ErrNoChanges) {
+				// Already safe in the latest checkpoint
+			} else if err != nil {
+				warn("failed to create checkpoint before launch: %w", err)
+			} else {
+				session.PreCheckpointID = cp.ID
+			}
+		}
+	}
+
+	session.StartedAt = time.Now().UTC()
+	process, err := s.startGame(command)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start game: %w", err)
+	}
+
+	session.ExitCode, err = process.Wait()
+	session.EndedAt = time.Now().UTC()
+	if err != nil {
+		return nil, fmt.Errorf("failed to wait for game: %w", err)
+	}
+
+	if game == nil {
+		return session, nil
+	}
+
+	played := session.Duration().Round(time.Second)
+	entry.Detail = fmt.Sprintf("played %s, exit code %d", played, session.ExitCode)
+
+	if err := s.RecordSession(game.ID, session); err != nil {
+		warn("failed to record playtime: %w", err)
+	}
+
+	// If the saves couldn't be read before, assume they changed
+	after, fpErr := saveFingerprint(s.vaultMgr.FS(), game.SavePath)
+	session.SavesChanged = before == "" || fpErr != nil || after != before
+
+	if session.SavesChanged && !opts.SkipPostCheckpoint {
+		cp, err := s.createSessionCheckpoint(game, "After playing", fmt.Sprintf("Taken automatically after playing for %s", played))
+		if errors.Is(err, models.ErrNoChanges) {
+			// Written back, but with the same contents
+			session.SavesChanged = false
+		} else if err != nil {
+			warn("failed to create checkpoint after exit: %w", err)
+		} else {
+			session.PostCheckpointID = cp.ID
+		}
+	}
+
+	return session, nil
+}
+
+// createSessionCheckpoint takes a checkpoint tagged as part of a session
+func (s *Service) createSessionCheckpoint(game *models.Game, name, note string) (*models.Checkpoint, error) {
+	cp, err := s.CreateCheckpoint(game.ID, name, note)
+	if err != nil {
+		return nil, err
+	}
+
+	// Best effort: the checkpoint is what matters, not the tag
+	if tagged, err := s.AddCheckpointTags(cp.ID, sessionTag); err == nil {
+		cp = tagged
+	}
+	return cp, nil
+}
+
+// RecordSession adds a finished play session to a game's playtime and
+// last played time
+func (s *Service) RecordSession(gameIdentifier string, session *models.Session) error {
+	game, err := s.GetGame(gameIdentifier)
+	if err != nil {
+		return err
+	}
+
+	games, err := s.store.LoadGames()
+	if err != nil {
+		return fmt.Errorf("failed to load games: %w", err)
+	}
+
+	ended := session.EndedAt
+	for i := range games {
+		if games[i].ID == game.ID {
+			games[i].PlaytimeSeconds += int64(session.Duration().Seconds())
+			games[i].LastPlayedAt = &ended
+		}
+	}
+
+	if err := s.store.SaveGames(games); err != nil {
+		return fmt.Errorf("failed to save games: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package core
+
+import (
+	"syscall"
+	"testing"
+
+	"github.com/adrielfilipedesign/gamekeep/internal/fsys"
+	"github.com/adrielfilipedesign/gamekeep/internal/models"
+)
+
+// fakeProcess stands in for a game, running play when waited on
+type fakeProcess struct {
+	play     func()
+	exitCode int
+}
+
+func (p *fakeProcess) Wait() (int, error) {
+	if p.play != nil {
+		p.play()
+	}
+	return p.exitCode, nil
+}
+
+func TestRunGame(t *testing.T) {
+	service, fs, game := newTestService(t)
+
+	var launched [][]string
+	play := func() {}
+	service.startGame = func(command []string) (gameProcess, error) {
+		launched = append(launched, command)
+		return &fakeProcess{play: play, exitCode: 3}, nil
+	}
+
+	var warnings []error
+	opts := models.RunOptions{OnWarning: func(err error) { warnings = append(warnings, err) }}
+
+	play = func() { writeSave(t, fs, "/saves/slot1.sav", "level=13") }
+	session, err := service.RunGame(game.ID, []string{"game", "-windowed"}, opts)
+	if err != nil {
+		t.Fatalf("RunGame: %v", err)
+	}
+	if session.ExitCode != 3 || !session.SavesChanged || session.PreCheckpointID == "" || session.PostCheckpointID == "" {
+		t.Fatalf("unexpected session: %+v", session)
+	}
+	post, err := service.GetCheckpoint(session.PostCheckpointID)
+	if err != nil || post.ParentID != session.PreCheckpointID || !post.HasTag(sessionTag) {
+		t.Fatalf("post-session checkpoint = %+v, %v", post, err)
+	}
+
+	// Nothing changed, so there is nothing to checkpoint afterwards
+	play = func() {}
+	session, err = service.RunGame(game.ID, []string{"game"}, opts)
+	if err != nil || session.SavesChanged || session.PostCheckpointID != "" {
+		t.Fatalf("unchanged session = %+v, %v", session, err)
+	}
+
+	// Checkpoint failures are warnings; the game still runs
+	writeSave(t, fs, "/saves/slot1.sav", "level=14")
+	fs.Inject(fsys.Fault{Op: fsys.OpCreate, Path: ".zip", Err: syscall.ENOSPC})
+	session, err = service.RunGame(game.ID, []string{"game"}, opts)
+	fs.Reset()
+	if err != nil || session.PreCheckpointID != "" {
+		t.Fatalf("session with failing checkpoint = %+v, %v", session, err)
+	}
+	if len(warnings) != 1 {
+		t.Fatalf("warnings = %v, want one", warnings)
+	}
+
+	// An unknown game still runs, untracked
+	warnings = nil
+	session, err = service.RunGame("no such game", []string{"game"}, opts)
+	if err != nil || session.GameID != "" || len(warnings) != 1 {
+		t.Fatalf("untracked session = %+v, %v, warnings %v", session, err, warnings)
+	}
+
+	if len(launched) != 4 || launched[0][1] != "-windowed" {
+		t.Fatalf("launched = %q", launched)
+	}
+
+	updated, _ := service.GetGame(game.ID)
+	if updated.LastPlayedAt == nil {
+		t.Fatal("last played time not recorded")
+	}
+}
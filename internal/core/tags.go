@@ -0,0 +1,67 @@
+package core
+
+import (
+	"sort"
+
+	"github.com/adrielfilipedesign/gamekeep/internal/models"
+)
+
+// AddCheckpointTags adds tags to a checkpoint, ignoring ones it already has
+func (s *Service) AddCheckpointTags(checkpointID string, tags ...string) (*models.Checkpoint, error) {
+	checkpoint, err := s.GetCheckpoint(checkpointID)
+	if err != nil {
+		return nil, err
+	}
+
+	updated := append(append([]string{}, checkpoint.Tags...), tags...)
+	return s.UpdateCheckpoint(checkpoint.ID, models.CheckpointUpdate{Tags: &updated})
+}
+
+// RemoveCheckpointTags removes tags from a checkpoint, ignoring ones it doesn't have
+func (s *Service) RemoveCheckpointTags(checkpointID string, tags ...string) (*models.Checkpoint, error) {
+	checkpoint, err := s.GetCheckpoint(checkpointID)
+	if err != nil {
+		return nil, err
+	}
+
+	remove, err := models.NormalizeTags(tags)
+	if err != nil {
+		return nil, err
+	}
+	removed := models.Checkpoint{Tags: remove}
+
+	updated := []string{}
+	for _, tag := range checkpoint.Tags {
+		if !removed.HasTag(tag) {
+			updated = append(updated, tag)
+		}
+	}
+	return s.UpdateCheckpoint(checkpoint.ID, models.CheckpointUpdate{Tags: &updated})
+}
+
+// ListCheckpointsWithTag lists a game's checkpoints that have a tag; an
+// empty tag lists them all
+func (s *Service) ListCheckpointsWithTag(gameIdentifier, tag string) ([]models.Checkpoint, error) {
+	return s.QueryCheckpoints(gameIdentifier, models.CheckpointQuery{Tag: tag})
+}
+
+// ListTags returns the tags used by a game's checkpoints, sorted
+func (s *Service) ListTags(gameIdentifier string) ([]string, error) {
+	checkpoints, err := s.ListCheckpoints(gameIdentifier)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	tags := []string{}
+	for _, cp := range checkpoints {
+		for _, tag := range cp.Tags {
+			if !seen[tag] {
+				seen[tag] = true
+				tags = append(tags, tag)
+			}
+		}
+	}
+	sort.Strings(tags)
+	return tags, nil
+}
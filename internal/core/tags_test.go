@@ -0,0 +1,78 @@
+package core
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/adrielfilipedesign/gamekeep/internal/models"
+)
+
+func TestCheckpointTags(t *testing.T) {
+	service, _, game := newTestService(t)
+
+	boss, err := service.CreateCheckpoint(game.ID, "Boss", "")
+	if err != nil {
+		t.Fatalf("CreateCheckpoint: %v", err)
+	}
+	if _, err := service.CreateCheckpointWithOptions(game.ID, "Other", "", models.CheckpointOptions{Force: true}); err != nil {
+		t.Fatalf("CreateCheckpoint: %v", err)
+	}
+
+	cp, err := service.AddCheckpointTags(boss.ID, " Boss ", "100%", "boss")
+	if err != nil {
+		t.Fatalf("AddCheckpointTags: %v", err)
+	}
+	if want := []string{"boss", "100%"}; !reflect.DeepEqual(cp.Tags, want) {
+		t.Fatalf("tags = %q, want %q", cp.Tags, want)
+	}
+
+	if _, err := service.AddCheckpointTags(boss.ID, "a,b"); !errors.Is(err, models.ErrInvalidTag) {
+		t.Fatalf("expected ErrInvalidTag, got %v", err)
+	}
+
+	tagged, err := service.ListCheckpointsWithTag(game.ID, "BOSS")
+	if err != nil || len(tagged) != 1 || tagged[0].ID != boss.ID {
+		t.Fatalf("ListCheckpointsWithTag = %+v, %v", tagged, err)
+	}
+
+	cp, err = service.RemoveCheckpointTags(boss.ID, "boss", "missing")
+	if err != nil {
+		t.Fatalf("RemoveCheckpointTags: %v", err)
+	}
+	if want := []string{"100%"}; !reflect.DeepEqual(cp.Tags, want) {
+		t.Fatalf("tags = %q, want %q", cp.Tags, want)
+	}
+
+	if tags, _ := service.ListTags(game.ID); !reflect.DeepEqual(tags, []string{"100%"}) {
+		t.Fatalf("ListTags = %q", tags)
+	}
+}
+
+func TestTagsSurviveExportImport(t *testing.T) {
+	service, _, game := newTestService(t)
+
+	cp, err := service.CreateCheckpoint(game.ID, "Boss", "")
+	if err != nil {
+		t.Fatalf("CreateCheckpoint: %v", err)
+	}
+	if _, err := service.AddCheckpointTags(cp.ID, "boss", "pre-dlc"); err != nil {
+		t.Fatalf("AddCheckpointTags: %v", err)
+	}
+
+	opts := models.RemoveGameOptions{Mode: models.RemoveExport, ExportPath: "/bundle.zip", Permanent: true}
+	if _, err := service.RemoveGame(game.ID, opts); err != nil {
+		t.Fatalf("RemoveGame: %v", err)
+	}
+	if _, _, err := service.ImportBundle("/bundle.zip", ""); err != nil {
+		t.Fatalf("ImportBundle: %v", err)
+	}
+
+	imported, err := service.GetCheckpoint(cp.ID)
+	if err != nil {
+		t.Fatalf("GetCheckpoint: %v", err)
+	}
+	if want := []string{"boss", "pre-dlc"}; !reflect.DeepEqual(imported.Tags, want) {
+		t.Fatalf("imported tags = %q, want %q", imported.Tags, want)
+	}
+}
@@ -0,0 +1,338 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/adrielfilipedesign/gamekeep/internal/models"
+)
+
+// ListTrash returns the checkpoints currently in the trash, purging expired ones first
+func (s *Service) ListTrash() ([]models.TrashEntry, error) {
+	if _, err := s.PurgeExpiredTrash(); err != nil {
+		return nil, err
+	}
+
+	return s.store.LoadTrash()
+}
+
+// RestoreFromTrash moves a trashed checkpoint back into its game's checkpoint list
+func (s *Service) RestoreFromTrash(checkpointID string) (_ *models.Checkpoint, err error) {
+	journal := s.newEntry(models.OpRestoreFromTrash)
+	journal.CheckpointID = checkpointID
+	defer func() { s.record(journal, err) }()
+
+	entries, err := s.store.LoadTrash()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load trash: %w", err)
+	}
+
+	index, err := findTrashEntry(entries, checkpointID)
+	if err != nil {
+		return nil, err
+	}
+	entry := entries[index]
+	checkpoint := entry.Checkpoint
+	journal.CheckpointID = checkpoint.ID
+	journal.GameID = checkpoint.GameID
+	journal.Detail = checkpoint.Name
+
+	// The game must still be registered, or be registered again if it was
+	// removed along with this checkpoint
+	if _, err := s.GetGame(checkpoint.GameID); err != nil {
+		if !errors.Is(err, models.ErrGameNotFound) || entry.Game == nil {
+			return nil, err
+		}
+		if err := s.reregisterGame(*entry.Game); err != nil {
+			return nil, err
+		}
+	}
+
+	checkpoints, err := s.store.LoadCheckpoints()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load checkpoints: %w", err)
+	}
+
+	// It may have been imported again from a bundle since it was deleted
+	for _, cp := range checkpoints {
+		if cp.ID == checkpoint.ID {
+			return nil, models.ErrCheckpointExists
+		}
+	}
+
+	// Move archive back into the vault
+	if err := s.vaultMgr.UntrashCheckpoint(entry.TrashFile, checkpoint.VaultFile); err != nil {
+		return nil, err
+	}
+
+	// Re-add checkpoint
+	if err := s.store.SaveCheckpoints(append(checkpoints, checkpoint)); err != nil {
+		s.vaultMgr.TrashCheckpoint(checkpoint.VaultFile) // Roll back
+		return nil, fmt.Errorf("failed to save checkpoints: %w", err)
+	}
+
+	// Drop trash entry
+	remaining := append(entries[:index:index], entries[index+1:]...)
+	if err := s.store.SaveTrash(remaining); err != nil {
+		return nil, fmt.Errorf("failed to save trash: %w", err)
+	}
+
+	return &checkpoint, nil
+}
+
+// DeleteFromTrash permanently deletes a single trashed checkpoint
+func (s *Service) DeleteFromTrash(checkpointID string) (err error) {
+	journal := s.newEntry(models.OpDeleteFromTrash)
+	journal.CheckpointID = checkpointID
+	defer func() { s.record(journal, err) }()
+
+	entries, err := s.store.LoadTrash()
+	if err != nil {
+		return fmt.Errorf("failed to load trash: %w", err)
+	}
+
+	index, err := findTrashEntry(entries, checkpointID)
+	if err != nil {
+		return err
+	}
+	target := entries[index].Checkpoint.ID
+	journal.CheckpointID = target
+	journal.GameID = entries[index].Checkpoint.GameID
+	journal.Detail = entries[index].Checkpoint.Name
+
+	if _, err := s.purgeTrash(func(entry models.TrashEntry) bool {
+		return entry.Checkpoint.ID == target
+	}); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// reregisterGame adds back a game that was removed into the trash
+func (s *Service) reregisterGame(game models.Game) error {
+	games, err := s.store.LoadGames()
+	if err != nil {
+		return fmt.Errorf("failed to load games: %w", err)
+	}
+
+	for _, g := range games {
+		if g.ID == game.ID || strings.EqualFold(g.Name, game.Name) {
+			return gameExistsError(&g)
+		}
+	}
+
+	game.Archived = false
+	if err := s.store.SaveGames(append(games, game)); err != nil {
+		return fmt.Errorf("failed to save games: %w", err)
+	}
+
+	return nil
+}
+
+// EmptyTrash permanently deletes everything in the trash and returns how many
+// checkpoints were removed
+func (s *Service) EmptyTrash() (count int, err error) {
+	journal := s.newEntry(models.OpEmptyTrash)
+	defer func() {
+		journal.Detail = fmt.Sprintf("%d checkpoint(s)", count)
+		s.record(journal, err)
+	}()
+
+	return s.purgeTrash(func(models.TrashEntry) bool {
+		return true
+	})
+}
+
+// PurgeExpiredTrash permanently deletes trashed checkpoints past their expiry
+// and returns how many were removed. Protected checkpoints that were force
+// deleted never expire; only emptying the trash removes them.
+func (s *Service) PurgeExpiredTrash() (count int, err error) {
+	now := time.Now().UTC()
+	count, err = s.purgeTrash(func(entry models.TrashEntry) bool {
+		return entry.Expired(now) && !entry.Checkpoint.Protected
+	})
+
+	// Only journal purges that actually did something
+	if count > 0 || err != nil {
+		journal := s.newEntry(models.OpPurgeTrash)
+		journal.Detail = fmt.Sprintf("%d checkpoint(s)", count)
+		s.record(journal, err)
+	}
+
+	return count, err
+}
+
+// GetSettings returns the current application settings
+func (s *Service) GetSettings() (models.Settings, error) {
+	return s.store.LoadSettings()
+}
+
+// UpdateSettings validates and saves application settings
+func (s *Service) UpdateSettings(settings models.Settings) (err error) {
+	journal := s.newEntry(models.OpUpdateSettings)
+	defer func() { s.record(journal, err) }()
+
+	if err := settings.Validate(); err != nil {
+		return err
+	}
+
+	if err := s.store.SaveSettings(settings); err != nil {
+		return fmt.Errorf("failed to save settings: %w", err)
+	}
+
+	return nil
+}
+
+// moveToTrash moves checkpoint archives and metadata into the trash in one
+// step. game is recorded with the entries when the game itself is being
+// removed, so restoring from the trash can register it again.
+func (s *Service) moveToTrash(checkpoints []models.Checkpoint, game *models.Game) (err error) {
+	settings, err := s.store.LoadSettings()
+	if err != nil {
+		return fmt.Errorf("failed to load settings: %w", err)
+	}
+
+	entries, err := s.store.LoadTrash()
+	if err != nil {
+		return fmt.Errorf("failed to load trash: %w", err)
+	}
+
+	now := time.Now().UTC()
+	var moved []models.TrashEntry
+	defer func() {
+		if err != nil {
+			// Roll back archives already moved
+			for _, entry := range moved {
+				s.vaultMgr.UntrashCheckpoint(entry.TrashFile, entry.Checkpoint.VaultFile)
+			}
+		}
+	}()
+
+	for _, checkpoint := range checkpoints {
+		trashFile, err := s.vaultMgr.TrashCheckpoint(checkpoint.VaultFile)
+		if err != nil {
+			return err
+		}
+
+		entry := models.TrashEntry{
+			Checkpoint: checkpoint,
+			Game:       game,
+			TrashFile:  trashFile,
+			DeletedAt:  now,
+		}
+		// Protected checkpoints are kept until the trash is emptied
+		if settings.TrashRetentionDays > 0 && !checkpoint.Protected {
+			entry.ExpiresAt = now.AddDate(0, 0, settings.TrashRetentionDays)
+		}
+		moved = append(moved, entry)
+	}
+
+	if err := s.store.SaveTrash(append(entries, moved...)); err != nil {
+		return fmt.Errorf("failed to save trash: %w", err)
+	}
+
+	return nil
+}
+
+// undoMoveToTrash reverts moveToTrash after a later step failed
+func (s *Service) undoMoveToTrash(checkpoints []models.Checkpoint) {
+	trashed := make(map[string]bool, len(checkpoints))
+	for _, checkpoint := range checkpoints {
+		trashed[checkpoint.ID] = true
+	}
+
+	entries, err := s.store.LoadTrash()
+	if err != nil {
+		return
+	}
+
+	var remaining []models.TrashEntry
+	for _, entry := range entries {
+		if trashed[entry.Checkpoint.ID] {
+			s.vaultMgr.UntrashCheckpoint(entry.TrashFile, entry.Checkpoint.VaultFile)
+			continue
+		}
+		remaining = append(remaining, entry)
+	}
+	s.store.SaveTrash(remaining)
+}
+
+// purgeTrash permanently deletes the trash entries selected by match
+func (s *Service) purgeTrash(match func(models.TrashEntry) bool) (int, error) {
+	entries, err := s.store.LoadTrash()
+	if err != nil {
+		return 0, fmt.Errorf("failed to load trash: %w", err)
+	}
+
+	var remaining, purged []models.TrashEntry
+	for _, entry := range entries {
+		if !match(entry) {
+			remaining = append(remaining, entry)
+			continue
+		}
+
+		if err := s.vaultMgr.DeleteCheckpoint(entry.TrashFile); err != nil && !errors.Is(err, os.ErrNotExist) {
+			// Keep the entry so the file isn't orphaned
+			remaining = append(remaining, entry)
+			continue
+		}
+		purged = append(purged, entry)
+	}
+
+	if len(purged) == 0 {
+		return 0, nil
+	}
+
+	if remaining == nil {
+		remaining = []models.TrashEntry{}
+	}
+	if err := s.store.SaveTrash(remaining); err != nil {
+		return 0, fmt.Errorf("failed to save trash: %w", err)
+	}
+
+	s.deleteTrashedCovers(purged, remaining)
+	return len(purged), nil
+}
+
+// deleteTrashedCovers deletes the covers that purged entries kept for
+// removed games, once no remaining entry could register the game again
+// and no registered game uses them. Best effort, like any other stale
+// cover.
+func (s *Service) deleteTrashedCovers(purged, remaining []models.TrashEntry) {
+	inUse := map[string]bool{}
+	for _, entry := range remaining {
+		if entry.Game != nil {
+			inUse[entry.Game.CoverImage] = true
+		}
+	}
+	games, err := s.store.LoadGames()
+	if err != nil {
+		return
+	}
+	for _, game := range games {
+		inUse[game.CoverImage] = true
+	}
+
+	for _, entry := range purged {
+		if entry.Game == nil || entry.Game.CoverImage == "" || inUse[entry.Game.CoverImage] {
+			continue
+		}
+		s.vaultMgr.DeleteCover(entry.Game.CoverImage)
+		inUse[entry.Game.CoverImage] = true // So later entries skip it
+	}
+}
+
+// findTrashEntry returns the index of the entry matching a checkpoint ID or prefix
+func findTrashEntry(entries []models.TrashEntry, checkpointID string) (int, error) {
+	index, err := matchCheckpointID(checkpointID, len(entries), func(i int) models.Checkpoint {
+		return entries[i].Checkpoint
+	})
+	if errors.Is(err, errNoMatch) {
+		return -1, models.ErrTrashEntryNotFound
+	}
+	return index, err
+}
@@ -0,0 +1,123 @@
+package core
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/adrielfilipedesign/gamekeep/internal/models"
+)
+
+func TestDeleteAndRestoreFromTrash(t *testing.T) {
+	service, _, game := newTestService(t)
+
+	cp, err := service.CreateCheckpoint(game.ID, "Before boss", "")
+	if err != nil {
+		t.Fatalf("CreateCheckpoint: %v", err)
+	}
+
+	if err := service.DeleteCheckpoint(cp.ID, false); err != nil {
+		t.Fatalf("DeleteCheckpoint: %v", err)
+	}
+	if _, err := service.GetCheckpoint(cp.ID); !errors.Is(err, models.ErrCheckpointNotFound) {
+		t.Fatalf("deleted checkpoint still listed: %v", err)
+	}
+
+	entries, err := service.ListTrash()
+	if err != nil {
+		t.Fatalf("ListTrash: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Checkpoint.ID != cp.ID {
+		t.Fatalf("unexpected trash contents: %+v", entries)
+	}
+
+	if _, err := service.RestoreFromTrash(cp.ID); err != nil {
+		t.Fatalf("RestoreFromTrash: %v", err)
+	}
+
+	// The restored checkpoint must still be intact
+	if err := service.RestoreCheckpoint(cp.ID); err != nil {
+		t.Fatalf("RestoreCheckpoint after untrash: %v", err)
+	}
+	if entries, _ := service.ListTrash(); len(entries) != 0 {
+		t.Fatalf("trash not empty after restore: %+v", entries)
+	}
+}
+
+func TestPurgeExpiredTrash(t *testing.T) {
+	service, _, game := newTestService(t)
+
+	for _, name := range []string{"old", "new"} {
+		cp, err := service.CreateCheckpoint(game.ID, name, "")
+		if err != nil {
+			t.Fatalf("CreateCheckpoint: %v", err)
+		}
+		if err := service.DeleteCheckpoint(cp.ID, false); err != nil {
+			t.Fatalf("DeleteCheckpoint: %v", err)
+		}
+	}
+
+	// Backdate the first entry past its expiry
+	entries, _ := service.store.LoadTrash()
+	entries[0].ExpiresAt = time.Now().Add(-time.Hour)
+	service.store.SaveTrash(entries)
+
+	purged, err := service.PurgeExpiredTrash()
+	if err != nil {
+		t.Fatalf("PurgeExpiredTrash: %v", err)
+	}
+	if purged != 1 {
+		t.Fatalf("purged %d entries, want 1", purged)
+	}
+
+	remaining, _ := service.ListTrash()
+	if len(remaining) != 1 || remaining[0].Checkpoint.Name != "new" {
+		t.Fatalf("unexpected trash contents: %+v", remaining)
+	}
+
+	if n, err := service.EmptyTrash(); err != nil || n != 1 {
+		t.Fatalf("EmptyTrash = %d, %v; want 1, nil", n, err)
+	}
+}
+
+func TestProtectedCheckpoint(t *testing.T) {
+	service, _, game := newTestService(t)
+
+	cp, err := service.CreateCheckpoint(game.ID, "Keeper", "")
+	if err != nil {
+		t.Fatalf("CreateCheckpoint: %v", err)
+	}
+	protected := true
+	if _, err := service.UpdateCheckpoint(cp.ID, models.CheckpointUpdate{Protected: &protected}); err != nil {
+		t.Fatalf("UpdateCheckpoint: %v", err)
+	}
+
+	if err := service.DeleteCheckpoint(cp.ID, false); !errors.Is(err, models.ErrCheckpointProtected) {
+		t.Fatalf("expected ErrCheckpointProtected, got %v", err)
+	}
+	opts := models.RemoveGameOptions{Mode: models.RemoveDelete}
+	if _, err := service.RemoveGame(game.ID, opts); !errors.Is(err, models.ErrCheckpointProtected) {
+		t.Fatalf("RemoveGame: expected ErrCheckpointProtected, got %v", err)
+	}
+	if _, err := service.GetCheckpoint(cp.ID); err != nil {
+		t.Fatalf("protected checkpoint gone: %v", err)
+	}
+
+	if err := service.DeleteCheckpoint(cp.ID, true); err != nil {
+		t.Fatalf("forced DeleteCheckpoint: %v", err)
+	}
+
+	// Expiry never purges a protected checkpoint from the trash
+	entries, _ := service.store.LoadTrash()
+	if len(entries) != 1 || !entries[0].ExpiresAt.IsZero() {
+		t.Fatalf("trash = %+v, want the checkpoint without an expiry", entries)
+	}
+	entries[0].ExpiresAt = time.Now().Add(-time.Hour)
+	service.store.SaveTrash(entries)
+	if purged, err := service.PurgeExpiredTrash(); err != nil || purged != 0 {
+		t.Fatalf("PurgeExpiredTrash = %d, %v; want 0, nil", purged, err)
+	}
+	if entries, _ := service.ListTrash(); len(entries) != 1 {
+		t.Fatalf("trash = %+v, want the protected checkpoint kept", entries)
+	}
+}
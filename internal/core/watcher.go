@@ -0,0 +1,317 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/adrielfilipedesign/gamekeep/internal/models"
+	"github.com/adrielfilipedesign/gamekeep/internal/procwatch"
+)
+
+// commLength is how much of a process name the kernel keeps
+const commLength = 15
+
+// Watcher notices registered games starting and stopping by looking at the
+// running processes, records their sessions and checkpoints their saves
+// when they exit. Create one with Service.NewWatcher.
+type Watcher struct {
+	service *Service
+	opts    models.WatchOptions
+
+	mu      sync.Mutex
+	running map[string]*watchedGame
+}
+
+// watchedGame is a running game and the state of its saves at launch
+type watchedGame struct {
+	models.RunningGame
+	fingerprint string // Empty if the saves couldn't be read
+}
+
+// NewWatcher creates a game watcher. Nothing is watched until Poll or Run
+// is called.
+func (s *Service) NewWatcher(opts models.WatchOptions) *Watcher {
+	return &Watcher{
+		service: s,
+		opts:    opts,
+		running: make(map[string]*watchedGame),
+	}
+}
+
+// Run polls until stop is closed. Games still running when it returns are
+// not recorded. It only returns early if this system can't list processes;
+// other problems are reported as error events.
+func (w *Watcher) Run(stop <-chan struct{}) error {
+	for {
+		if err := w.Poll(); err != nil {
+			if errors.Is(err, procwatch.ErrUnsupported) {
+				return err
+			}
+			w.emit(models.WatchEvent{Type: models.WatchError, At: time.Now().UTC(), Err: err})
+		}
+
+		select {
+		case <-stop:
+			return nil
+		case <-time.After(w.interval()):
+		}
+	}
+}
+
+// interval returns the time to wait between polls
+func (w *Watcher) interval() time.Duration {
+	if w.opts.Interval > 0 {
+		return w.opts.Interval
+	}
+	settings, err := w.service.GetSettings()
+	if err != nil || settings.WatchIntervalSeconds < 1 {
+		return models.DefaultWatchIntervalSeconds * time.Second
+	}
+	return time.Duration(settings.WatchIntervalSeconds) * time.Second
+}
+
+// Poll looks at the running processes once, emitting an event for each
+// game that started or stopped since the last poll
+func (w *Watcher) Poll() error {
+	processes, err := w.service.listProcesses()
+	if err != nil {
+		return err
+	}
+
+	games, err := w.service.ListGames()
+	if err != nil {
+		return fmt.Errorf("failed to load games: %w", err)
+	}
+
+	now := time.Now().UTC()
+	seen := make(map[string]bool)
+	var started []models.WatchEvent
+	var stopped []*watchedGame
+
+	w.mu.Lock()
+	for i := range games {
+		game := &games[i]
+		pids := matchingPIDs(game, processes)
+		if len(pids) == 0 {
+			continue
+		}
+		seen[game.ID] = true
+
+		if run, ok := w.running[game.ID]; ok {
+			run.PIDs = pids
+			continue
+		}
+
+		// If the saves can't be read now, the fingerprint stays empty and
+		// they count as changed on exit
+		fingerprint, _ := saveFingerprint(w.service.vaultMgr.FS(), game.SavePath)
+		w.running[game.ID] = &watchedGame{
+			RunningGame: models.RunningGame{
+				GameID:    game.ID,
+				GameName:  game.Name,
+				PIDs:      pids,
+				StartedAt: now,
+			},
+			fingerprint: fingerprint,
+		}
+		started = append(started, models.WatchEvent{
+			Type:     models.WatchStarted,
+			GameID:   game.ID,
+			GameName: game.Name,
+			PIDs:     pids,
+			At:       now,
+		})
+	}
+	for id, run := range w.running {
+		if !seen[id] {
+			stopped = append(stopped, run)
+			delete(w.running, id)
+		}
+	}
+	w.mu.Unlock()
+
+	for _, event := range started {
+		w.emit(event)
+	}
+
+	sort.Slice(stopped, func(i, j int) bool {
+		return stopped[i].StartedAt.Before(stopped[j].StartedAt)
+	})
+	for _, run := range stopped {
+		w.emit(w.finish(run, now))
+	}
+
+	return nil
+}
+
+// finish records a session that ended and checkpoints the saves if they
+// changed. Problems are reported on the event rather than returned, since
+// the game has stopped either way.
+func (w *Watcher) finish(run *watchedGame, endedAt time.Time) (event models.WatchEvent) {
+	s := w.service
+	session := &models.Session{
+		GameID:    run.GameID,
+		StartedAt: run.StartedAt,
+		EndedAt:   endedAt,
+	}
+	event = models.WatchEvent{
+		Type:     models.WatchStopped,
+		GameID:   run.GameID,
+		GameName: run.GameName,
+		PIDs:     run.PIDs,
+		At:       endedAt,
+		Session:  session,
+	}
+
+	played := session.Duration().Round(time.Second)
+	entry := s.newEntry(models.OpPlaySession)
+	entry.GameID = run.GameID
+	entry.Detail = fmt.Sprintf("played %s, seen by the watcher", played)
+
+	var errs []error
+	defer func() {
+		event.Err = errors.Join(errs...)
+		s.record(entry, event.Err)
+	}()
+
+	game, err := s.GetGame(run.GameID)
+	if err != nil {
+		errs = append(errs, err)
+		return event
+	}
+
+	if err := s.RecordSession(game.ID, session); err != nil {
+		errs = append(errs, fmt.Errorf("failed to record playtime: %w", err))
+	}
+
+	after, fpErr := saveFingerprint(s.vaultMgr.FS(), game.SavePath)
+	session.SavesChanged = run.fingerprint == "" || fpErr != nil || after != run.fingerprint
+	if !session.SavesChanged || w.opts.SkipExitCheckpoint {
+		return event
+	}
+
+	settings, err := s.GetSettings()
+	if err != nil {
+		errs = append(errs, fmt.Errorf("failed to load settings: %w", err))
+		return event
+	}
+	if !settings.CheckpointOnExit {
+		return event
+	}
+
+	cp, err := s.createSessionCheckpoint(game, "After playing", fmt.Sprintf("Taken automatically when the game exited after %s", played))
+	if errors.Is(err, models.ErrNoChanges) {
+		// Written back, but with the same contents
+		session.SavesChanged = false
+	} else if err != nil {
+		errs = append(errs, fmt.Errorf("failed to create checkpoint after exit: %w", err))
+	} else {
+		session.PostCheckpointID = cp.ID
+	}
+	return event
+}
+
+// emit passes an event to the OnEvent callback, if any
+func (w *Watcher) emit(event models.WatchEvent) {
+	if w.opts.OnEvent != nil {
+		w.opts.OnEvent(event)
+	}
+}
+
+// Running returns the games seen running at the last poll, by name
+func (w *Watcher) Running() []models.RunningGame {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	running := make([]models.RunningGame, 0, len(w.running))
+	for _, run := range w.running {
+		game := run.RunningGame
+		game.PIDs = append([]int(nil), run.PIDs...)
+		running = append(running, game)
+	}
+	sort.Slice(running, func(i, j int) bool {
+		return strings.ToLower(running[i].GameName) < strings.ToLower(running[j].GameName)
+	})
+	return running
+}
+
+// IsRunning reports whether a game was seen running at the last poll
+func (w *Watcher) IsRunning(gameID string) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	_, ok := w.running[gameID]
+	return ok
+}
+
+// matchingPIDs returns the IDs of the processes that belong to a game
+func matchingPIDs(game *models.Game, processes []procwatch.Process) []int {
+	var pids []int
+	for i := range processes {
+		if matchesProcess(game, &processes[i]) {
+			pids = append(pids, processes[i].PID)
+		}
+	}
+	return pids
+}
+
+// matchesProcess reports whether a process belongs to a game: its
+// executable is the game's, or its executable, first argument or kernel
+// name matches the base name of the game's executable or one of the game's
+// process names, ignoring case. Games without either are never matched.
+func matchesProcess(game *models.Game, p *procwatch.Process) bool {
+	if game.Executable != "" && p.Exe != "" && filepath.Clean(game.Executable) == p.Exe {
+		return true
+	}
+
+	names := game.ProcessNames
+	if game.Executable != "" {
+		names = append([]string{procwatch.BaseName(game.Executable)}, names...)
+	}
+
+	candidates := []string{procwatch.BaseName(p.Exe)}
+	if len(p.Args) > 0 {
+		candidates = append(candidates, procwatch.BaseName(p.Args[0]))
+	}
+
+	for _, name := range names {
+		if name == "" {
+			continue
+		}
+		for _, candidate := range candidates {
+			if strings.EqualFold(name, candidate) {
+				return true
+			}
+		}
+
+		// The kernel name is cut short, so compare it to a cut name
+		comm := name
+		if len(comm) > commLength {
+			comm = comm[:commLength]
+		}
+		if p.Name != "" && strings.EqualFold(comm, p.Name) {
+			return true
+		}
+	}
+	return false
+}
+
+// cleanProcessNames trims process names and drops empty and repeated ones
+func cleanProcessNames(names []string) []string {
+	var cleaned []string
+	seen := make(map[string]bool)
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		key := strings.ToLower(name)
+		if name == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		cleaned = append(cleaned, name)
+	}
+	return cleaned
+}
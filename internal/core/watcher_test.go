@@ -0,0 +1,114 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/adrielfilipedesign/gamekeep/internal/models"
+	"github.com/adrielfilipedesign/gamekeep/internal/procwatch"
+)
+
+func TestMatchesProcess(t *testing.T) {
+	game := &models.Game{
+		Executable:   "/games/hollow/Hollow Knight.x86_64",
+		ProcessNames: []string{"HollowKnightLauncher.exe"},
+	}
+
+	tests := []struct {
+		name string
+		p    procwatch.Process
+		want bool
+	}{
+		{"exact path", procwatch.Process{Exe: "/games/hollow/Hollow Knight.x86_64"}, true},
+		{"same name elsewhere", procwatch.Process{Exe: "/other/hollow knight.X86_64"}, true},
+		{"wine argv0", procwatch.Process{Exe: "/usr/bin/wine64-preloader", Args: []string{`Z:\games\HollowKnightLauncher.exe`}}, true},
+		{"truncated comm", procwatch.Process{Name: "HollowKnightLau"}, true},
+		{"unrelated", procwatch.Process{Name: "bash", Exe: "/usr/bin/bash", Args: []string{"bash"}}, false},
+	}
+	for _, tt := range tests {
+		if got := matchesProcess(game, &tt.p); got != tt.want {
+			t.Errorf("%s: matchesProcess = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	if matchesProcess(&models.Game{}, &procwatch.Process{Name: "game"}) {
+		t.Error("a game without an executable or process names should never match")
+	}
+}
+
+func TestWatcher(t *testing.T) {
+	service, fs, game := newTestService(t)
+
+	names := []string{"game.bin"}
+	if _, err := service.UpdateGame(game.ID, models.GameUpdate{ProcessNames: &names}); err != nil {
+		t.Fatalf("UpdateGame: %v", err)
+	}
+
+	var processes []procwatch.Process
+	service.listProcesses = func() ([]procwatch.Process, error) {
+		return processes, nil
+	}
+
+	var events []models.WatchEvent
+	watcher := service.NewWatcher(models.WatchOptions{
+		OnEvent: func(e models.WatchEvent) { events = append(events, e) },
+	})
+
+	poll := func() {
+		t.Helper()
+		if err := watcher.Poll(); err != nil {
+			t.Fatalf("Poll: %v", err)
+		}
+	}
+
+	processes = []procwatch.Process{{PID: 42, Name: "game.bin"}}
+	poll()
+	poll()
+	if len(events) != 1 || events[0].Type != models.WatchStarted || events[0].GameID != game.ID {
+		t.Fatalf("events after start = %+v", events)
+	}
+	if !watcher.IsRunning(game.ID) || len(watcher.Running()) != 1 {
+		t.Fatal("game should be running")
+	}
+
+	writeSave(t, fs, "/saves/slot1.sav", "level=13")
+	processes = nil
+	poll()
+	if len(events) != 2 || events[1].Type != models.WatchStopped || events[1].Err != nil {
+		t.Fatalf("events after exit = %+v", events)
+	}
+	session := events[1].Session
+	if !session.SavesChanged || session.PostCheckpointID == "" {
+		t.Fatalf("unexpected session: %+v", session)
+	}
+	cp, err := service.GetCheckpoint(session.PostCheckpointID)
+	if err != nil || !cp.HasTag(sessionTag) {
+		t.Fatalf("exit checkpoint = %+v, %v", cp, err)
+	}
+	if watcher.IsRunning(game.ID) {
+		t.Fatal("game should have stopped")
+	}
+
+	updated, err := service.GetGame(game.ID)
+	if err != nil || updated.LastPlayedAt == nil {
+		t.Fatalf("session not recorded: %+v, %v", updated, err)
+	}
+
+	// With the setting off, nothing is checkpointed on exit
+	settings, err := service.GetSettings()
+	if err != nil {
+		t.Fatalf("GetSettings: %v", err)
+	}
+	settings.CheckpointOnExit = false
+	if err := service.UpdateSettings(settings); err != nil {
+		t.Fatalf("UpdateSettings: %v", err)
+	}
+
+	processes = []procwatch.Process{{PID: 43, Name: "game.bin"}}
+	poll()
+	writeSave(t, fs, "/saves/slot1.sav", "level=14")
+	processes = nil
+	poll()
+	if len(events) != 4 || events[3].Session.PostCheckpointID != "" || !events[3].Session.SavesChanged {
+		t.Fatalf("events with checkpoints off = %+v", events)
+	}
+}
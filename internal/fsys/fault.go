@@ -0,0 +1,276 @@
+package fsys
+
+import (
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Op identifies a filesystem operation for fault injection
+type Op string
+
+const (
+	OpOpen    Op = "open"
+	OpCreate  Op = "create"
+	OpStat    Op = "stat"
+	OpReadDir Op = "readdir"
+	OpMkdir   Op = "mkdir"
+	OpRemove  Op = "remove"
+	OpRename  Op = "rename"
+	OpSymlink Op = "symlink"
+	OpChmod   Op = "chmod"
+	OpChtimes Op = "chtimes"
+	OpRead    Op = "read"
+	OpWrite   Op = "write"
+	OpClose   Op = "close"
+)
+
+// Fault describes an error to inject into matching operations
+type Fault struct {
+	// Op is the operation to fail
+	Op Op
+
+	// Path is matched as a substring of the operation's path; empty matches all
+	Path string
+
+	// After lets this many bytes through before failing, for OpRead and OpWrite
+	After int64
+
+	// Err is returned by the failing operation
+	Err error
+}
+
+// FaultFS wraps an FS and injects errors into matching operations
+type FaultFS struct {
+	FS
+
+	mu     sync.Mutex
+	faults []Fault
+}
+
+// NewFaultFS wraps fs with fault injection
+func NewFaultFS(fs FS) *FaultFS {
+	return &FaultFS{FS: fs}
+}
+
+// Inject registers a fault
+func (f *FaultFS) Inject(fault Fault) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.faults = append(f.faults, fault)
+}
+
+// Reset removes all registered faults
+func (f *FaultFS) Reset() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.faults = nil
+}
+
+// match returns the first fault registered for op on path
+func (f *FaultFS) match(op Op, path string) *Fault {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for i := range f.faults {
+		fault := f.faults[i]
+		if fault.Op == op && strings.Contains(path, fault.Path) {
+			return &fault
+		}
+	}
+	return nil
+}
+
+// fail returns the injected error for op on path, if any
+func (f *FaultFS) fail(op Op, path string) error {
+	if fault := f.match(op, path); fault != nil {
+		return pathError(string(op), path, fault.Err)
+	}
+	return nil
+}
+
+// Open opens a file for reading
+func (f *FaultFS) Open(name string) (File, error) {
+	return f.OpenFile(name, os.O_RDONLY, 0)
+}
+
+// Create creates or truncates a file for writing
+func (f *FaultFS) Create(name string) (File, error) {
+	return f.OpenFile(name, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0666)
+}
+
+// OpenFile opens a file with the given flags and permissions
+func (f *FaultFS) OpenFile(name string, flag int, perm os.FileMode) (File, error) {
+	op := OpOpen
+	if flag&os.O_CREATE != 0 {
+		op = OpCreate
+	}
+	if err := f.fail(op, name); err != nil {
+		return nil, err
+	}
+
+	file, err := f.FS.OpenFile(name, flag, perm)
+	if err != nil {
+		return nil, err
+	}
+	return &faultFile{File: file, fs: f}, nil
+}
+
+// Stat returns file info, following symlinks
+func (f *FaultFS) Stat(name string) (os.FileInfo, error) {
+	if err := f.fail(OpStat, name); err != nil {
+		return nil, err
+	}
+	return f.FS.Stat(name)
+}
+
+// Lstat returns file info without following symlinks
+func (f *FaultFS) Lstat(name string) (os.FileInfo, error) {
+	if err := f.fail(OpStat, name); err != nil {
+		return nil, err
+	}
+	return f.FS.Lstat(name)
+}
+
+// ReadDir returns the entries of a directory sorted by name
+func (f *FaultFS) ReadDir(name string) ([]os.FileInfo, error) {
+	if err := f.fail(OpReadDir, name); err != nil {
+		return nil, err
+	}
+	return f.FS.ReadDir(name)
+}
+
+// MkdirAll creates a directory and any missing parents
+func (f *FaultFS) MkdirAll(path string, perm os.FileMode) error {
+	if err := f.fail(OpMkdir, path); err != nil {
+		return err
+	}
+	return f.FS.MkdirAll(path, perm)
+}
+
+// Remove removes a file or empty directory
+func (f *FaultFS) Remove(name string) error {
+	if err := f.fail(OpRemove, name); err != nil {
+		return err
+	}
+	return f.FS.Remove(name)
+}
+
+// RemoveAll removes a path and any children it contains
+func (f *FaultFS) RemoveAll(path string) error {
+	if err := f.fail(OpRemove, path); err != nil {
+		return err
+	}
+	return f.FS.RemoveAll(path)
+}
+
+// Rename moves a file or directory
+func (f *FaultFS) Rename(oldpath, newpath string) error {
+	if err := f.fail(OpRename, oldpath); err != nil {
+		return err
+	}
+	return f.FS.Rename(oldpath, newpath)
+}
+
+// Symlink creates newname as a symbolic link to oldname
+func (f *FaultFS) Symlink(oldname, newname string) error {
+	if err := f.fail(OpSymlink, newname); err != nil {
+		return err
+	}
+	return f.FS.Symlink(oldname, newname)
+}
+
+// Readlink returns the target of a symbolic link
+func (f *FaultFS) Readlink(name string) (string, error) {
+	if err := f.fail(OpStat, name); err != nil {
+		return "", err
+	}
+	return f.FS.Readlink(name)
+}
+
+// Chmod changes a file's permission bits, following symlinks
+func (f *FaultFS) Chmod(name string, mode os.FileMode) error {
+	if err := f.fail(OpChmod, name); err != nil {
+		return err
+	}
+	return f.FS.Chmod(name, mode)
+}
+
+// Chtimes changes a file's access and modification times, following
+// symlinks
+func (f *FaultFS) Chtimes(name string, atime, mtime time.Time) error {
+	if err := f.fail(OpChtimes, name); err != nil {
+		return err
+	}
+	return f.FS.Chtimes(name, atime, mtime)
+}
+
+// faultFile injects read, write and close faults into an open file
+type faultFile struct {
+	File
+	fs      *FaultFS
+	read    int64
+	written int64
+}
+
+// Read reads from the file, failing once the read fault budget is spent
+func (f *faultFile) Read(p []byte) (int, error) {
+	p, err := f.limit(OpRead, f.read, p)
+	n, rerr := f.File.Read(p)
+	f.read += int64(n)
+	if rerr != nil {
+		return n, rerr
+	}
+	return n, err
+}
+
+// ReadAt reads at an offset, failing once the read fault budget is spent
+func (f *faultFile) ReadAt(p []byte, off int64) (int, error) {
+	p, err := f.limit(OpRead, f.read, p)
+	n, rerr := f.File.ReadAt(p, off)
+	f.read += int64(n)
+	if rerr != nil {
+		return n, rerr
+	}
+	return n, err
+}
+
+// Write writes to the file, failing once the write fault budget is spent
+func (f *faultFile) Write(p []byte) (int, error) {
+	p, err := f.limit(OpWrite, f.written, p)
+	n, werr := f.File.Write(p)
+	f.written += int64(n)
+	if werr != nil {
+		return n, werr
+	}
+	return n, err
+}
+
+// Close closes the file, returning any injected close fault
+func (f *faultFile) Close() error {
+	err := f.File.Close()
+	if ferr := f.fs.fail(OpClose, f.Name()); ferr != nil {
+		return ferr
+	}
+	return err
+}
+
+// limit truncates p to the remaining byte budget of a matching fault
+func (f *faultFile) limit(op Op, done int64, p []byte) ([]byte, error) {
+	fault := f.fs.match(op, f.Name())
+	if fault == nil {
+		return p, nil
+	}
+
+	remaining := fault.After - done
+	if remaining >= int64(len(p)) {
+		return p, nil
+	}
+	if remaining < 0 {
+		remaining = 0
+	}
+	return p[:remaining], pathError(string(op), f.Name(), fault.Err)
+}
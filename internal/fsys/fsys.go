@@ -0,0 +1,140 @@
+package fsys
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// FS abstracts the filesystem operations used by the vault and storage layers
+type FS interface {
+	// Open opens a file for reading
+	Open(name string) (File, error)
+
+	// Create creates or truncates a file for writing
+	Create(name string) (File, error)
+
+	// OpenFile opens a file with the given flags and permissions
+	OpenFile(name string, flag int, perm os.FileMode) (File, error)
+
+	// Stat returns file info, following symlinks
+	Stat(name string) (os.FileInfo, error)
+
+	// Lstat returns file info without following symlinks
+	Lstat(name string) (os.FileInfo, error)
+
+	// ReadDir returns the entries of a directory sorted by name
+	ReadDir(name string) ([]os.FileInfo, error)
+
+	// MkdirAll creates a directory and any missing parents
+	MkdirAll(path string, perm os.FileMode) error
+
+	// Remove removes a file or empty directory
+	Remove(name string) error
+
+	// RemoveAll removes a path and any children it contains
+	RemoveAll(path string) error
+
+	// Rename moves a file or directory
+	Rename(oldpath, newpath string) error
+
+	// Symlink creates newname as a symbolic link to oldname
+	Symlink(oldname, newname string) error
+
+	// Readlink returns the target of a symbolic link
+	Readlink(name string) (string, error)
+
+	// Chmod changes a file's permission bits, following symlinks
+	Chmod(name string, mode os.FileMode) error
+
+	// Chtimes changes a file's access and modification times, following
+	// symlinks
+	Chtimes(name string, atime, mtime time.Time) error
+}
+
+// File is an open file handle
+type File interface {
+	io.Reader
+	io.ReaderAt
+	io.Writer
+	io.Seeker
+	io.Closer
+
+	Name() string
+	Stat() (os.FileInfo, error)
+}
+
+// ReadFile reads the whole named file
+func ReadFile(fs FS, name string) ([]byte, error) {
+	file, err := fs.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return io.ReadAll(file)
+}
+
+// WriteFile writes data to the named file, creating or truncating it
+func WriteFile(fs FS, name string, data []byte, perm os.FileMode) error {
+	file, err := fs.OpenFile(name, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return err
+	}
+
+	return file.Close()
+}
+
+// Walk walks the tree rooted at root like filepath.Walk, using fs for all access
+func Walk(fs FS, root string, fn filepath.WalkFunc) error {
+	info, err := fs.Lstat(root)
+	if err != nil {
+		err = fn(root, nil, err)
+	} else {
+		err = walk(fs, root, info, fn)
+	}
+
+	if err == filepath.SkipDir || err == filepath.SkipAll {
+		return nil
+	}
+	return err
+}
+
+// walk recursively descends path, calling fn for each entry
+func walk(fs FS, path string, info os.FileInfo, fn filepath.WalkFunc) error {
+	if !info.IsDir() {
+		return fn(path, info, nil)
+	}
+
+	entries, err := fs.ReadDir(path)
+	err1 := fn(path, info, err)
+	// Bail out if the directory could not be read or fn asked us to
+	if err != nil || err1 != nil {
+		return err1
+	}
+
+	for _, entry := range entries {
+		name := filepath.Join(path, entry.Name())
+		if err := walk(fs, name, entry, fn); err != nil {
+			if !entry.IsDir() || err != filepath.SkipDir {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// sortInfos sorts directory entries by name
+func sortInfos(infos []os.FileInfo) {
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].Name() < infos[j].Name()
+	})
+}
@@ -0,0 +1,539 @@
+package fsys
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MemFS is an in-memory FS, mainly intended for tests. It follows symlinks
+// only in the last element of a path.
+type MemFS struct {
+	mu    sync.Mutex
+	nodes map[string]*memNode
+}
+
+// maxLinkHops is how many symlinks MemFS follows before giving up on a loop
+const maxLinkHops = 40
+
+// memNode is a single file, directory or symlink held by MemFS
+type memNode struct {
+	mode    os.FileMode
+	modTime time.Time
+	data    []byte // A file's contents or a symlink's target
+}
+
+// NewMemFS creates an empty in-memory filesystem
+func NewMemFS() *MemFS {
+	now := time.Now()
+	return &MemFS{
+		nodes: map[string]*memNode{
+			string(filepath.Separator): {mode: os.ModeDir | 0755, modTime: now},
+			".":                        {mode: os.ModeDir | 0755, modTime: now},
+		},
+	}
+}
+
+// Open opens a file for reading
+func (m *MemFS) Open(name string) (File, error) {
+	return m.OpenFile(name, os.O_RDONLY, 0)
+}
+
+// Create creates or truncates a file for writing
+func (m *MemFS) Create(name string) (File, error) {
+	return m.OpenFile(name, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0666)
+}
+
+// OpenFile opens a file with the given flags and permissions
+func (m *MemFS) OpenFile(name string, flag int, perm os.FileMode) (File, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	p, node, err := m.resolve("open", name)
+	if err != nil {
+		return nil, err
+	}
+
+	if node == nil {
+		if flag&os.O_CREATE == 0 {
+			return nil, pathError("open", name, os.ErrNotExist)
+		}
+		if err := m.checkParent("open", p); err != nil {
+			return nil, err
+		}
+		node = &memNode{mode: perm.Perm(), modTime: time.Now()}
+		m.nodes[p] = node
+	} else {
+		if flag&os.O_CREATE != 0 && flag&os.O_EXCL != 0 {
+			return nil, pathError("open", name, os.ErrExist)
+		}
+		if node.mode.IsDir() && flag&(os.O_WRONLY|os.O_RDWR) != 0 {
+			return nil, pathError("open", name, errIsDir)
+		}
+		if flag&os.O_TRUNC != 0 {
+			node.data = nil
+			node.modTime = time.Now()
+		}
+	}
+
+	return &memFile{fs: m, node: node, name: name, flag: flag}, nil
+}
+
+// Stat returns file info, following symlinks
+func (m *MemFS) Stat(name string) (os.FileInfo, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	p, node, err := m.resolve("stat", name)
+	if err != nil {
+		return nil, err
+	}
+	if node == nil {
+		return nil, pathError("stat", name, os.ErrNotExist)
+	}
+
+	return node.info(filepath.Base(p)), nil
+}
+
+// Lstat returns file info without following symlinks
+func (m *MemFS) Lstat(name string) (os.FileInfo, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	p := filepath.Clean(name)
+	node, ok := m.nodes[p]
+	if !ok {
+		return nil, pathError("stat", name, os.ErrNotExist)
+	}
+
+	return node.info(filepath.Base(p)), nil
+}
+
+// ReadDir returns the entries of a directory sorted by name
+func (m *MemFS) ReadDir(name string) ([]os.FileInfo, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	p := filepath.Clean(name)
+	node, ok := m.nodes[p]
+	if !ok {
+		return nil, pathError("readdir", name, os.ErrNotExist)
+	}
+	if !node.mode.IsDir() {
+		return nil, pathError("readdir", name, errNotDir)
+	}
+
+	var infos []os.FileInfo
+	for key, child := range m.nodes {
+		if key != p && filepath.Dir(key) == p {
+			infos = append(infos, child.info(filepath.Base(key)))
+		}
+	}
+
+	sortInfos(infos)
+	return infos, nil
+}
+
+// MkdirAll creates a directory and any missing parents
+func (m *MemFS) MkdirAll(path string, perm os.FileMode) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// Collect missing directories from p up to the first existing ancestor
+	var missing []string
+	for p := filepath.Clean(path); ; p = filepath.Dir(p) {
+		if node, ok := m.nodes[p]; ok {
+			if !node.mode.IsDir() {
+				return pathError("mkdir", path, errNotDir)
+			}
+			break
+		}
+		missing = append(missing, p)
+	}
+
+	// Create them top-down
+	now := time.Now()
+	for i := len(missing) - 1; i >= 0; i-- {
+		m.nodes[missing[i]] = &memNode{mode: os.ModeDir | perm.Perm(), modTime: now}
+	}
+	return nil
+}
+
+// Remove removes a file or empty directory
+func (m *MemFS) Remove(name string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	p := filepath.Clean(name)
+	node, ok := m.nodes[p]
+	if !ok {
+		return pathError("remove", name, os.ErrNotExist)
+	}
+	if node.mode.IsDir() && m.hasChildren(p) {
+		return pathError("remove", name, errNotEmpty)
+	}
+
+	delete(m.nodes, p)
+	return nil
+}
+
+// RemoveAll removes a path and any children it contains
+func (m *MemFS) RemoveAll(path string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	p := filepath.Clean(path)
+	for key := range m.nodes {
+		if key == p || isWithin(p, key) {
+			delete(m.nodes, key)
+		}
+	}
+	return nil
+}
+
+// Rename moves a file or directory
+func (m *MemFS) Rename(oldpath, newpath string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	from := filepath.Clean(oldpath)
+	to := filepath.Clean(newpath)
+
+	node, ok := m.nodes[from]
+	if !ok {
+		return linkError("rename", oldpath, newpath, os.ErrNotExist)
+	}
+	if err := m.checkParent("rename", to); err != nil {
+		return err
+	}
+	if existing, ok := m.nodes[to]; ok && existing.mode.IsDir() && m.hasChildren(to) {
+		return linkError("rename", oldpath, newpath, errNotEmpty)
+	}
+
+	// Move the node and, for directories, everything below it
+	moved := map[string]*memNode{to: node}
+	delete(m.nodes, from)
+	if node.mode.IsDir() {
+		for key, child := range m.nodes {
+			if isWithin(from, key) {
+				moved[filepath.Join(to, strings.TrimPrefix(key, from))] = child
+				delete(m.nodes, key)
+			}
+		}
+	}
+	for key, child := range moved {
+		m.nodes[key] = child
+	}
+
+	return nil
+}
+
+// Symlink creates newname as a symbolic link to oldname
+func (m *MemFS) Symlink(oldname, newname string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	p := filepath.Clean(newname)
+	if _, ok := m.nodes[p]; ok {
+		return linkError("symlink", oldname, newname, os.ErrExist)
+	}
+	if err := m.checkParent("symlink", p); err != nil {
+		return err
+	}
+
+	m.nodes[p] = &memNode{mode: os.ModeSymlink | 0777, modTime: time.Now(), data: []byte(oldname)}
+	return nil
+}
+
+// Readlink returns the target of a symbolic link
+func (m *MemFS) Readlink(name string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	node, ok := m.nodes[filepath.Clean(name)]
+	if !ok {
+		return "", pathError("readlink", name, os.ErrNotExist)
+	}
+	if node.mode&os.ModeSymlink == 0 {
+		return "", pathError("readlink", name, errNotLink)
+	}
+	return string(node.data), nil
+}
+
+// Chmod changes a file's permission bits, following symlinks
+func (m *MemFS) Chmod(name string, mode os.FileMode) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	_, node, err := m.resolve("chmod", name)
+	if err != nil {
+		return err
+	}
+	if node == nil {
+		return pathError("chmod", name, os.ErrNotExist)
+	}
+
+	node.mode = node.mode&^os.ModePerm | mode.Perm()
+	return nil
+}
+
+// Chtimes changes a file's modification time, following symlinks. MemFS
+// doesn't keep access times.
+func (m *MemFS) Chtimes(name string, atime, mtime time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	_, node, err := m.resolve("chtimes", name)
+	if err != nil {
+		return err
+	}
+	if node == nil {
+		return pathError("chtimes", name, os.ErrNotExist)
+	}
+
+	node.modTime = mtime
+	return nil
+}
+
+// resolve follows symlinks from name to the path they lead to and its
+// node, which is nil if nothing is there
+func (m *MemFS) resolve(op, name string) (string, *memNode, error) {
+	p := filepath.Clean(name)
+	for hops := 0; hops <= maxLinkHops; hops++ {
+		node, ok := m.nodes[p]
+		if !ok || node.mode&os.ModeSymlink == 0 {
+			return p, node, nil
+		}
+
+		target := string(node.data)
+		if !filepath.IsAbs(target) {
+			target = filepath.Join(filepath.Dir(p), target)
+		}
+		p = filepath.Clean(target)
+	}
+	return "", nil, pathError(op, name, errLinkLoop)
+}
+
+// checkParent ensures the parent directory of p exists
+func (m *MemFS) checkParent(op, p string) error {
+	parent, ok := m.nodes[filepath.Dir(p)]
+	if !ok {
+		return pathError(op, p, os.ErrNotExist)
+	}
+	if !parent.mode.IsDir() {
+		return pathError(op, p, errNotDir)
+	}
+	return nil
+}
+
+// hasChildren reports whether directory p contains any entries
+func (m *MemFS) hasChildren(p string) bool {
+	for key := range m.nodes {
+		if isWithin(p, key) {
+			return true
+		}
+	}
+	return false
+}
+
+// isWithin reports whether path is strictly below dir
+func isWithin(dir, path string) bool {
+	prefix := dir
+	if !strings.HasSuffix(prefix, string(filepath.Separator)) {
+		prefix += string(filepath.Separator)
+	}
+	return path != dir && strings.HasPrefix(path, prefix)
+}
+
+// info returns a snapshot of the node's metadata
+func (n *memNode) info(name string) os.FileInfo {
+	return &memInfo{
+		name:    name,
+		size:    int64(len(n.data)),
+		mode:    n.mode,
+		modTime: n.modTime,
+	}
+}
+
+// memFile is an open handle on a MemFS node
+type memFile struct {
+	fs     *MemFS
+	node   *memNode
+	name   string
+	flag   int
+	offset int64
+	closed bool
+}
+
+// Name returns the name the file was opened with
+func (f *memFile) Name() string {
+	return f.name
+}
+
+// Read reads from the current offset
+func (f *memFile) Read(p []byte) (int, error) {
+	f.fs.mu.Lock()
+	defer f.fs.mu.Unlock()
+
+	if err := f.check("read", false); err != nil {
+		return 0, err
+	}
+	if f.offset >= int64(len(f.node.data)) {
+		return 0, io.EOF
+	}
+
+	n := copy(p, f.node.data[f.offset:])
+	f.offset += int64(n)
+	return n, nil
+}
+
+// ReadAt reads from the given offset without moving the file offset
+func (f *memFile) ReadAt(p []byte, off int64) (int, error) {
+	f.fs.mu.Lock()
+	defer f.fs.mu.Unlock()
+
+	if err := f.check("read", false); err != nil {
+		return 0, err
+	}
+	if off < 0 {
+		return 0, pathError("read", f.name, errors.New("negative offset"))
+	}
+	if off >= int64(len(f.node.data)) {
+		return 0, io.EOF
+	}
+
+	n := copy(p, f.node.data[off:])
+	if n < len(p) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+// Write writes at the current offset, or at the end in append mode
+func (f *memFile) Write(p []byte) (int, error) {
+	f.fs.mu.Lock()
+	defer f.fs.mu.Unlock()
+
+	if err := f.check("write", true); err != nil {
+		return 0, err
+	}
+	if f.flag&os.O_APPEND != 0 {
+		f.offset = int64(len(f.node.data))
+	}
+
+	end := f.offset + int64(len(p))
+	if end > int64(len(f.node.data)) {
+		grown := make([]byte, end)
+		copy(grown, f.node.data)
+		f.node.data = grown
+	}
+	copy(f.node.data[f.offset:], p)
+	f.offset = end
+	f.node.modTime = time.Now()
+
+	return len(p), nil
+}
+
+// Seek sets the offset for the next Read or Write
+func (f *memFile) Seek(offset int64, whence int) (int64, error) {
+	f.fs.mu.Lock()
+	defer f.fs.mu.Unlock()
+
+	if f.closed {
+		return 0, pathError("seek", f.name, os.ErrClosed)
+	}
+
+	var base int64
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		base = f.offset
+	case io.SeekEnd:
+		base = int64(len(f.node.data))
+	default:
+		return 0, pathError("seek", f.name, errors.New("invalid whence"))
+	}
+
+	if base+offset < 0 {
+		return 0, pathError("seek", f.name, errors.New("negative offset"))
+	}
+	f.offset = base + offset
+	return f.offset, nil
+}
+
+// Stat returns the file's current metadata
+func (f *memFile) Stat() (os.FileInfo, error) {
+	f.fs.mu.Lock()
+	defer f.fs.mu.Unlock()
+
+	if f.closed {
+		return nil, pathError("stat", f.name, os.ErrClosed)
+	}
+	return f.node.info(filepath.Base(f.name)), nil
+}
+
+// Close closes the handle
+func (f *memFile) Close() error {
+	f.fs.mu.Lock()
+	defer f.fs.mu.Unlock()
+
+	if f.closed {
+		return pathError("close", f.name, os.ErrClosed)
+	}
+	f.closed = true
+	return nil
+}
+
+// check validates that the handle can be used for the given access
+func (f *memFile) check(op string, write bool) error {
+	if f.closed {
+		return pathError(op, f.name, os.ErrClosed)
+	}
+	if f.node.mode.IsDir() {
+		return pathError(op, f.name, errIsDir)
+	}
+
+	access := f.flag & (os.O_RDONLY | os.O_WRONLY | os.O_RDWR)
+	if write && access == os.O_RDONLY {
+		return pathError(op, f.name, os.ErrPermission)
+	}
+	if !write && access == os.O_WRONLY {
+		return pathError(op, f.name, os.ErrPermission)
+	}
+	return nil
+}
+
+// memInfo implements os.FileInfo for MemFS nodes
+type memInfo struct {
+	name    string
+	size    int64
+	mode    os.FileMode
+	modTime time.Time
+}
+
+func (i *memInfo) Name() string       { return i.name }
+func (i *memInfo) Size() int64        { return i.size }
+func (i *memInfo) Mode() os.FileMode  { return i.mode }
+func (i *memInfo) ModTime() time.Time { return i.modTime }
+func (i *memInfo) IsDir() bool        { return i.mode.IsDir() }
+func (i *memInfo) Sys() interface{}   { return nil }
+
+var (
+	errIsDir    = errors.New("is a directory")
+	errNotDir   = errors.New("not a directory")
+	errNotEmpty = errors.New("directory not empty")
+	errNotLink  = errors.New("not a symbolic link")
+	errLinkLoop = errors.New("too many levels of symbolic links")
+)
+
+func pathError(op, path string, err error) error {
+	return &os.PathError{Op: op, Path: path, Err: err}
+}
+
+func linkError(op, oldpath, newpath string, err error) error {
+	return &os.LinkError{Op: op, Old: oldpath, New: newpath, Err: err}
+}
@@ -0,0 +1,166 @@
+package fsys
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestMemFSReadWrite(t *testing.T) {
+	fs := NewMemFS()
+
+	if err := fs.MkdirAll("/saves/slot1", 0755); err != nil {
+		t.Fatalf("MkdirAll: %v", err)
+	}
+	if err := WriteFile(fs, "/saves/slot1/data.sav", []byte("hello"), 0644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	data, err := ReadFile(fs, "/saves/slot1/data.sav")
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	if string(data) != "hello" {
+		t.Fatalf("got %q, want %q", data, "hello")
+	}
+
+	info, err := fs.Stat("/saves/slot1/data.sav")
+	if err != nil {
+		t.Fatalf("Stat: %v", err)
+	}
+	if info.Size() != 5 || info.IsDir() {
+		t.Fatalf("unexpected info: size=%d dir=%v", info.Size(), info.IsDir())
+	}
+}
+
+func TestMemFSCreateRequiresParent(t *testing.T) {
+	fs := NewMemFS()
+
+	_, err := fs.Create("/missing/file")
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected ErrNotExist, got %v", err)
+	}
+}
+
+func TestMemFSRenameDirectory(t *testing.T) {
+	fs := NewMemFS()
+	fs.MkdirAll("/a/b", 0755)
+	WriteFile(fs, "/a/b/c.txt", []byte("x"), 0644)
+
+	if err := fs.Rename("/a", "/z"); err != nil {
+		t.Fatalf("Rename: %v", err)
+	}
+	if _, err := fs.Stat("/a/b/c.txt"); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("old path still exists: %v", err)
+	}
+	if data, err := ReadFile(fs, "/z/b/c.txt"); err != nil || string(data) != "x" {
+		t.Fatalf("moved file: %q, %v", data, err)
+	}
+}
+
+func TestMemFSSymlink(t *testing.T) {
+	fs := NewMemFS()
+	fs.MkdirAll("/saves/slots", 0755)
+	WriteFile(fs, "/saves/slots/1.sav", []byte("level=12"), 0644)
+
+	if err := fs.Symlink("slots/1.sav", "/saves/latest.sav"); err != nil {
+		t.Fatalf("Symlink: %v", err)
+	}
+	if err := fs.Symlink("elsewhere", "/saves/latest.sav"); !errors.Is(err, os.ErrExist) {
+		t.Fatalf("Symlink over an existing link = %v, want ErrExist", err)
+	}
+
+	if target, err := fs.Readlink("/saves/latest.sav"); err != nil || target != "slots/1.sav" {
+		t.Fatalf("Readlink = %q, %v", target, err)
+	}
+	if _, err := fs.Readlink("/saves/slots/1.sav"); err == nil {
+		t.Fatal("Readlink of a regular file succeeded")
+	}
+	if data, err := ReadFile(fs, "/saves/latest.sav"); err != nil || string(data) != "level=12" {
+		t.Fatalf("reading through the link: %q, %v", data, err)
+	}
+
+	linkInfo, _ := fs.Lstat("/saves/latest.sav")
+	fileInfo, _ := fs.Stat("/saves/latest.sav")
+	if linkInfo.Mode()&os.ModeSymlink == 0 || !fileInfo.Mode().IsRegular() || fileInfo.Size() != 8 {
+		t.Fatalf("Lstat mode %v, Stat mode %v size %d", linkInfo.Mode(), fileInfo.Mode(), fileInfo.Size())
+	}
+
+	fs.Symlink("/loop/b", "/loop-a")
+	fs.MkdirAll("/loop", 0755)
+	fs.Symlink("/loop-a", "/loop/b")
+	if _, err := fs.Stat("/loop-a"); err == nil {
+		t.Fatal("Stat of a symlink loop succeeded")
+	}
+}
+
+func TestMemFSChmodChtimes(t *testing.T) {
+	fs := NewMemFS()
+	WriteFile(fs, "/run.sh", []byte("#!/bin/sh"), 0644)
+	fs.Symlink("run.sh", "/start")
+
+	mtime := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	if err := fs.Chmod("/start", 0755); err != nil {
+		t.Fatalf("Chmod: %v", err)
+	}
+	if err := fs.Chtimes("/start", mtime, mtime); err != nil {
+		t.Fatalf("Chtimes: %v", err)
+	}
+
+	info, _ := fs.Stat("/run.sh")
+	if info.Mode() != 0755 || !info.ModTime().Equal(mtime) {
+		t.Fatalf("run.sh mode %v, modified %v", info.Mode(), info.ModTime())
+	}
+	if link, _ := fs.Lstat("/start"); link.Mode()&os.ModeSymlink == 0 {
+		t.Fatalf("Chmod through the link changed the link itself: %v", link.Mode())
+	}
+}
+
+func TestWalkOrder(t *testing.T) {
+	fs := NewMemFS()
+	fs.MkdirAll("/root/b", 0755)
+	fs.MkdirAll("/root/a", 0755)
+	WriteFile(fs, "/root/b/2.txt", nil, 0644)
+	WriteFile(fs, "/root/a/1.txt", nil, 0644)
+
+	var got []string
+	err := Walk(fs, "/root", func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		got = append(got, filepath.ToSlash(path))
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Walk: %v", err)
+	}
+
+	want := []string{"/root", "/root/a", "/root/a/1.txt", "/root/b", "/root/b/2.txt"}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("got %v, want %v", got, want)
+		}
+	}
+}
+
+func TestFaultFSWriteBudget(t *testing.T) {
+	fs := NewFaultFS(NewMemFS())
+	fs.Inject(Fault{Op: OpWrite, Path: "full.bin", After: 3, Err: syscall.ENOSPC})
+
+	file, err := fs.Create("/full.bin")
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	defer file.Close()
+
+	n, err := file.Write([]byte("12345"))
+	if n != 3 || !errors.Is(err, syscall.ENOSPC) {
+		t.Fatalf("got n=%d err=%v, want n=3 ENOSPC", n, err)
+	}
+}
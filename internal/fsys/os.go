@@ -0,0 +1,99 @@
+package fsys
+
+import (
+	"os"
+	"time"
+)
+
+// OS is an FS backed by the real operating system filesystem
+type OS struct{}
+
+// Open opens a file for reading
+func (OS) Open(name string) (File, error) {
+	return os.Open(name)
+}
+
+// Create creates or truncates a file for writing
+func (OS) Create(name string) (File, error) {
+	return os.Create(name)
+}
+
+// OpenFile opens a file with the given flags and permissions
+func (OS) OpenFile(name string, flag int, perm os.FileMode) (File, error) {
+	return os.OpenFile(name, flag, perm)
+}
+
+// Stat returns file info, following symlinks
+func (OS) Stat(name string) (os.FileInfo, error) {
+	return os.Stat(name)
+}
+
+// Lstat returns file info without following symlinks
+func (OS) Lstat(name string) (os.FileInfo, error) {
+	return os.Lstat(name)
+}
+
+// ReadDir returns the entries of a directory sorted by name
+func (OS) ReadDir(name string) ([]os.FileInfo, error) {
+	entries, err := os.ReadDir(name)
+	if err != nil {
+		return nil, err
+	}
+
+	infos := make([]os.FileInfo, 0, len(entries))
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil {
+			// Entry vanished between listing and stat
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+		infos = append(infos, info)
+	}
+
+	sortInfos(infos)
+	return infos, nil
+}
+
+// MkdirAll creates a directory and any missing parents
+func (OS) MkdirAll(path string, perm os.FileMode) error {
+	return os.MkdirAll(path, perm)
+}
+
+// Remove removes a file or empty directory
+func (OS) Remove(name string) error {
+	return os.Remove(name)
+}
+
+// RemoveAll removes a path and any children it contains
+func (OS) RemoveAll(path string) error {
+	return os.RemoveAll(path)
+}
+
+// Rename moves a file or directory
+func (OS) Rename(oldpath, newpath string) error {
+	return os.Rename(oldpath, newpath)
+}
+
+// Symlink creates newname as a symbolic link to oldname
+func (OS) Symlink(oldname, newname string) error {
+	return os.Symlink(oldname, newname)
+}
+
+// Readlink returns the target of a symbolic link
+func (OS) Readlink(name string) (string, error) {
+	return os.Readlink(name)
+}
+
+// Chmod changes a file's permission bits, following symlinks
+func (OS) Chmod(name string, mode os.FileMode) error {
+	return os.Chmod(name, mode)
+}
+
+// Chtimes changes a file's access and modification times, following
+// symlinks
+func (OS) Chtimes(name string, atime, mtime time.Time) error {
+	return os.Chtimes(name, atime, mtime)
+}
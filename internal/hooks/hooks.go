@@ -0,0 +1,91 @@
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// maxOutput is how much of a hook's output is kept for error messages
+const maxOutput = 4096
+
+// ErrTimeout is returned when a hook runs past its timeout
+var ErrTimeout = errors.New("hook timed out")
+
+// Command is a hook command to run through the system shell
+type Command struct {
+	Command string
+	Timeout time.Duration
+	Env     []string // Added to this process's environment, as KEY=value
+	Stdin   []byte
+}
+
+// Run runs a hook command and waits for it, killing it at its timeout. A
+// non-zero exit is an error that includes the end of the hook's output.
+func Run(c Command) (output string, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.Timeout)
+	defer cancel()
+
+	cmd := shellCommand(ctx, c.Command)
+	cmd.Env = append(os.Environ(), c.Env...)
+	cmd.Stdin = bytes.NewReader(c.Stdin)
+
+	var buf limitedBuffer
+	cmd.Stdout = &buf
+	cmd.Stderr = &buf
+
+	// Don't wait forever for children that kept the output open
+	cmd.WaitDelay = time.Second
+
+	err = cmd.Run()
+	output = strings.TrimSpace(buf.String())
+	if ctx.Err() == context.DeadlineExceeded {
+		return output, fmt.Errorf("%w after %s", ErrTimeout, c.Timeout)
+	}
+	if err != nil {
+		if output != "" {
+			return output, fmt.Errorf("%w: %s", err, lastLine(output))
+		}
+		return output, err
+	}
+	return output, nil
+}
+
+// shellCommand runs a command line the way the user would type it
+func shellCommand(ctx context.Context, command string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.CommandContext(ctx, "cmd", "/C", command)
+	}
+	return exec.CommandContext(ctx, "sh", "-c", command)
+}
+
+// lastLine returns the last line of output, which usually says what went wrong
+func lastLine(output string) string {
+	if i := strings.LastIndexByte(output, '\n'); i >= 0 {
+		return output[i+1:]
+	}
+	return output
+}
+
+// limitedBuffer keeps the last maxOutput bytes written to it
+type limitedBuffer struct {
+	buf []byte
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	b.buf = append(b.buf, p...)
+	if len(b.buf) > maxOutput {
+		b.buf = b.buf[len(b.buf)-maxOutput:]
+	}
+	return len(p), nil
+}
+
+func (b *limitedBuffer) String() string {
+	return string(b.buf)
+}
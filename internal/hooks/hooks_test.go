@@ -0,0 +1,39 @@
+package hooks
+
+import (
+	"errors"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRun(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+
+	output, err := Run(Command{
+		Command: `read line; echo "$GAMEKEEP_EVENT $line"`,
+		Timeout: 5 * time.Second,
+		Env:     []string{"GAMEKEEP_EVENT=pre-restore"},
+		Stdin:   []byte("{}\n"),
+	})
+	if err != nil || output != "pre-restore {}" {
+		t.Fatalf("Run = %q, %v", output, err)
+	}
+
+	_, err = Run(Command{Command: "echo starting; echo sync client busy >&2; exit 3", Timeout: 5 * time.Second})
+	if err == nil || !strings.Contains(err.Error(), "sync client busy") {
+		t.Fatalf("failing hook = %v, want error with its last line", err)
+	}
+
+	start := time.Now()
+	_, err = Run(Command{Command: "sleep 10", Timeout: 100 * time.Millisecond})
+	if !errors.Is(err, ErrTimeout) {
+		t.Fatalf("slow hook = %v, want ErrTimeout", err)
+	}
+	if time.Since(start) > 5*time.Second {
+		t.Fatal("slow hook was not killed at its timeout")
+	}
+}
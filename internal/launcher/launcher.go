@@ -0,0 +1,74 @@
+package launcher
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"os/signal"
+	"syscall"
+)
+
+// ErrNoCommand is returned when there is nothing to launch
+var ErrNoCommand = errors.New("no command to launch")
+
+// Process is a launched game
+type Process struct {
+	cmd *exec.Cmd
+}
+
+// Start starts a command attached to this process's stdin, stdout and
+// stderr. Where supported, processes the command leaves behind are adopted
+// so Wait can wait for them too.
+func Start(command []string) (*Process, error) {
+	if len(command) == 0 {
+		return nil, ErrNoCommand
+	}
+
+	// Adopting is a nicety: without it Wait only sees the command itself
+	adoptOrphans()
+
+	cmd := exec.Command(command[0], command[1:]...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	return &Process{cmd: cmd}, nil
+}
+
+// Wait waits for the command and any processes it left behind to exit, and
+// returns the command's exit code. SIGTERM is passed on to the command;
+// Ctrl-C already reaches it through the terminal, so this process only
+// keeps running to finish up.
+func (p *Process) Wait() (int, error) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(signals)
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		for {
+			select {
+			case sig := <-signals:
+				if sig == syscall.SIGTERM {
+					p.cmd.Process.Signal(sig)
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	err := p.cmd.Wait()
+	var exitErr *exec.ExitError
+	if err != nil && !errors.As(err, &exitErr) {
+		return -1, err
+	}
+
+	waitOrphans()
+	return p.cmd.ProcessState.ExitCode(), nil
+}
@@ -0,0 +1,26 @@
+package launcher
+
+import "syscall"
+
+// prSetChildSubreaper is prctl's PR_SET_CHILD_SUBREAPER option
+const prSetChildSubreaper = 36
+
+// adoptOrphans makes this process the parent of any orphaned descendants,
+// such as a game a launcher script started before exiting
+func adoptOrphans() {
+	syscall.RawSyscall(syscall.SYS_PRCTL, prSetChildSubreaper, 1, 0)
+}
+
+// waitOrphans waits until every adopted descendant has exited
+func waitOrphans() {
+	for {
+		var status syscall.WaitStatus
+		_, err := syscall.Wait4(-1, &status, 0, nil)
+		if err == syscall.EINTR {
+			continue
+		}
+		if err != nil {
+			return
+		}
+	}
+}
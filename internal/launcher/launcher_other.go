@@ -0,0 +1,10 @@
+//go:build !linux
+
+package launcher
+
+// adoptOrphans is not supported on this platform; Wait only waits for the
+// command itself
+func adoptOrphans() {}
+
+// waitOrphans has nothing to wait for without adoptOrphans
+func waitOrphans() {}
@@ -0,0 +1,99 @@
+package models
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Codec is how a checkpoint's files are compressed
+type Codec string
+
+const (
+	CodecStore   Codec = "store"   // No compression, for saves that are already compressed
+	CodecDeflate Codec = "deflate" // Readable by every zip tool; the default
+	CodecZstd    Codec = "zstd"    // Zstandard: faster, and smaller at high levels
+)
+
+// AllCodecs lists every codec, for validation and pickers
+var AllCodecs = []Codec{CodecStore, CodecDeflate, CodecZstd}
+
+// Known reports whether this version can read archives made with the
+// codec. Empty is known: checkpoints from older versions don't record
+// their codec, and always used deflate.
+func (c Codec) Known() bool {
+	if c == "" {
+		return true
+	}
+	for _, codec := range AllCodecs {
+		if c == codec {
+			return true
+		}
+	}
+	return false
+}
+
+// Compression is how to compress a game's checkpoints. Files that barely
+// compress are stored as they are whatever the codec.
+type Compression struct {
+	Codec Codec `json:"codec"`
+	Level int   `json:"level,omitempty"` // 0 uses the codec's default
+}
+
+// DefaultCompression is used by games that don't choose their own
+func DefaultCompression() Compression {
+	return Compression{Codec: CodecDeflate}
+}
+
+// ParseCompression parses a codec optionally followed by a level, such as
+// "store", "deflate:9" or "zstd:3"
+func ParseCompression(s string) (Compression, error) {
+	name, level, hasLevel := strings.Cut(strings.TrimSpace(s), ":")
+	c := Compression{Codec: Codec(strings.ToLower(name))}
+	if hasLevel {
+		n, err := strconv.Atoi(level)
+		if err != nil {
+			return Compression{}, fmt.Errorf("%w: level %q is not a number", ErrInvalidCompression, level)
+		}
+		c.Level = n
+	}
+	if err := c.Validate(); err != nil {
+		return Compression{}, err
+	}
+	return c, nil
+}
+
+// Validate validates a compression setting
+func (c *Compression) Validate() error {
+	min, max := c.Levels()
+	if !c.Codec.Known() || c.Codec == "" {
+		return fmt.Errorf("%w: unknown codec %q", ErrInvalidCompression, c.Codec)
+	}
+	if c.Level != 0 && (c.Level < min || c.Level > max) {
+		if max == 0 {
+			return fmt.Errorf("%w: %s has no levels", ErrInvalidCompression, c.Codec)
+		}
+		return fmt.Errorf("%w: %s levels are %d to %d", ErrInvalidCompression, c.Codec, min, max)
+	}
+	return nil
+}
+
+// Levels returns the range of levels the codec accepts; both are zero if
+// it has none
+func (c *Compression) Levels() (min, max int) {
+	switch c.Codec {
+	case CodecDeflate:
+		return 1, 9
+	case CodecZstd:
+		return 1, 22
+	}
+	return 0, 0
+}
+
+// String returns the setting in the form ParseCompression reads
+func (c Compression) String() string {
+	if c.Level == 0 {
+		return string(c.Codec)
+	}
+	return fmt.Sprintf("%s:%d", c.Codec, c.Level)
+}
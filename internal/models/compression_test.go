@@ -0,0 +1,37 @@
+package models
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseCompression(t *testing.T) {
+	tests := []struct {
+		s    string
+		want Compression
+	}{
+		{"store", Compression{Codec: CodecStore}},
+		{"deflate", Compression{Codec: CodecDeflate}},
+		{"Deflate:9", Compression{Codec: CodecDeflate, Level: 9}},
+		{"zstd:19", Compression{Codec: CodecZstd, Level: 19}},
+	}
+	for _, tt := range tests {
+		got, err := ParseCompression(tt.s)
+		if err != nil {
+			t.Errorf("ParseCompression(%q): %v", tt.s, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseCompression(%q) = %+v, want %+v", tt.s, got, tt.want)
+		}
+		if again, _ := ParseCompression(got.String()); again != got {
+			t.Errorf("%q doesn't parse back from %q", tt.s, got.String())
+		}
+	}
+
+	for _, bad := range []string{"", "brotli", "store:1", "deflate:10", "zstd:0x3", "zstd:23"} {
+		if _, err := ParseCompression(bad); !errors.Is(err, ErrInvalidCompression) {
+			t.Errorf("ParseCompression(%q) = %v, want ErrInvalidCompression", bad, err)
+		}
+	}
+}
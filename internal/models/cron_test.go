@@ -0,0 +1,72 @@
+package models
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCronNext(t *testing.T) {
+	// A Wednesday
+	from := time.Date(2024, 1, 31, 22, 10, 30, 0, time.UTC)
+
+	tests := []struct {
+		expr string
+		want time.Time
+	}{
+		{"* * * * *", time.Date(2024, 1, 31, 22, 11, 0, 0, time.UTC)},
+		{"*/30 * * * *", time.Date(2024, 1, 31, 22, 30, 0, 0, time.UTC)},
+		{"0 9 * * *", time.Date(2024, 2, 1, 9, 0, 0, 0, time.UTC)},
+		{"15 20-23/2 * * *", time.Date(2024, 1, 31, 22, 15, 0, 0, time.UTC)},
+		{"0 12 * * sat,sun", time.Date(2024, 2, 3, 12, 0, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2024, 2, 4, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 feb *", time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)},
+		// Either day field matches when both are given
+		{"0 0 10 * fri", time.Date(2024, 2, 2, 0, 0, 0, 0, time.UTC)},
+		{"@monthly", time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 31 2 *", time.Time{}},
+	}
+	for _, tt := range tests {
+		expr, err := ParseCron(tt.expr)
+		if err != nil {
+			t.Errorf("ParseCron(%q): %v", tt.expr, err)
+			continue
+		}
+		if got := expr.Next(from); !got.Equal(tt.want) {
+			t.Errorf("%q: Next = %v, want %v", tt.expr, got, tt.want)
+		}
+	}
+
+	for _, bad := range []string{"", "* * * *", "60 * * * *", "* * * * mon-sun/0", "5-1 * * * *", "* * * foo *"} {
+		if _, err := ParseCron(bad); !errors.Is(err, ErrInvalidSchedule) {
+			t.Errorf("ParseCron(%q) = %v, want ErrInvalidSchedule", bad, err)
+		}
+	}
+}
+
+func TestScheduleNext(t *testing.T) {
+	from := time.Date(2024, 1, 31, 22, 10, 0, 0, time.UTC)
+
+	daily := Schedule{Kind: ScheduleDaily, DailyAt: "21:30"}
+	if got, want := daily.Next(from), time.Date(2024, 2, 1, 21, 30, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("daily Next = %v, want %v", got, want)
+	}
+
+	interval := Schedule{Kind: ScheduleInterval, IntervalMinutes: 90}
+	if got, want := interval.Next(from), from.Add(90*time.Minute); !got.Equal(want) {
+		t.Errorf("interval Next = %v, want %v", got, want)
+	}
+	if got := interval.Describe(); got != "every 1h30m" {
+		t.Errorf("Describe = %q", got)
+	}
+
+	for _, bad := range []Schedule{
+		{Kind: ScheduleInterval},
+		{Kind: ScheduleDaily, DailyAt: "25:00"},
+		{Kind: "weekly"},
+	} {
+		if err := bad.Validate(); !errors.Is(err, ErrInvalidSchedule) {
+			t.Errorf("Validate(%+v) = %v, want ErrInvalidSchedule", bad, err)
+		}
+	}
+}
@@ -1,20 +1,94 @@
 package models
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 var (
 	// Game errors
-	ErrEmptyGameName  = errors.New("game name cannot be empty")
-	ErrEmptySavePath  = errors.New("save path cannot be empty")
-	ErrGameNotFound   = errors.New("game not found")
-	ErrGameExists     = errors.New("game already exists")
+	ErrEmptyGameName      = errors.New("game name cannot be empty")
+	ErrEmptySavePath      = errors.New("save path cannot be empty")
+	ErrGameNotFound       = errors.New("game not found")
+	ErrGameExists         = errors.New("game already exists")
+	ErrGameArchived       = errors.New("game is archived")
+	ErrInvalidRemoveMode  = errors.New("invalid remove mode: use keep, delete or export")
+	ErrUnknownInspector   = errors.New("unknown save inspector")
+	ErrInvalidCover       = errors.New("cover image must be a PNG, JPEG, GIF or WebP file")
+	ErrInvalidCompression = errors.New("invalid compression")
 
 	// Checkpoint errors
 	ErrEmptyGameID          = errors.New("game ID cannot be empty")
 	ErrEmptyCheckpointName  = errors.New("checkpoint name cannot be empty")
 	ErrCheckpointNotFound   = errors.New("checkpoint not found")
-	
+	ErrTrashEntryNotFound   = errors.New("checkpoint not found in trash")
+	ErrCheckpointExists     = errors.New("checkpoint already exists")
+	ErrCheckpointIDTooShort = errors.New("checkpoint ID prefix is too short")
+	ErrInvalidTag           = errors.New("tags cannot be empty or contain commas")
+	ErrCheckpointProtected  = errors.New("checkpoint is protected")
+	ErrInvalidField         = errors.New("invalid custom field")
+	ErrNoChanges            = errors.New("saves have not changed since the latest checkpoint")
+
+	// Schedule errors
+	ErrScheduleNotFound = errors.New("schedule not found")
+	ErrInvalidSchedule  = errors.New("invalid schedule")
+
+	// Hook errors
+	ErrInvalidHook  = errors.New("invalid hook")
+	ErrHookVetoed   = errors.New("stopped by a hook")
+	ErrHookNotFound = errors.New("hook not found")
+
+	// Lookup errors
+	ErrAmbiguous = errors.New("ambiguous identifier")
+
 	// Storage errors
-	ErrInvalidPath          = errors.New("invalid path")
-	ErrHashMismatch         = errors.New("hash mismatch")
+	ErrInvalidPath      = errors.New("invalid path")
+	ErrHashMismatch     = errors.New("hash mismatch")
+	ErrInvalidBundle    = errors.New("invalid export bundle")
+	ErrUnsupportedCodec = errors.New("archive uses a compression this version can't read")
+	ErrArchiveLimit     = errors.New("archive exceeds a safety limit")
+	ErrUnsafeLink       = errors.New("symlink points outside the save directory")
+
+	// Settings errors
+	ErrInvalidSettings = errors.New("invalid settings")
 )
+
+// Candidate is one of several items matched by an ambiguous identifier
+type Candidate struct {
+	ID     string
+	Name   string
+	Detail string
+}
+
+// AmbiguousError is returned when an identifier matches more than one game,
+// checkpoint or schedule. It matches ErrAmbiguous with errors.Is.
+type AmbiguousError struct {
+	Kind       string // "game", "checkpoint" or "schedule"
+	Query      string
+	Candidates []Candidate
+}
+
+func (e *AmbiguousError) Error() string {
+	return fmt.Sprintf("%q matches %d %ss", e.Query, len(e.Candidates), e.Kind)
+}
+
+// Is reports whether target is ErrAmbiguous
+func (e *AmbiguousError) Is(target error) bool {
+	return target == ErrAmbiguous
+}
+
+// NoChangesError is returned instead of creating a checkpoint whose saves
+// match the game's latest checkpoint. It matches ErrNoChanges with
+// errors.Is, so automation can ignore it.
+type NoChangesError struct {
+	Checkpoint Checkpoint // The latest checkpoint, which the saves match
+}
+
+func (e *NoChangesError) Error() string {
+	return fmt.Sprintf("saves have not changed since checkpoint %q", e.Checkpoint.Name)
+}
+
+// Is reports whether target is ErrNoChanges
+func (e *NoChangesError) Is(target error) bool {
+	return target == ErrNoChanges
+}
@@ -0,0 +1,70 @@
+package models
+
+import (
+	"time"
+)
+
+// EventType identifies what an Event reports
+type EventType string
+
+const (
+	EventGameAdded          EventType = "game-added"
+	EventCheckpointCreated  EventType = "checkpoint-created"
+	EventCheckpointRestored EventType = "checkpoint-restored"
+	EventCheckpointDeleted  EventType = "checkpoint-deleted"
+	EventVerifyFailed       EventType = "verify-failed"
+	EventProgress           EventType = "progress"
+	EventHookFailed         EventType = "hook-failed"
+	EventLinkSkipped        EventType = "link-skipped"
+
+	// EventOperation reports every journaled operation as it finishes,
+	// with its journal entry
+	EventOperation EventType = "operation"
+)
+
+// Event is published by the service to its subscribers. Only the fields
+// that apply to the event's type are set.
+type Event struct {
+	Type         EventType
+	At           time.Time
+	Origin       Origin
+	GameID       string
+	CheckpointID string
+
+	Game       *Game         // GameAdded, when it happened in this process
+	Checkpoint *Checkpoint   // Checkpoint events, when it happened in this process
+	Entry      *JournalEntry // Operation
+	Progress   *Progress     // Progress
+	Err        error         // VerifyFailed, HookFailed, LinkSkipped
+
+	// External is set for events learned from the journal that another
+	// process, such as the CLI, wrote
+	External bool
+}
+
+// Progress reports how far a long operation has got. Archive work counts
+// files and bytes; bundle export and import count checkpoints as files.
+type Progress struct {
+	Operation  Operation
+	Item       string // What is being worked on, e.g. a file or checkpoint name
+	FilesDone  int
+	FilesTotal int
+	BytesDone  int64
+	BytesTotal int64
+}
+
+// ProgressFunc receives progress reports as an operation runs
+type ProgressFunc func(Progress)
+
+// Fraction returns how much of the operation is done, from 0 to 1, by
+// bytes when they are known and by files otherwise
+func (p *Progress) Fraction() float64 {
+	switch {
+	case p.BytesTotal > 0:
+		return float64(p.BytesDone) / float64(p.BytesTotal)
+	case p.FilesTotal > 0:
+		return float64(p.FilesDone) / float64(p.FilesTotal)
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,254 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FieldType is the type of a custom checkpoint field
+type FieldType string
+
+const (
+	FieldString FieldType = "string"
+	FieldNumber FieldType = "number"
+	FieldBool   FieldType = "bool"
+	FieldDate   FieldType = "date"
+)
+
+// FieldDateLayout is the format of date field values
+const FieldDateLayout = "2006-01-02"
+
+// FieldDef defines a custom field that a game's checkpoints can carry
+type FieldDef struct {
+	Key  string    `json:"key"`
+	Type FieldType `json:"type"`
+}
+
+// Validate validates a field definition
+func (d *FieldDef) Validate() error {
+	if d.Key == "" {
+		return fmt.Errorf("%w: empty key", ErrInvalidField)
+	}
+	for _, r := range d.Key {
+		if !((r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '_' || r == '-') {
+			return fmt.Errorf("%w: key %q may only use a-z, 0-9, '_' and '-'", ErrInvalidField, d.Key)
+		}
+	}
+	switch d.Type {
+	case FieldString, FieldNumber, FieldBool, FieldDate:
+		return nil
+	}
+	return fmt.Errorf("%w: unknown type %q for %s", ErrInvalidField, d.Type, d.Key)
+}
+
+// ParseFieldDef parses a "key:type" definition; the type defaults to string
+func ParseFieldDef(s string) (FieldDef, error) {
+	key, typ, found := strings.Cut(strings.TrimSpace(s), ":")
+	def := FieldDef{Key: strings.ToLower(strings.TrimSpace(key)), Type: FieldString}
+	if found {
+		def.Type = FieldType(strings.ToLower(strings.TrimSpace(typ)))
+	}
+	return def, def.Validate()
+}
+
+// String formats the definition as "key:type"
+func (d FieldDef) String() string {
+	return d.Key + ":" + string(d.Type)
+}
+
+// Normalize checks a value against the field's type and returns it in
+// canonical form, so stored values compare consistently
+func (d *FieldDef) Normalize(value string) (string, error) {
+	value = strings.TrimSpace(value)
+	switch d.Type {
+	case FieldNumber:
+		n, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return "", fmt.Errorf("%w: %s must be a number, got %q", ErrInvalidField, d.Key, value)
+		}
+		return strconv.FormatFloat(n, 'f', -1, 64), nil
+	case FieldBool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return "", fmt.Errorf("%w: %s must be true or false, got %q", ErrInvalidField, d.Key, value)
+		}
+		return strconv.FormatBool(b), nil
+	case FieldDate:
+		t, err := time.Parse(FieldDateLayout, value)
+		if err != nil {
+			return "", fmt.Errorf("%w: %s must be a date like 2024-01-31, got %q", ErrInvalidField, d.Key, value)
+		}
+		return t.Format(FieldDateLayout), nil
+	}
+	return value, nil
+}
+
+// Compare orders two values of this field, returning -1, 0 or 1. Values
+// that don't parse as the field's type sort before ones that do.
+func (d *FieldDef) Compare(a, b string) int {
+	switch d.Type {
+	case FieldNumber:
+		x, errX := strconv.ParseFloat(a, 64)
+		y, errY := strconv.ParseFloat(b, 64)
+		if errX != nil || errY != nil {
+			return compareValid(errX == nil, errY == nil, a, b)
+		}
+		switch {
+		case x < y:
+			return -1
+		case x > y:
+			return 1
+		}
+		return 0
+	case FieldBool:
+		x, errX := strconv.ParseBool(a)
+		y, errY := strconv.ParseBool(b)
+		if errX != nil || errY != nil {
+			return compareValid(errX == nil, errY == nil, a, b)
+		}
+		switch {
+		case x == y:
+			return 0
+		case !x:
+			return -1
+		}
+		return 1
+	}
+	// Strings and dates (in FieldDateLayout) compare lexically
+	return strings.Compare(a, b)
+}
+
+// compareValid orders values when at least one failed to parse
+func compareValid(okA, okB bool, a, b string) int {
+	switch {
+	case okA && !okB:
+		return 1
+	case !okA && okB:
+		return -1
+	}
+	return strings.Compare(a, b)
+}
+
+// FindField returns the definition for key, or nil
+func FindField(defs []FieldDef, key string) *FieldDef {
+	for i := range defs {
+		if defs[i].Key == key {
+			return &defs[i]
+		}
+	}
+	return nil
+}
+
+// NormalizeFields checks checkpoint field values against a game's field
+// definitions and returns them in canonical form. Empty values are dropped.
+func NormalizeFields(defs []FieldDef, values map[string]string) (map[string]string, error) {
+	normalized := make(map[string]string, len(values))
+	for key, value := range values {
+		def := FindField(defs, key)
+		if def == nil {
+			return nil, fmt.Errorf("%w: %s is not defined for this game", ErrInvalidField, key)
+		}
+		if strings.TrimSpace(value) == "" {
+			continue
+		}
+		v, err := def.Normalize(value)
+		if err != nil {
+			return nil, err
+		}
+		normalized[key] = v
+	}
+	if len(normalized) == 0 {
+		return nil, nil
+	}
+	return normalized, nil
+}
+
+// FieldOp is a comparison used by a FieldFilter
+type FieldOp string
+
+const (
+	FieldEq FieldOp = "="
+	FieldNe FieldOp = "!="
+	FieldLt FieldOp = "<"
+	FieldLe FieldOp = "<="
+	FieldGt FieldOp = ">"
+	FieldGe FieldOp = ">="
+)
+
+// fieldOps lists operators longest first, so "<=" is found before "<"
+var fieldOps = []FieldOp{FieldNe, FieldLe, FieldGe, FieldEq, FieldLt, FieldGt}
+
+// FieldFilter matches checkpoints by a custom field value
+type FieldFilter struct {
+	Key   string
+	Op    FieldOp
+	Value string
+}
+
+// ParseFieldFilter parses an expression such as "level>=10" or "chapter=3"
+func ParseFieldFilter(s string) (FieldFilter, error) {
+	for _, op := range fieldOps {
+		if i := strings.Index(s, string(op)); i > 0 {
+			return FieldFilter{
+				Key:   strings.ToLower(strings.TrimSpace(s[:i])),
+				Op:    op,
+				Value: strings.TrimSpace(s[i+len(op):]),
+			}, nil
+		}
+	}
+	return FieldFilter{}, fmt.Errorf("%w: filter %q must look like key=value or key>=value", ErrInvalidField, s)
+}
+
+// Matches reports whether a checkpoint passes the filter. Checkpoints
+// without the field only match "!=".
+func (f *FieldFilter) Matches(def *FieldDef, cp *Checkpoint) bool {
+	value, ok := cp.Fields[f.Key]
+	if !ok {
+		return f.Op == FieldNe
+	}
+
+	want := f.Value
+	if v, err := def.Normalize(f.Value); err == nil {
+		want = v
+	}
+
+	c := def.Compare(value, want)
+	switch f.Op {
+	case FieldEq:
+		return c == 0
+	case FieldNe:
+		return c != 0
+	case FieldLt:
+		return c < 0
+	case FieldLe:
+		return c <= 0
+	case FieldGt:
+		return c > 0
+	case FieldGe:
+		return c >= 0
+	}
+	return false
+}
+
+// SortedFieldKeys returns a checkpoint's field keys in the order the game
+// defines them, followed by any no longer defined, alphabetically
+func SortedFieldKeys(defs []FieldDef, fields map[string]string) []string {
+	keys := make([]string, 0, len(fields))
+	for _, def := range defs {
+		if _, ok := fields[def.Key]; ok {
+			keys = append(keys, def.Key)
+		}
+	}
+
+	var rest []string
+	for key := range fields {
+		if FindField(defs, key) == nil {
+			rest = append(rest, key)
+		}
+	}
+	sort.Strings(rest)
+	return append(keys, rest...)
+}
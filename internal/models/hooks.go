@@ -0,0 +1,130 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DefaultHookTimeoutSeconds is how long a hook may run when it doesn't say
+const DefaultHookTimeoutSeconds = 30
+
+// HookEvent is the point in an operation where a hook runs
+type HookEvent string
+
+const (
+	HookPreCheckpoint  HookEvent = "pre-checkpoint"
+	HookPostCheckpoint HookEvent = "post-checkpoint"
+	HookPreRestore     HookEvent = "pre-restore"
+	HookPostRestore    HookEvent = "post-restore"
+	HookPreDelete      HookEvent = "pre-delete"
+	HookPostDelete     HookEvent = "post-delete"
+)
+
+// AllHookEvents lists every hook event, for validation and pickers
+var AllHookEvents = []HookEvent{
+	HookPreCheckpoint,
+	HookPostCheckpoint,
+	HookPreRestore,
+	HookPostRestore,
+	HookPreDelete,
+	HookPostDelete,
+}
+
+// Hook is a user command run around an operation. Pre hooks that exit
+// non-zero or time out stop the operation. Post hooks run after every
+// attempt that got as far as the pre hooks, even failed or stopped ones,
+// so they can undo what the pre hooks did; their failures are only
+// reported.
+type Hook struct {
+	Event          HookEvent `json:"event"`
+	Command        string    `json:"command"` // Run through the system shell
+	TimeoutSeconds int       `json:"timeout_seconds,omitempty"`
+}
+
+// Validate validates a hook
+func (h *Hook) Validate() error {
+	known := false
+	for _, event := range AllHookEvents {
+		known = known || h.Event == event
+	}
+	if !known {
+		return fmt.Errorf("%w: unknown event %q", ErrInvalidHook, h.Event)
+	}
+	if strings.TrimSpace(h.Command) == "" {
+		return fmt.Errorf("%w: empty command", ErrInvalidHook)
+	}
+	if h.TimeoutSeconds < 0 {
+		return fmt.Errorf("%w: negative timeout", ErrInvalidHook)
+	}
+	return nil
+}
+
+// Timeout returns how long the hook may run
+func (h *Hook) Timeout() time.Duration {
+	if h.TimeoutSeconds == 0 {
+		return DefaultHookTimeoutSeconds * time.Second
+	}
+	return time.Duration(h.TimeoutSeconds) * time.Second
+}
+
+// Hook outcomes, telling post hooks how the operation went
+const (
+	HookOutcomeSuccess   = "success"
+	HookOutcomeFailure   = "failure"
+	HookOutcomeUnchanged = "unchanged" // No checkpoint: the saves hadn't changed
+)
+
+// HookContext describes an operation to its hooks. It is passed as JSON on
+// stdin and as GAMEKEEP_* environment variables.
+type HookContext struct {
+	Event          HookEvent `json:"event"`
+	GameID         string    `json:"game_id"`
+	GameName       string    `json:"game_name"`
+	SavePath       string    `json:"save_path"`
+	CheckpointID   string    `json:"checkpoint_id,omitempty"` // Only once a checkpoint is saved
+	CheckpointName string    `json:"checkpoint_name,omitempty"`
+	Origin         Origin    `json:"origin"`
+
+	// Outcome and Error are set for post hooks: check Outcome, not
+	// CheckpointID, to learn whether the operation worked
+	Outcome string `json:"outcome,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// Env returns the context as environment variables
+func (c *HookContext) Env() []string {
+	return []string{
+		"GAMEKEEP_EVENT=" + string(c.Event),
+		"GAMEKEEP_GAME_ID=" + c.GameID,
+		"GAMEKEEP_GAME_NAME=" + c.GameName,
+		"GAMEKEEP_SAVE_PATH=" + c.SavePath,
+		"GAMEKEEP_CHECKPOINT_ID=" + c.CheckpointID,
+		"GAMEKEEP_CHECKPOINT_NAME=" + c.CheckpointName,
+		"GAMEKEEP_ORIGIN=" + string(c.Origin),
+		"GAMEKEEP_OUTCOME=" + c.Outcome,
+		"GAMEKEEP_ERROR=" + c.Error,
+	}
+}
+
+// HookVetoError is returned when a pre hook stops an operation. It matches
+// ErrHookVetoed with errors.Is.
+type HookVetoError struct {
+	Event   HookEvent
+	Command string
+	Err     error
+}
+
+func (e *HookVetoError) Error() string {
+	return fmt.Sprintf("%s hook %q stopped the operation: %v", e.Event, e.Command, e.Err)
+}
+
+// Is reports whether target is ErrHookVetoed
+func (e *HookVetoError) Is(target error) bool {
+	return target == ErrHookVetoed
+}
+
+// Unwrap returns why the hook failed
+func (e *HookVetoError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,116 @@
+package models
+
+import (
+	"time"
+)
+
+// Operation identifies a journaled service operation
+type Operation string
+
+const (
+	OpAddGame           Operation = "add-game"
+	OpUpdateGame        Operation = "update-game"
+	OpRemoveGame        Operation = "remove-game"
+	OpExportGame        Operation = "export-game"
+	OpImportBundle      Operation = "import-bundle"
+	OpCreateCheckpoint  Operation = "create-checkpoint"
+	OpRestoreCheckpoint Operation = "restore-checkpoint"
+	OpUpdateCheckpoint  Operation = "update-checkpoint"
+	OpDeleteCheckpoint  Operation = "delete-checkpoint"
+	OpRestoreFromTrash  Operation = "restore-from-trash"
+	OpDeleteFromTrash   Operation = "delete-from-trash"
+	OpEmptyTrash        Operation = "empty-trash"
+	OpPurgeTrash        Operation = "purge-trash"
+	OpUpdateSettings    Operation = "update-settings"
+	OpMigrateGameIDs    Operation = "migrate-game-ids"
+	OpRunGame           Operation = "run-game"
+	OpPlaySession       Operation = "play-session"
+	OpAddSchedule       Operation = "add-schedule"
+	OpUpdateSchedule    Operation = "update-schedule"
+	OpRemoveSchedule    Operation = "remove-schedule"
+)
+
+// AllOperations lists every journaled operation, for filters and pickers
+var AllOperations = []Operation{
+	OpAddGame,
+	OpUpdateGame,
+	OpRemoveGame,
+	OpExportGame,
+	OpImportBundle,
+	OpCreateCheckpoint,
+	OpRestoreCheckpoint,
+	OpUpdateCheckpoint,
+	OpDeleteCheckpoint,
+	OpRestoreFromTrash,
+	OpDeleteFromTrash,
+	OpEmptyTrash,
+	OpPurgeTrash,
+	OpUpdateSettings,
+	OpMigrateGameIDs,
+	OpRunGame,
+	OpPlaySession,
+	OpAddSchedule,
+	OpUpdateSchedule,
+	OpRemoveSchedule,
+}
+
+// Outcome is the result of a journaled operation
+type Outcome string
+
+const (
+	OutcomeSuccess Outcome = "success"
+	OutcomeFailure Outcome = "failure"
+)
+
+// Origin identifies which front end performed an operation
+type Origin string
+
+const (
+	OriginCLI    Origin = "cli"
+	OriginGUI    Origin = "gui"
+	OriginDaemon Origin = "daemon"
+)
+
+// JournalEntry is one record in the append-only operations journal
+type JournalEntry struct {
+	Timestamp    time.Time `json:"timestamp"`
+	Operation    Operation `json:"operation"`
+	GameID       string    `json:"game_id,omitempty"`
+	CheckpointID string    `json:"checkpoint_id,omitempty"`
+	Detail       string    `json:"detail,omitempty"`
+	Outcome      Outcome   `json:"outcome"`
+	Error        string    `json:"error,omitempty"`
+	Origin       Origin    `json:"origin"`
+	Hostname     string    `json:"hostname"`
+	PID          int       `json:"pid,omitempty"`
+}
+
+// JournalFilter selects journal entries; zero-valued fields match everything
+type JournalFilter struct {
+	GameID     string
+	Operation  Operation
+	Origin     Origin
+	Since      time.Time
+	FailedOnly bool
+	Limit      int
+}
+
+// Matches reports whether an entry passes the filter (Limit is not considered)
+func (f *JournalFilter) Matches(entry *JournalEntry) bool {
+	if f.GameID != "" && entry.GameID != f.GameID {
+		return false
+	}
+	if f.Operation != "" && entry.Operation != f.Operation {
+		return false
+	}
+	if f.Origin != "" && entry.Origin != f.Origin {
+		return false
+	}
+	if !f.Since.IsZero() && entry.Timestamp.Before(f.Since) {
+		return false
+	}
+	if f.FailedOnly && entry.Outcome != OutcomeFailure {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,72 @@
+package models
+
+import "fmt"
+
+// ArchiveLimits bounds what an archive may unpack to, so a tampered
+// checkpoint or an imported bundle can't fill the disk or exhaust memory.
+// Zero turns a limit off.
+type ArchiveLimits struct {
+	MaxSizeMB  int64 `json:"max_size_mb"` // Uncompressed size of all entries together
+	MaxEntries int   `json:"max_entries"`
+	MaxRatio   int   `json:"max_ratio"` // Uncompressed to compressed size of one entry
+	MaxDepth   int   `json:"max_depth"` // Directories an entry's path may be nested in
+}
+
+// DefaultArchiveLimits returns limits well beyond any real save directory.
+// The ratio is set for deflate, which can't pass about 1032:1; zstd entries
+// are allowed more, as zstd packs repetitive files at up to 11000:1.
+func DefaultArchiveLimits() ArchiveLimits {
+	return ArchiveLimits{
+		MaxSizeMB:  16 << 10,
+		MaxEntries: 100000,
+		MaxRatio:   1000,
+		MaxDepth:   32,
+	}
+}
+
+// Validate validates archive limits
+func (l *ArchiveLimits) Validate() error {
+	if l.MaxSizeMB < 0 || l.MaxEntries < 0 || l.MaxRatio < 0 || l.MaxDepth < 0 {
+		return fmt.Errorf("%w: archive limits cannot be negative", ErrInvalidSettings)
+	}
+	return nil
+}
+
+// ArchiveLimit names one of the ArchiveLimits
+type ArchiveLimit string
+
+const (
+	LimitSize    ArchiveLimit = "size"
+	LimitEntries ArchiveLimit = "entries"
+	LimitRatio   ArchiveLimit = "ratio"
+	LimitDepth   ArchiveLimit = "depth"
+)
+
+// ArchiveLimitError is returned when an archive goes over one of its
+// limits, before or during extraction. It matches ErrArchiveLimit with
+// errors.Is.
+type ArchiveLimitError struct {
+	Limit ArchiveLimit
+	Entry string // The entry that went over, for the ratio and depth limits
+	Value int64  // Bytes for the size limit, as is Max
+	Max   int64
+}
+
+func (e *ArchiveLimitError) Error() string {
+	switch e.Limit {
+	case LimitSize:
+		return fmt.Sprintf("archive unpacks to more than the %d MB allowed", e.Max>>20)
+	case LimitEntries:
+		return fmt.Sprintf("archive has %d entries, more than the %d allowed", e.Value, e.Max)
+	case LimitRatio:
+		return fmt.Sprintf("%s compresses more than the %d:1 allowed", e.Entry, e.Max)
+	case LimitDepth:
+		return fmt.Sprintf("%s is nested %d directories deep, more than the %d allowed", e.Entry, e.Value, e.Max)
+	}
+	return fmt.Sprintf("archive exceeds its %s limit", e.Limit)
+}
+
+// Is reports whether target is ErrArchiveLimit
+func (e *ArchiveLimitError) Is(target error) bool {
+	return target == ErrArchiveLimit
+}
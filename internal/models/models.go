@@ -1,6 +1,8 @@
 package models
 
 import (
+	"fmt"
+	"strings"
 	"time"
 )
 
@@ -9,6 +11,141 @@ type Game struct {
 	ID       string `json:"id"`
 	Name     string `json:"name"`
 	SavePath string `json:"save_path"`
+	Archived bool   `json:"archived,omitempty"`
+
+	// HeadCheckpointID is the checkpoint last created or restored, which
+	// becomes the parent of the next checkpoint
+	HeadCheckpointID string `json:"head_checkpoint_id,omitempty"`
+
+	// Fields defines the custom fields this game's checkpoints can carry
+	Fields []FieldDef `json:"fields,omitempty"`
+
+	// Inspector names the save inspector to use; empty tries each
+	// registered inspector and InspectorNone turns inspection off
+	Inspector string `json:"inspector,omitempty"`
+
+	Platform      string `json:"platform,omitempty"`       // Store or launcher, e.g. "Steam"
+	Executable    string `json:"executable,omitempty"`     // Path to the game's executable
+	LaunchCommand string `json:"launch_command,omitempty"` // Command that starts the game
+	Notes         string `json:"notes,omitempty"`
+
+	// CoverImage is the cover's path relative to the vault root
+	CoverImage string `json:"cover_image,omitempty"`
+
+	// CreatedAt is zero for games registered by older versions
+	CreatedAt    time.Time  `json:"created_at"`
+	LastPlayedAt *time.Time `json:"last_played_at,omitempty"`
+
+	// PlaytimeSeconds totals the recorded play sessions
+	PlaytimeSeconds int64 `json:"playtime_seconds,omitempty"`
+
+	// ProcessNames are extra process names the watcher recognizes the game
+	// by, besides the executable's
+	ProcessNames []string `json:"process_names,omitempty"`
+
+	// Hooks run around this game's operations, after the global ones
+	Hooks []Hook `json:"hooks,omitempty"`
+
+	// Compression is how this game's checkpoints are compressed; nil uses
+	// DefaultCompression
+	Compression *Compression `json:"compression,omitempty"`
+}
+
+// ArchiveCompression returns how to compress the game's checkpoints
+func (g *Game) ArchiveCompression() Compression {
+	if g.Compression == nil {
+		return DefaultCompression()
+	}
+	return *g.Compression
+}
+
+// GameOptions holds optional details for a new game
+type GameOptions struct {
+	Platform      string
+	Executable    string
+	LaunchCommand string
+	Notes         string
+	CoverImage    string // Path of an image to copy into the vault
+}
+
+// RunOptions controls a game session started by the run command
+type RunOptions struct {
+	SkipPreCheckpoint  bool
+	SkipPostCheckpoint bool
+
+	// OnWarning is told about problems that don't stop the game, as they
+	// happen; nil ignores them
+	OnWarning func(error)
+}
+
+// Session describes one run of a game
+type Session struct {
+	GameID           string
+	StartedAt        time.Time
+	EndedAt          time.Time
+	ExitCode         int
+	PreCheckpointID  string // Empty if skipped or failed, or the saves matched the latest checkpoint
+	PostCheckpointID string // Empty if the saves didn't change, or skipped or failed
+	SavesChanged     bool
+}
+
+// Duration returns how long the session lasted
+func (s *Session) Duration() time.Duration {
+	return s.EndedAt.Sub(s.StartedAt)
+}
+
+// WatchEventType is what a game watcher noticed
+type WatchEventType string
+
+const (
+	WatchStarted WatchEventType = "started"
+	WatchStopped WatchEventType = "stopped"
+	WatchError   WatchEventType = "error" // Processes or games could not be read
+)
+
+// WatchOptions controls a game watcher
+type WatchOptions struct {
+	// Interval between looks at the running processes; zero uses the
+	// WatchIntervalSeconds setting
+	Interval time.Duration
+
+	// SkipExitCheckpoint turns off checkpoints on exit even if the
+	// CheckpointOnExit setting is on
+	SkipExitCheckpoint bool
+
+	// OnEvent is told about each event as it happens; nil ignores them
+	OnEvent func(WatchEvent)
+}
+
+// WatchEvent reports a game starting or stopping while being watched
+type WatchEvent struct {
+	Type     WatchEventType
+	GameID   string
+	GameName string
+	PIDs     []int
+	At       time.Time
+
+	// Session is set for stopped events. The watcher can't see exit codes,
+	// so ExitCode is always zero.
+	Session *Session
+
+	// Err reports a problem recording a stopped session; the event still happened
+	Err error
+}
+
+// RunningGame is a game the watcher currently sees running
+type RunningGame struct {
+	GameID    string
+	GameName  string
+	PIDs      []int
+	StartedAt time.Time
+}
+
+// GameStats summarizes a game's checkpoints
+type GameStats struct {
+	CheckpointCount  int
+	TotalSize        int64
+	LastCheckpointAt time.Time // Zero if there are no checkpoints
 }
 
 // Checkpoint represents a save state snapshot
@@ -20,6 +157,147 @@ type Checkpoint struct {
 	VaultFile string    `json:"vault_file"`
 	Hash      string    `json:"hash"`
 	CreatedAt time.Time `json:"created_at"`
+	Tags      []string  `json:"tags,omitempty"`
+	Protected bool      `json:"protected,omitempty"` // Refuses deletion unless forced
+	ParentID  string    `json:"parent_id,omitempty"` // Game head when this was created
+
+	// Fields holds custom field values in canonical form, keyed by FieldDef.Key
+	Fields map[string]string `json:"fields,omitempty"`
+
+	// SaveInfo is what a save inspector read from the saves when this was created
+	SaveInfo *SaveMetadata `json:"save_info,omitempty"`
+
+	// SaveFingerprint and ContentHash describe the saves this was created
+	// from, so unchanged saves aren't checkpointed again. Empty for
+	// checkpoints made by older versions.
+	SaveFingerprint string `json:"save_fingerprint,omitempty"` // Names, sizes and modification times
+	ContentHash     string `json:"content_hash,omitempty"`     // Names and contents
+
+	// Codec is how the archive's files were compressed, though files that
+	// barely compressed were stored. Empty for checkpoints made by older
+	// versions, which always used deflate.
+	Codec Codec `json:"codec,omitempty"`
+}
+
+// CheckpointOptions holds optional settings for a new checkpoint
+type CheckpointOptions struct {
+	Fields map[string]string
+
+	// Force creates the checkpoint even if the saves match the game's
+	// latest checkpoint
+	Force bool
+
+	// Progress receives reports while the saves are archived; may be nil
+	Progress ProgressFunc
+}
+
+// CheckpointQuery selects and orders a game's checkpoints
+type CheckpointQuery struct {
+	Tag     string
+	Filters []FieldFilter
+
+	// SortBy is "created", "name" or a custom field key; empty keeps
+	// creation order
+	SortBy     string
+	Descending bool
+}
+
+// CheckpointNode is a checkpoint in a game's lineage tree
+type CheckpointNode struct {
+	Checkpoint Checkpoint
+	Children   []*CheckpointNode
+}
+
+// GameUpdate holds the game fields to change; nil fields are left as they are
+type GameUpdate struct {
+	Name      *string
+	SavePath  *string
+	Archived  *bool
+	Fields    *[]FieldDef // Replaces all field definitions
+	Inspector *string
+
+	Platform      *string
+	Executable    *string
+	LaunchCommand *string
+	Notes         *string
+	CoverImage    *string // Path of an image to copy into the vault; "" removes the cover
+	ProcessNames  *[]string
+	Hooks         *[]Hook      // Replaces all of the game's hooks
+	Compression   *Compression // A zero Compression goes back to the default
+}
+
+// CheckpointUpdate holds the checkpoint fields to change; nil fields are left as they are
+type CheckpointUpdate struct {
+	Name      *string
+	Note      *string
+	Tags      *[]string // Replaces all tags
+	Protected *bool
+	Fields    map[string]string // Merged into existing values; "" removes one
+}
+
+// TrashEntry is a deleted checkpoint held in the recycle bin
+type TrashEntry struct {
+	Checkpoint Checkpoint `json:"checkpoint"`
+	Game       *Game      `json:"game,omitempty"` // Set when the game was removed too
+	TrashFile  string     `json:"trash_file"`
+	DeletedAt  time.Time  `json:"deleted_at"`
+	ExpiresAt  time.Time  `json:"expires_at,omitempty"`
+}
+
+// Expired reports whether the entry is past its expiry time
+func (e *TrashEntry) Expired(now time.Time) bool {
+	return !e.ExpiresAt.IsZero() && now.After(e.ExpiresAt)
+}
+
+// RemoveMode selects what happens to a removed game's checkpoints
+type RemoveMode string
+
+const (
+	// RemoveKeep archives the game, keeping its checkpoints as a hidden group
+	RemoveKeep RemoveMode = "keep"
+
+	// RemoveDelete deletes the checkpoints along with the game's vault directory
+	RemoveDelete RemoveMode = "delete"
+
+	// RemoveExport exports the checkpoints to a bundle, then deletes them
+	RemoveExport RemoveMode = "export"
+)
+
+// RemoveGameOptions controls how a game is removed
+type RemoveGameOptions struct {
+	Mode RemoveMode
+
+	// ExportPath is the bundle file written in RemoveExport mode
+	ExportPath string
+
+	// Permanent skips the trash when deleting checkpoints
+	Permanent bool
+
+	// Force deletes protected checkpoints too
+	Force bool
+}
+
+// Validate validates remove options
+func (o *RemoveGameOptions) Validate() error {
+	switch o.Mode {
+	case RemoveKeep, RemoveDelete:
+	case RemoveExport:
+		if o.ExportPath == "" {
+			return ErrInvalidPath
+		}
+	default:
+		return ErrInvalidRemoveMode
+	}
+	return nil
+}
+
+// RemoveGameSummary describes what removing a game affects
+type RemoveGameSummary struct {
+	Game            Game
+	CheckpointCount int
+	ProtectedCount  int   // Checkpoints that need Force to be deleted
+	VaultBytes      int64 // Size of the game's checkpoint archives
+	FreedBytes      int64 // Space freed right away by the chosen mode
 }
 
 // Validate validates game fields
@@ -30,6 +308,27 @@ func (g *Game) Validate() error {
 	if g.SavePath == "" {
 		return ErrEmptySavePath
 	}
+
+	seen := make(map[string]bool, len(g.Fields))
+	for _, def := range g.Fields {
+		if err := def.Validate(); err != nil {
+			return err
+		}
+		if seen[def.Key] {
+			return fmt.Errorf("%w: %s is defined twice", ErrInvalidField, def.Key)
+		}
+		seen[def.Key] = true
+	}
+
+	for i := range g.Hooks {
+		if err := g.Hooks[i].Validate(); err != nil {
+			return err
+		}
+	}
+
+	if g.Compression != nil {
+		return g.Compression.Validate()
+	}
 	return nil
 }
 
@@ -43,3 +342,32 @@ func (c *Checkpoint) Validate() error {
 	}
 	return nil
 }
+
+// HasTag reports whether the checkpoint has a tag, ignoring case
+func (c *Checkpoint) HasTag(tag string) bool {
+	for _, t := range c.Tags {
+		if strings.EqualFold(t, strings.TrimSpace(tag)) {
+			return true
+		}
+	}
+	return false
+}
+
+// NormalizeTags trims and lowercases tags and drops duplicates, keeping
+// their order. Tags cannot be empty or contain commas, which separate tags
+// on the command line.
+func NormalizeTags(tags []string) ([]string, error) {
+	normalized := []string{}
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || strings.Contains(tag, ",") {
+			return nil, ErrInvalidTag
+		}
+		if !seen[tag] {
+			seen[tag] = true
+			normalized = append(normalized, tag)
+		}
+	}
+	return normalized, nil
+}
@@ -0,0 +1,94 @@
+package models
+
+import (
+	"strconv"
+	"strings"
+)
+
+// InspectorNone turns off save inspection for a game
+const InspectorNone = "none"
+
+// SaveMetadata is what a save inspector read from a game's save files
+type SaveMetadata struct {
+	// Inspector is the name of the inspector that produced this
+	Inspector string `json:"inspector"`
+
+	// Level, Location and PlaytimeSeconds describe the latest save;
+	// inspectors leave them empty when the format doesn't record them
+	Level           string `json:"level,omitempty"`
+	Location        string `json:"location,omitempty"`
+	PlaytimeSeconds int64  `json:"playtime_seconds,omitempty"`
+
+	// Slots names the save slots found, latest first
+	Slots []string `json:"slots,omitempty"`
+
+	// Extra holds other simple values from the latest save, keyed in the
+	// same form as custom field keys
+	Extra map[string]string `json:"extra,omitempty"`
+}
+
+// IsEmpty reports whether the inspector found nothing worth recording
+func (m *SaveMetadata) IsEmpty() bool {
+	return m.Level == "" && m.Location == "" && m.PlaytimeSeconds == 0 &&
+		len(m.Slots) == 0 && len(m.Extra) == 0
+}
+
+// Values flattens the metadata into custom field values: "level",
+// "location", "playtime" (in seconds), "slot" (the latest slot) and "slots"
+// (comma separated), plus everything in Extra
+func (m *SaveMetadata) Values() map[string]string {
+	values := make(map[string]string, len(m.Extra)+5)
+	for key, value := range m.Extra {
+		values[key] = value
+	}
+	if m.Level != "" {
+		values["level"] = m.Level
+	}
+	if m.Location != "" {
+		values["location"] = m.Location
+	}
+	if m.PlaytimeSeconds > 0 {
+		values["playtime"] = strconv.FormatInt(m.PlaytimeSeconds, 10)
+	}
+	if len(m.Slots) > 0 {
+		values["slot"] = m.Slots[0]
+		values["slots"] = strings.Join(m.Slots, ", ")
+	}
+	return values
+}
+
+// Summary formats the level, location, playtime and latest slot on one
+// line, e.g. "Level 12 · Forest · 1h30m · Slot 2"
+func (m *SaveMetadata) Summary() string {
+	var parts []string
+	if m.Level != "" {
+		parts = append(parts, "Level "+m.Level)
+	}
+	if m.Location != "" {
+		parts = append(parts, m.Location)
+	}
+	if m.PlaytimeSeconds > 0 {
+		parts = append(parts, FormatPlaytime(m.PlaytimeSeconds))
+	}
+	if len(m.Slots) > 0 {
+		parts = append(parts, m.Slots[0])
+	}
+	return strings.Join(parts, " · ")
+}
+
+// FormatPlaytime formats a playtime in seconds as "12h05m" or "5m"
+func FormatPlaytime(seconds int64) string {
+	hours, minutes := seconds/3600, seconds/60%60
+	if hours == 0 {
+		return strconv.FormatInt(minutes, 10) + "m"
+	}
+	return strconv.FormatInt(hours, 10) + "h" + pad2(minutes) + "m"
+}
+
+// pad2 formats n with at least two digits
+func pad2(n int64) string {
+	if n < 10 {
+		return "0" + strconv.FormatInt(n, 10)
+	}
+	return strconv.FormatInt(n, 10)
+}
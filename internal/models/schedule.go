@@ -0,0 +1,129 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ScheduleKind is how a schedule decides when to run
+type ScheduleKind string
+
+const (
+	ScheduleInterval ScheduleKind = "interval" // Every N minutes
+	ScheduleDaily    ScheduleKind = "daily"    // Once a day at a local time
+	ScheduleCron     ScheduleKind = "cron"     // A cron expression, in local time
+)
+
+// ScheduleTimeLayout is the format of daily schedule times
+const ScheduleTimeLayout = "15:04"
+
+// Schedule takes checkpoints of a game automatically. Runs are skipped
+// while the saves match the game's latest checkpoint.
+type Schedule struct {
+	ID              string       `json:"id"`
+	GameID          string       `json:"game_id"`
+	Kind            ScheduleKind `json:"kind"`
+	IntervalMinutes int          `json:"interval_minutes,omitempty"`
+	DailyAt         string       `json:"daily_at,omitempty"` // In ScheduleTimeLayout
+	Cron            string       `json:"cron,omitempty"`
+	Paused          bool         `json:"paused,omitempty"`
+	CreatedAt       time.Time    `json:"created_at"`
+
+	// Last run state, kept so restarts neither repeat nor forget runs
+	LastRunAt        *time.Time `json:"last_run_at,omitempty"`
+	LastCheckpointID string     `json:"last_checkpoint_id,omitempty"`
+	LastError        string     `json:"last_error,omitempty"`
+}
+
+// Validate validates a schedule's timing
+func (s *Schedule) Validate() error {
+	switch s.Kind {
+	case ScheduleInterval:
+		if s.IntervalMinutes < 1 {
+			return fmt.Errorf("%w: interval must be at least a minute", ErrInvalidSchedule)
+		}
+	case ScheduleDaily:
+		if _, err := time.Parse(ScheduleTimeLayout, s.DailyAt); err != nil {
+			return fmt.Errorf("%w: daily time must look like 21:30, got %q", ErrInvalidSchedule, s.DailyAt)
+		}
+	case ScheduleCron:
+		if _, err := ParseCron(s.Cron); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("%w: unknown kind %q", ErrInvalidSchedule, s.Kind)
+	}
+	return nil
+}
+
+// Next returns the first time after t the schedule should run, in t's
+// location; zero if it never will
+func (s *Schedule) Next(t time.Time) time.Time {
+	switch s.Kind {
+	case ScheduleInterval:
+		return t.Add(time.Duration(s.IntervalMinutes) * time.Minute)
+	case ScheduleDaily:
+		at, err := time.Parse(ScheduleTimeLayout, s.DailyAt)
+		if err != nil {
+			return time.Time{}
+		}
+		next := time.Date(t.Year(), t.Month(), t.Day(), at.Hour(), at.Minute(), 0, 0, t.Location())
+		if !next.After(t) {
+			next = time.Date(t.Year(), t.Month(), t.Day()+1, at.Hour(), at.Minute(), 0, 0, t.Location())
+		}
+		return next
+	case ScheduleCron:
+		expr, err := ParseCron(s.Cron)
+		if err != nil {
+			return time.Time{}
+		}
+		return expr.Next(t)
+	}
+	return time.Time{}
+}
+
+// NextRun returns when the schedule is next due, counting from its last
+// run or, if it never ran, its creation. A time in the past means it is
+// due now.
+func (s *Schedule) NextRun() time.Time {
+	from := s.CreatedAt
+	if s.LastRunAt != nil {
+		from = *s.LastRunAt
+	}
+	return s.Next(from.Local())
+}
+
+// Describe summarizes when the schedule runs, e.g. "every 30m"
+func (s *Schedule) Describe() string {
+	switch s.Kind {
+	case ScheduleInterval:
+		every := strings.TrimSuffix((time.Duration(s.IntervalMinutes) * time.Minute).String(), "0s")
+		if strings.HasSuffix(every, "h0m") {
+			every = strings.TrimSuffix(every, "0m")
+		}
+		return "every " + every
+	case ScheduleDaily:
+		return "daily at " + s.DailyAt
+	case ScheduleCron:
+		return "cron " + s.Cron
+	}
+	return string(s.Kind)
+}
+
+// ScheduleRun is the outcome of one run of a schedule
+type ScheduleRun struct {
+	ScheduleID   string
+	GameID       string
+	GameName     string
+	At           time.Time
+	CheckpointID string // Empty if skipped or failed
+	Skipped      bool   // The saves hadn't changed
+	Err          error
+}
+
+// SchedulerOptions controls a scheduler
+type SchedulerOptions struct {
+	// OnRun is told about each run as it finishes; nil ignores them
+	OnRun func(ScheduleRun)
+}
@@ -0,0 +1,66 @@
+package models
+
+// DefaultTrashRetentionDays is how long deleted checkpoints stay in the trash
+const DefaultTrashRetentionDays = 30
+
+// DefaultWatchIntervalSeconds is how often the game watcher looks for processes
+const DefaultWatchIntervalSeconds = 5
+
+// Settings holds user-configurable application settings
+type Settings struct {
+	// TrashRetentionDays is how long deleted checkpoints are kept; 0 keeps them until emptied
+	TrashRetentionDays int `json:"trash_retention_days"`
+
+	// WatchGames runs the game watcher while the GUI is open
+	WatchGames bool `json:"watch_games"`
+
+	// WatchIntervalSeconds is how often the watcher looks for game processes
+	WatchIntervalSeconds int `json:"watch_interval_seconds"`
+
+	// CheckpointOnExit has the watcher checkpoint a game's saves when it
+	// exits, if they changed
+	CheckpointOnExit bool `json:"checkpoint_on_exit"`
+
+	// CacheVerification skips re-reading a checkpoint archive to verify it
+	// before a restore when its size and modification time show it hasn't
+	// changed since it was last hashed
+	CacheVerification bool `json:"cache_verification"`
+
+	// CompressionWorkers is how many files are compressed at once when
+	// creating a checkpoint; 0 means one per CPU
+	CompressionWorkers int `json:"compression_workers"`
+
+	// ArchiveLimits bounds what restored and imported archives may unpack to
+	ArchiveLimits ArchiveLimits `json:"archive_limits"`
+
+	// Hooks run around every game's operations
+	Hooks []Hook `json:"hooks,omitempty"`
+}
+
+// DefaultSettings returns the settings used when none have been saved
+func DefaultSettings() Settings {
+	return Settings{
+		TrashRetentionDays:   DefaultTrashRetentionDays,
+		WatchGames:           true,
+		WatchIntervalSeconds: DefaultWatchIntervalSeconds,
+		CheckpointOnExit:     true,
+		CacheVerification:    true,
+		ArchiveLimits:        DefaultArchiveLimits(),
+	}
+}
+
+// Validate validates settings fields
+func (s *Settings) Validate() error {
+	if s.TrashRetentionDays < 0 || s.WatchIntervalSeconds < 1 || s.CompressionWorkers < 0 {
+		return ErrInvalidSettings
+	}
+	if err := s.ArchiveLimits.Validate(); err != nil {
+		return err
+	}
+	for i := range s.Hooks {
+		if err := s.Hooks[i].Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
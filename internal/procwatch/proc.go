@@ -0,0 +1,97 @@
+package procwatch
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// DefaultRoot is where the kernel exposes running processes
+const DefaultRoot = "/proc"
+
+// ErrUnsupported is returned where there is no /proc to read
+var ErrUnsupported = errors.New("process listing needs /proc, which this system does not have")
+
+// Process is a running process
+type Process struct {
+	PID  int
+	Name string   // Kernel's short name for the process, at most 15 bytes
+	Exe  string   // Executable path; empty if it cannot be read
+	Args []string // Command line; empty for kernel threads
+}
+
+// List returns the processes running on this system
+func List() ([]Process, error) {
+	return ListIn(DefaultRoot)
+}
+
+// ListIn reads processes from a /proc-style directory. Processes that exit
+// while being read, or that can't be inspected, are skipped or returned
+// with the fields that could be read.
+func ListIn(root string) ([]Process, error) {
+	entries, err := os.ReadDir(root)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrUnsupported
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list processes: %w", err)
+	}
+
+	var processes []Process
+	for _, entry := range entries {
+		pid, err := strconv.Atoi(entry.Name())
+		if err != nil || !entry.IsDir() {
+			continue
+		}
+
+		dir := filepath.Join(root, entry.Name())
+		comm, err := os.ReadFile(filepath.Join(dir, "comm"))
+		if err != nil {
+			// Gone already
+			continue
+		}
+
+		p := Process{
+			PID:  pid,
+			Name: strings.TrimSuffix(string(comm), "\n"),
+		}
+		if exe, err := os.Readlink(filepath.Join(dir, "exe")); err == nil {
+			p.Exe = strings.TrimSuffix(exe, " (deleted)")
+		}
+		if cmdline, err := os.ReadFile(filepath.Join(dir, "cmdline")); err == nil {
+			p.Args = splitCmdline(cmdline)
+		}
+
+		processes = append(processes, p)
+	}
+
+	return processes, nil
+}
+
+// splitCmdline splits a NUL-separated command line
+func splitCmdline(data []byte) []string {
+	data = bytes.TrimRight(data, "\x00")
+	if len(data) == 0 {
+		return nil
+	}
+
+	parts := bytes.Split(data, []byte{0})
+	args := make([]string, len(parts))
+	for i, part := range parts {
+		args[i] = string(part)
+	}
+	return args
+}
+
+// BaseName returns the file name at the end of a path written with either
+// slashes or backslashes, since Wine and Proton games show Windows paths
+func BaseName(path string) string {
+	if i := strings.LastIndexAny(path, `/\`); i >= 0 {
+		return path[i+1:]
+	}
+	return path
+}
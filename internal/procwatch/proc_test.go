@@ -0,0 +1,59 @@
+package procwatch
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestListIn(t *testing.T) {
+	root := t.TempDir()
+
+	writeProc := func(pid, comm, exe, cmdline string) {
+		t.Helper()
+		dir := filepath.Join(root, pid)
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatalf("MkdirAll: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "comm"), []byte(comm+"\n"), 0644); err != nil {
+			t.Fatalf("WriteFile: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "cmdline"), []byte(cmdline), 0644); err != nil {
+			t.Fatalf("WriteFile: %v", err)
+		}
+		if exe != "" {
+			if err := os.Symlink(exe, filepath.Join(dir, "exe")); err != nil {
+				t.Fatalf("Symlink: %v", err)
+			}
+		}
+	}
+
+	writeProc("42", "wine64-preload", "/usr/bin/wine64-preloader", "Z:\\games\\Witcher3\\witcher3.exe\x00-skipIntro\x00")
+	writeProc("7", "celeste", "/games/celeste/Celeste (deleted)", "/games/celeste/Celeste\x00")
+	writeProc("3", "kthreadd", "", "")
+	os.MkdirAll(filepath.Join(root, "self"), 0755)
+	os.MkdirAll(filepath.Join(root, "99"), 0755) // exited mid-scan
+
+	processes, err := ListIn(root)
+	if err != nil {
+		t.Fatalf("ListIn: %v", err)
+	}
+
+	want := []Process{
+		{PID: 3, Name: "kthreadd"},
+		{PID: 42, Name: "wine64-preload", Exe: "/usr/bin/wine64-preloader", Args: []string{`Z:\games\Witcher3\witcher3.exe`, "-skipIntro"}},
+		{PID: 7, Name: "celeste", Exe: "/games/celeste/Celeste", Args: []string{"/games/celeste/Celeste"}},
+	}
+	if !reflect.DeepEqual(processes, want) {
+		t.Fatalf("processes =\n%+v\nwant\n%+v", processes, want)
+	}
+
+	if got := BaseName(processes[1].Args[0]); got != "witcher3.exe" {
+		t.Errorf("BaseName = %q, want witcher3.exe", got)
+	}
+
+	if _, err := ListIn(filepath.Join(root, "missing")); err != ErrUnsupported {
+		t.Errorf("expected ErrUnsupported, got %v", err)
+	}
+}
@@ -1,12 +1,16 @@
 package storage
 
 import (
+	"bufio"
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"sync"
 
+	"github.com/adrielfilipedesign/gamekeep/internal/fsys"
 	"github.com/adrielfilipedesign/gamekeep/internal/models"
 )
 
@@ -15,31 +19,63 @@ type MetadataStore interface {
 	// Games
 	SaveGames(games []models.Game) error
 	LoadGames() ([]models.Game, error)
-	
+
 	// Checkpoints
 	SaveCheckpoints(checkpoints []models.Checkpoint) error
 	LoadCheckpoints() ([]models.Checkpoint, error)
+
+	// Trash
+	SaveTrash(entries []models.TrashEntry) error
+	LoadTrash() ([]models.TrashEntry, error)
+
+	// Settings
+	SaveSettings(settings models.Settings) error
+	LoadSettings() (models.Settings, error)
+
+	// Schedules
+	SaveSchedules(schedules []models.Schedule) error
+	LoadSchedules() ([]models.Schedule, error)
+
+	// Journal
+	AppendJournal(entry models.JournalEntry) error
+	LoadJournal() ([]models.JournalEntry, error)
+	ReadJournalFrom(offset int64) ([]models.JournalEntry, int64, error)
 }
 
 // JSONStore implements MetadataStore using JSON files
 type JSONStore struct {
-	configDir         string
-	gamesFile         string
-	checkpointsFile   string
-	mu                sync.RWMutex
+	fs              fsys.FS
+	configDir       string
+	gamesFile       string
+	checkpointsFile string
+	trashFile       string
+	settingsFile    string
+	journalFile     string
+	schedulesFile   string
+	mu              sync.RWMutex
 }
 
-// NewJSONStore creates a new JSON-based metadata store
+// NewJSONStore creates a new JSON-based metadata store on the OS filesystem
 func NewJSONStore(configDir string) (*JSONStore, error) {
+	return NewJSONStoreWithFS(fsys.OS{}, configDir)
+}
+
+// NewJSONStoreWithFS creates a new JSON-based metadata store on the given filesystem
+func NewJSONStoreWithFS(fs fsys.FS, configDir string) (*JSONStore, error) {
 	// Ensure config directory exists
-	if err := os.MkdirAll(configDir, 0755); err != nil {
+	if err := fs.MkdirAll(configDir, 0755); err != nil {
 		return nil, fmt.Errorf("failed to create config directory: %w", err)
 	}
 
 	return &JSONStore{
+		fs:              fs,
 		configDir:       configDir,
 		gamesFile:       filepath.Join(configDir, "games.json"),
 		checkpointsFile: filepath.Join(configDir, "checkpoints.json"),
+		trashFile:       filepath.Join(configDir, "trash.json"),
+		settingsFile:    filepath.Join(configDir, "settings.json"),
+		journalFile:     filepath.Join(configDir, "journal.jsonl"),
+		schedulesFile:   filepath.Join(configDir, "schedules.json"),
 	}, nil
 }
 
@@ -89,6 +125,215 @@ func (s *JSONStore) LoadCheckpoints() ([]models.Checkpoint, error) {
 	return checkpoints, nil
 }
 
+// SaveTrash saves trash entries to JSON file with atomic write
+func (s *JSONStore) SaveTrash(entries []models.TrashEntry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.atomicWriteJSON(s.trashFile, entries)
+}
+
+// LoadTrash loads trash entries from JSON file
+func (s *JSONStore) LoadTrash() ([]models.TrashEntry, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var entries []models.TrashEntry
+	if err := s.readJSON(s.trashFile, &entries); err != nil {
+		if os.IsNotExist(err) {
+			return []models.TrashEntry{}, nil
+		}
+		return nil, err
+	}
+	return entries, nil
+}
+
+// SaveSettings saves settings to JSON file with atomic write
+func (s *JSONStore) SaveSettings(settings models.Settings) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.atomicWriteJSON(s.settingsFile, settings)
+}
+
+// LoadSettings loads settings from JSON file, falling back to defaults
+// for the file or any fields that are missing
+func (s *JSONStore) LoadSettings() (models.Settings, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	settings := models.DefaultSettings()
+	if err := s.readJSON(s.settingsFile, &settings); err != nil {
+		if os.IsNotExist(err) {
+			return models.DefaultSettings(), nil
+		}
+		return models.Settings{}, err
+	}
+	return settings, nil
+}
+
+// SaveSchedules saves schedules to JSON file with atomic write
+func (s *JSONStore) SaveSchedules(schedules []models.Schedule) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.atomicWriteJSON(s.schedulesFile, schedules)
+}
+
+// LoadSchedules loads schedules from JSON file
+func (s *JSONStore) LoadSchedules() ([]models.Schedule, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var schedules []models.Schedule
+	if err := s.readJSON(s.schedulesFile, &schedules); err != nil {
+		if os.IsNotExist(err) {
+			return []models.Schedule{}, nil
+		}
+		return nil, err
+	}
+	return schedules, nil
+}
+
+// AppendJournal appends an entry to the JSON Lines journal file
+func (s *JSONStore) AppendJournal(entry models.JournalEntry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+
+	file, err := s.fs.OpenFile(s.journalFile, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open journal: %w", err)
+	}
+
+	// Single write so concurrent appenders never interleave within a line
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to append to journal: %w", err)
+	}
+
+	return file.Close()
+}
+
+// LoadJournal loads all journal entries in the order they were written.
+// Malformed lines, such as a partial write at the end, are skipped.
+func (s *JSONStore) LoadJournal() ([]models.JournalEntry, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	file, err := s.fs.Open(s.journalFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []models.JournalEntry{}, nil
+		}
+		return nil, err
+	}
+	defer file.Close()
+
+	entries := []models.JournalEntry{}
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var entry models.JournalEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read journal: %w", err)
+	}
+
+	return entries, nil
+}
+
+// ReadJournalFrom reads the journal entries written since offset, which an
+// earlier call returned, and returns the offset to read from next time.
+// Only whole lines are read, so an entry still being written is read next
+// time. A negative offset, or one a truncated or replaced journal no longer
+// lines up with, skips to the end of the journal without reading it.
+func (s *JSONStore) ReadJournalFrom(offset int64) ([]models.JournalEntry, int64, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	file, err := s.fs.Open(s.journalFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []models.JournalEntry{}, 0, nil
+		}
+		return nil, offset, err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, offset, err
+	}
+	if offset > info.Size() || (offset > 0 && !endsLine(file, offset)) {
+		offset = -1
+	}
+	if offset < 0 {
+		end, err := lastLineEnd(file, info.Size())
+		return []models.JournalEntry{}, end, err
+	}
+
+	if _, err := file.Seek(offset, io.SeekStart); err != nil {
+		return nil, offset, err
+	}
+	entries := []models.JournalEntry{}
+	reader := bufio.NewReader(file)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err == io.EOF {
+			break // A partial line, or nothing
+		}
+		if err != nil {
+			return nil, offset, fmt.Errorf("failed to read journal: %w", err)
+		}
+		offset += int64(len(line))
+
+		var entry models.JournalEntry
+		if err := json.Unmarshal(line, &entry); err != nil {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	return entries, offset, nil
+}
+
+// endsLine reports whether the byte before offset ends a line, as it does
+// wherever a journal read stopped
+func endsLine(file fsys.File, offset int64) bool {
+	b := make([]byte, 1)
+	_, err := file.ReadAt(b, offset-1)
+	return err == nil && b[0] == '\n'
+}
+
+// lastLineEnd returns the offset just past the last whole line of a file
+// size bytes long, reading it backwards
+func lastLineEnd(file fsys.File, size int64) (int64, error) {
+	buf := make([]byte, 4096)
+	for end := size; end > 0; {
+		start := end - int64(len(buf))
+		if start < 0 {
+			start = 0
+		}
+		chunk := buf[:end-start]
+		if _, err := file.ReadAt(chunk, start); err != nil && err != io.EOF {
+			return 0, err
+		}
+		if i := bytes.LastIndexByte(chunk, '\n'); i >= 0 {
+			return start + int64(i) + 1, nil
+		}
+		end = start
+	}
+	return 0, nil
+}
+
 // atomicWriteJSON writes JSON data atomically using temp file + rename
 func (s *JSONStore) atomicWriteJSON(filepath string, data interface{}) error {
 	// Marshal with indentation for readability
@@ -99,15 +344,16 @@ func (s *JSONStore) atomicWriteJSON(filepath string, data interface{}) error {
 
 	// Create temporary file in the same directory
 	tmpFile := filepath + ".tmp"
-	
+
 	// Write to temp file
-	if err := os.WriteFile(tmpFile, jsonData, 0644); err != nil {
+	if err := fsys.WriteFile(s.fs, tmpFile, jsonData, 0644); err != nil {
+		s.fs.Remove(tmpFile)
 		return fmt.Errorf("failed to write temp file: %w", err)
 	}
 
 	// Atomic rename
-	if err := os.Rename(tmpFile, filepath); err != nil {
-		os.Remove(tmpFile) // Clean up temp file on error
+	if err := s.fs.Rename(tmpFile, filepath); err != nil {
+		s.fs.Remove(tmpFile) // Clean up temp file on error
 		return fmt.Errorf("failed to rename temp file: %w", err)
 	}
 
@@ -116,7 +362,7 @@ func (s *JSONStore) atomicWriteJSON(filepath string, data interface{}) error {
 
 // readJSON reads and unmarshals JSON from file
 func (s *JSONStore) readJSON(filepath string, v interface{}) error {
-	data, err := os.ReadFile(filepath)
+	data, err := fsys.ReadFile(s.fs, filepath)
 	if err != nil {
 		return err
 	}
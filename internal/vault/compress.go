@@ -0,0 +1,386 @@
+package vault
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/flate"
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"hash/crc32"
+	"io"
+	"os"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+
+	"github.com/adrielfilipedesign/gamekeep/internal/fsys"
+	"github.com/adrielfilipedesign/gamekeep/internal/models"
+)
+
+const (
+	// deflateLevel is the compression level archive/zip uses by default
+	deflateLevel = 5
+
+	// storeRatio is the share of its size a file must compress to, or it
+	// is stored as it is: barely shrinking it isn't worth the time to
+	// inflate it again on restore
+	storeRatio = 0.95
+
+	// sampleSize is how much of a large file is compressed up front to
+	// decide whether to store it instead
+	sampleSize = 1 << 20
+
+	// pendingPerWorker is how many compressed files per worker may wait to
+	// be written, bounding memory while one slow file holds up the rest
+	pendingPerWorker = 2
+)
+
+// largeFileSize is the size from which a file is compressed as it is
+// written instead of by a worker, so workers never hold big files in memory
+var largeFileSize int64 = 32 << 20
+
+// ArchiveOptions controls how checkpoint archives are created
+type ArchiveOptions struct {
+	// Workers is how many files are compressed at once; 0 means one per CPU
+	Workers int
+
+	// Compression is the codec and level to use; zero means the default
+	Compression models.Compression
+
+	// Progress receives reports as files are archived; may be nil
+	Progress models.ProgressFunc
+
+	// Contents, if not nil, has every archived entry added to it, giving
+	// the saves' content hash without reading them a second time
+	Contents *ContentHasher
+}
+
+// workers returns how many compression workers to run
+func (o *ArchiveOptions) workers() int {
+	if o.Workers > 0 {
+		return o.Workers
+	}
+	return runtime.NumCPU()
+}
+
+// compression returns the codec and level to use
+func (o *ArchiveOptions) compression() models.Compression {
+	if o.Compression.Codec == "" {
+		return models.DefaultCompression()
+	}
+	return o.Compression
+}
+
+// codecMethod returns the zip method that files compressed with a codec
+// are marked with
+func codecMethod(codec models.Codec) uint16 {
+	switch codec {
+	case models.CodecStore:
+		return zip.Store
+	case models.CodecZstd:
+		return zstd.ZipMethodWinZip
+	}
+	return zip.Deflate
+}
+
+// knownMethod reports whether archives can contain files compressed with
+// a zip method
+func knownMethod(method uint16) bool {
+	return method == zip.Store || method == zip.Deflate || method == zstd.ZipMethodWinZip
+}
+
+// compressor is a codec's writer, reusable across files with Reset
+type compressor interface {
+	io.WriteCloser
+	Reset(w io.Writer)
+}
+
+// newCompressor creates a writer for a codec and level, or returns nil for
+// CodecStore
+func newCompressor(c models.Compression) (compressor, error) {
+	switch c.Codec {
+	case models.CodecStore:
+		return nil, nil
+	case models.CodecZstd:
+		// Files are already compressed in parallel
+		opts := []zstd.EOption{zstd.WithEncoderConcurrency(1)}
+		if c.Level != 0 {
+			opts = append(opts, zstd.WithEncoderLevel(zstd.EncoderLevelFromZstd(c.Level)))
+		}
+		return zstd.NewWriter(nil, opts...)
+	}
+
+	level := c.Level
+	if level == 0 {
+		level = deflateLevel
+	}
+	return flate.NewWriter(nil, level)
+}
+
+// registerCompressor has archive compress files with the codec's method
+// as c says, for files written through it rather than compressed by a
+// worker
+func registerCompressor(archive *zip.Writer, c models.Compression) {
+	if c.Codec == models.CodecStore {
+		return
+	}
+	archive.RegisterCompressor(codecMethod(c.Codec), func(w io.Writer) (io.WriteCloser, error) {
+		compressor, err := newCompressor(c)
+		if err != nil {
+			return nil, err
+		}
+		compressor.Reset(w)
+		return compressor, nil
+	})
+}
+
+// registerDecompressors lets reader read every method archives can contain
+func registerDecompressors(reader *zip.Reader) {
+	reader.RegisterDecompressor(zstd.ZipMethodWinZip, zstd.ZipDecompressor())
+}
+
+// worthCompressing reports whether data compressed to compressed bytes is
+// small enough to keep compressed rather than stored
+func worthCompressing(compressed, size int) bool {
+	return float64(compressed) <= storeRatio*float64(size)
+}
+
+// sampleCompresses compresses a sample of a file to see whether the file
+// is worth compressing
+func sampleCompresses(c models.Compression, sample []byte) (bool, error) {
+	compressor, err := newCompressor(c)
+	if err != nil || compressor == nil {
+		return false, err
+	}
+
+	var buf bytes.Buffer
+	compressor.Reset(&buf)
+	if _, err := compressor.Write(sample); err != nil {
+		return false, err
+	}
+	if err := compressor.Close(); err != nil {
+		return false, err
+	}
+	return worthCompressing(buf.Len(), len(sample)), nil
+}
+
+// archiveEntry is a file or directory to add to an archive
+type archiveEntry struct {
+	path   string
+	header *zip.FileHeader
+	pooled bool   // Compressed by a worker
+	link   string // A symlink's target, which is stored rather than followed
+}
+
+// compressedFile is a file a worker compressed, ready to be written
+type compressedFile struct {
+	header *zip.FileHeader
+	data   []byte
+	digest []byte // sha256 of the uncompressed data, if the pool hashes
+	err    error
+}
+
+// compressionPool compresses the pooled entries of an archive on a bounded
+// number of goroutines. Results are collected in entry order, so archives
+// come out the same however many workers there are.
+type compressionPool struct {
+	fs          fsys.FS
+	ctx         context.Context
+	cancel      context.CancelFunc
+	compression models.Compression
+	hash        bool // Whether to give files a digest
+	entries     []archiveEntry
+	results     []chan compressedFile // One per entry, used by pooled entries
+	slots       chan struct{}         // Held from queueing a file until it is written
+	wg          sync.WaitGroup
+}
+
+// startCompressionPool starts compressing the pooled entries in order,
+// hashing their contents too if hash is set. Call stop once done with the
+// pool.
+func startCompressionPool(ctx context.Context, fs fsys.FS, entries []archiveEntry, workers int, compression models.Compression, hash bool) *compressionPool {
+	ctx, cancel := context.WithCancel(ctx)
+	p := &compressionPool{
+		fs:          fs,
+		ctx:         ctx,
+		cancel:      cancel,
+		compression: compression,
+		hash:        hash,
+		entries:     entries,
+		results:     make([]chan compressedFile, len(entries)),
+		slots:       make(chan struct{}, workers*pendingPerWorker),
+	}
+	for i := range p.results {
+		p.results[i] = make(chan compressedFile, 1)
+	}
+
+	jobs := make(chan int)
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		defer close(jobs)
+		for i, entry := range entries {
+			if !entry.pooled {
+				continue
+			}
+			select {
+			case p.slots <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			select {
+			case jobs <- i:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	for w := 0; w < workers; w++ {
+		p.wg.Add(1)
+		go func() {
+			defer p.wg.Done()
+			// The codec was checked before the pool started
+			compressor, _ := newCompressor(compression)
+			for i := range jobs {
+				p.results[i] <- p.compress(compressor, p.entries[i])
+			}
+		}()
+	}
+
+	return p
+}
+
+// compress compresses one file into memory with compressor, or stores it
+// if compressor is nil or the file barely compresses
+func (p *compressionPool) compress(compressor compressor, entry archiveEntry) compressedFile {
+	file, err := p.fs.Open(entry.path)
+	if err != nil {
+		return compressedFile{err: err}
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(&contextReader{ctx: p.ctx, r: file})
+	if err != nil {
+		return compressedFile{err: err}
+	}
+
+	// Sizes are what was read, in case the file changed since it was listed
+	header := *entry.header
+	header.Extra = append(header.Extra[:len(header.Extra):len(header.Extra)], extendedTimestamp(header.Modified)...)
+	header.CRC32 = crc32.ChecksumIEEE(data)
+	header.UncompressedSize64 = uint64(len(data))
+	var digest []byte
+	if p.hash {
+		sum := sha256.Sum256(data)
+		digest = sum[:]
+	}
+
+	if compressor != nil {
+		var buf bytes.Buffer
+		compressor.Reset(&buf)
+		if _, err := compressor.Write(data); err != nil {
+			return compressedFile{err: err}
+		}
+		if err := compressor.Close(); err != nil {
+			return compressedFile{err: err}
+		}
+		if worthCompressing(buf.Len(), len(data)) {
+			header.CompressedSize64 = uint64(buf.Len())
+			return compressedFile{header: &header, data: buf.Bytes(), digest: digest}
+		}
+	}
+
+	header.Method = zip.Store
+	header.CompressedSize64 = uint64(len(data))
+	return compressedFile{header: &header, data: data, digest: digest}
+}
+
+// result waits for a pooled entry's compressed file. Call done once it is
+// written to let another file be queued.
+func (p *compressionPool) result(i int) (compressedFile, error) {
+	select {
+	case result := <-p.results[i]:
+		return result, result.err
+	case <-p.ctx.Done():
+		return compressedFile{}, p.ctx.Err()
+	}
+}
+
+// done frees the slot of a file that was written
+func (p *compressionPool) done() {
+	<-p.slots
+}
+
+// stop cancels any remaining work and waits for the workers to finish
+func (p *compressionPool) stop() {
+	p.cancel()
+	p.wg.Wait()
+}
+
+// extendedTimestamp returns the extra field zip.Writer.CreateHeader adds to
+// keep a modification time to the second, which CreateRaw leaves out;
+// without it only the two-second MS-DOS time is kept
+func extendedTimestamp(modified time.Time) []byte {
+	extra := make([]byte, 9)
+	binary.LittleEndian.PutUint16(extra[0:], 0x5455) // Extended timestamp ID
+	binary.LittleEndian.PutUint16(extra[2:], 5)      // Size of what follows
+	extra[4] = 1                                     // Flags: modification time
+	binary.LittleEndian.PutUint32(extra[5:], uint32(modified.Unix()))
+	return extra
+}
+
+// listArchiveEntries walks a directory and returns what to archive in walk
+// order, with files marked with method, and the total number of files and
+// bytes
+func (m *Manager) listArchiveEntries(sourceDir string, method uint16) (entries []archiveEntry, files int, bytes int64, err error) {
+	err = fsys.Walk(m.fs, sourceDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		// Skip the root directory itself
+		if path == sourceDir {
+			return nil
+		}
+
+		header, err := archiveHeader(sourceDir, path, info)
+		if err != nil {
+			return err
+		}
+
+		entry := archiveEntry{path: path, header: header}
+		if info.Mode()&os.ModeSymlink != 0 {
+			if entry.link, err = m.fs.Readlink(path); err != nil {
+				return err
+			}
+			files++
+			bytes += int64(len(entry.link))
+		} else if !info.IsDir() {
+			header.Method = method
+			files++
+			bytes += info.Size()
+			entry.pooled = info.Size() < largeFileSize
+		}
+		entries = append(entries, entry)
+		return nil
+	})
+	return entries, files, bytes, err
+}
+
+// contextReader stops reading with its context's error once the context
+// is done
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (r *contextReader) Read(p []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return r.r.Read(p)
+}
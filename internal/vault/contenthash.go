@@ -0,0 +1,44 @@
+package vault
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+)
+
+// ContentHasher hashes the names and contents of a save directory's
+// entries, and the targets of its symlinks. Unlike an archive's hash it
+// doesn't depend on modification times, so saves written back unchanged
+// still match. Entries must be added in walk order, without the directory
+// itself.
+type ContentHasher struct {
+	h hash.Hash
+}
+
+// NewContentHasher returns an empty content hasher
+func NewContentHasher() *ContentHasher {
+	return &ContentHasher{h: sha256.New()}
+}
+
+// Dir adds a directory, named by its slash-separated relative path
+func (c *ContentHasher) Dir(rel string) {
+	fmt.Fprintf(c.h, "d\x00%s\n", rel)
+}
+
+// Link adds a symlink; checkpoints keep links as links, so only where one
+// points matters
+func (c *ContentHasher) Link(rel, target string) {
+	fmt.Fprintf(c.h, "l\x00%s\x00%s\n", rel, target)
+}
+
+// File adds a file of size bytes whose contents hash to digest with
+// sha256
+func (c *ContentHasher) File(rel string, size int64, digest []byte) {
+	fmt.Fprintf(c.h, "f\x00%s\x00%d\x00%x\n", rel, size, digest)
+}
+
+// Sum returns the hash of the entries added so far
+func (c *ContentHasher) Sum() string {
+	return hex.EncodeToString(c.h.Sum(nil))
+}
@@ -0,0 +1,207 @@
+package vault
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/binary"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+
+	"github.com/adrielfilipedesign/gamekeep/internal/models"
+)
+
+// ratioFloor is the size from which an entry is held to the ratio limit.
+// Smaller ones can't do harm however well they compress.
+const ratioFloor = 1 << 20
+
+// zstdRatioFactor is how many times MaxRatio a zstd entry may compress.
+// The limit is set for deflate, which zstd far outdoes on repetitive saves.
+const zstdRatioFactor = 16
+
+// RestoreOptions controls how checkpoint archives are restored
+type RestoreOptions struct {
+	// Limits bounds what the archive may unpack to; nil uses the defaults
+	Limits *models.ArchiveLimits
+
+	// Progress receives reports as files are extracted; may be nil
+	Progress models.ProgressFunc
+
+	// SkippedLink is told about each symlink left out because it points
+	// outside the restored directory; may be nil
+	SkippedLink func(name, target string)
+}
+
+// limits returns the limits to hold the archive to
+func (o *RestoreOptions) limits() models.ArchiveLimits {
+	if o.Limits == nil {
+		return models.DefaultArchiveLimits()
+	}
+	return *o.Limits
+}
+
+// OpenArchive opens a zip archive held to limits. The entry count is
+// checked before the archive's directory is read, and what the directory
+// declares before anything is extracted; an ArchiveLimitError is returned
+// if the archive goes over.
+func OpenArchive(r io.ReaderAt, size int64, limits models.ArchiveLimits) (*zip.Reader, error) {
+	if entries, ok := declaredEntries(r, size); ok && limits.MaxEntries > 0 && entries > uint64(limits.MaxEntries) {
+		return nil, &models.ArchiveLimitError{Limit: models.LimitEntries, Value: int64(entries), Max: int64(limits.MaxEntries)}
+	}
+
+	reader, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, err
+	}
+	registerDecompressors(reader)
+
+	if limits.MaxEntries > 0 && len(reader.File) > limits.MaxEntries {
+		return nil, &models.ArchiveLimitError{Limit: models.LimitEntries, Value: int64(len(reader.File)), Max: int64(limits.MaxEntries)}
+	}
+	budget := &extractionBudget{limits: limits}
+	for _, file := range reader.File {
+		if err := budget.checkDepth(file.Name); err != nil {
+			return nil, err
+		}
+		if err := budget.count(file, file.UncompressedSize64, file.UncompressedSize64); err != nil {
+			return nil, err
+		}
+	}
+	return reader, nil
+}
+
+// CheckCheckpoint checks a checkpoint archive against limits without
+// extracting it, as OpenArchive does
+func (m *Manager) CheckCheckpoint(vaultFile string, limits models.ArchiveLimits) error {
+	file, err := m.fs.Open(filepath.Join(m.vaultDir, vaultFile))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+	_, err = OpenArchive(file, info.Size(), limits)
+	return err
+}
+
+// extractionBudget counts what an archive unpacks to against its limits.
+// Extraction counts the bytes entries really unpack to, since an archive's
+// directory can lie about them.
+type extractionBudget struct {
+	limits models.ArchiveLimits
+	total  uint64
+}
+
+// checkDepth checks how deeply an entry's path is nested
+func (b *extractionBudget) checkDepth(name string) error {
+	depth := strings.Count(strings.Trim(name, "/"), "/")
+	if b.limits.MaxDepth > 0 && depth > b.limits.MaxDepth {
+		return &models.ArchiveLimitError{Limit: models.LimitDepth, Entry: name, Value: int64(depth), Max: int64(b.limits.MaxDepth)}
+	}
+	return nil
+}
+
+// count adds n bytes of file to the total, with entry bytes of the file
+// unpacked so far, and checks both against the limits
+func (b *extractionBudget) count(file *zip.File, n, entry uint64) error {
+	if b.limits.MaxSizeMB > 0 {
+		max := uint64(b.limits.MaxSizeMB) << 20
+		if n > max || b.total+n > max {
+			return &models.ArchiveLimitError{Limit: models.LimitSize, Value: int64(b.total), Max: int64(max)}
+		}
+	}
+	b.total += n
+
+	if b.limits.MaxRatio > 0 && entry >= ratioFloor {
+		compressed := file.CompressedSize64
+		if compressed == 0 {
+			compressed = 1
+		}
+		max := uint64(b.limits.MaxRatio)
+		if file.Method == zstd.ZipMethodWinZip {
+			max *= zstdRatioFactor
+		}
+		if entry/compressed >= max {
+			return &models.ArchiveLimitError{Limit: models.LimitRatio, Entry: file.Name, Value: int64(entry / compressed), Max: int64(max)}
+		}
+	}
+	return nil
+}
+
+// reader counts what r unpacks file to, failing once it goes over a limit
+func (b *extractionBudget) reader(file *zip.File, r io.Reader) io.Reader {
+	return &budgetReader{budget: b, file: file, r: r}
+}
+
+// budgetReader counts the bytes of one entry for an extractionBudget
+type budgetReader struct {
+	budget *extractionBudget
+	file   *zip.File
+	r      io.Reader
+	read   uint64
+}
+
+func (r *budgetReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.read += uint64(n)
+	if cerr := r.budget.count(r.file, uint64(n), r.read); cerr != nil {
+		return n, cerr
+	}
+	return n, err
+}
+
+// Zip end of central directory records, which hold the entry count
+const (
+	endRecordLen      = 22
+	maxCommentLen     = 0xFFFF
+	zip64LocatorLen   = 20
+	zip64EndRecordLen = 56
+)
+
+var (
+	endRecordSignature    = []byte("PK\x05\x06")
+	zip64LocatorSignature = []byte("PK\x06\x07")
+	zip64EndSignature     = []byte("PK\x06\x06")
+)
+
+// declaredEntries reads how many entries an archive's end record says its
+// directory holds, without reading the directory. It reports false if the
+// record can't be found, leaving zip.NewReader to make sense of the archive.
+func declaredEntries(r io.ReaderAt, size int64) (uint64, bool) {
+	// The record ends the archive, followed only by a comment
+	tailLen := int64(endRecordLen + maxCommentLen)
+	if tailLen > size {
+		tailLen = size
+	}
+	tail := make([]byte, tailLen)
+	if _, err := r.ReadAt(tail, size-tailLen); err != nil && err != io.EOF {
+		return 0, false
+	}
+	i := bytes.LastIndex(tail, endRecordSignature)
+	if i < 0 || len(tail)-i < endRecordLen {
+		return 0, false
+	}
+	if entries := binary.LittleEndian.Uint16(tail[i+10:]); entries != 0xFFFF {
+		return uint64(entries), true
+	}
+
+	// Zip64 archives keep the real count in a record the locator points to
+	locatorAt := size - tailLen + int64(i) - zip64LocatorLen
+	if locatorAt < 0 {
+		return 0, false
+	}
+	locator := make([]byte, zip64LocatorLen)
+	if _, err := r.ReadAt(locator, locatorAt); err != nil || !bytes.HasPrefix(locator, zip64LocatorSignature) {
+		return 0, false
+	}
+	record := make([]byte, zip64EndRecordLen)
+	if _, err := r.ReadAt(record, int64(binary.LittleEndian.Uint64(locator[8:]))); err != nil || !bytes.HasPrefix(record, zip64EndSignature) {
+		return 0, false
+	}
+	return binary.LittleEndian.Uint64(record[32:]), true
+}
@@ -2,37 +2,72 @@ package vault
 
 import (
 	"archive/zip"
+	"bytes"
+	"context"
 	"crypto/sha256"
 	"encoding/hex"
 	"fmt"
 	"io"
 	"os"
+	"path"
 	"path/filepath"
 	"strings"
+	"sync"
+
+	"github.com/adrielfilipedesign/gamekeep/internal/fsys"
+	"github.com/adrielfilipedesign/gamekeep/internal/models"
+)
+
+const (
+	// trashDirName is the vault subdirectory holding deleted checkpoints
+	trashDirName = ".trash"
+
+	// coversDirName is the vault subdirectory holding game cover images
+	coversDirName = ".covers"
 )
 
 // Manager handles vault operations for save files
 type Manager struct {
+	fs       fsys.FS
 	vaultDir string
+	verifyMu sync.Mutex // Serializes verify cache updates
 }
 
-// NewManager creates a new vault manager
+// NewManager creates a new vault manager on the OS filesystem
 func NewManager(vaultDir string) (*Manager, error) {
+	return NewManagerWithFS(fsys.OS{}, vaultDir)
+}
+
+// NewManagerWithFS creates a new vault manager on the given filesystem
+func NewManagerWithFS(fs fsys.FS, vaultDir string) (*Manager, error) {
 	// Ensure vault directory exists
-	if err := os.MkdirAll(vaultDir, 0755); err != nil {
+	if err := fs.MkdirAll(vaultDir, 0755); err != nil {
 		return nil, fmt.Errorf("failed to create vault directory: %w", err)
 	}
 
 	return &Manager{
+		fs:       fs,
 		vaultDir: vaultDir,
 	}, nil
 }
 
+// FS returns the filesystem the vault operates on
+func (m *Manager) FS() fsys.FS {
+	return m.fs
+}
+
 // CreateCheckpoint creates a compressed archive of the save directory
 func (m *Manager) CreateCheckpoint(gameID, checkpointID, savePath string) (vaultFile string, hash string, err error) {
+	return m.CreateCheckpointContext(context.Background(), gameID, checkpointID, savePath, ArchiveOptions{})
+}
+
+// CreateCheckpointContext creates a compressed archive of the save
+// directory as opts says. If ctx is done first, the partial archive is
+// removed and ctx's error returned.
+func (m *Manager) CreateCheckpointContext(ctx context.Context, gameID, checkpointID, savePath string, opts ArchiveOptions) (vaultFile string, hash string, err error) {
 	// Create game-specific directory
 	gameVaultDir := filepath.Join(m.vaultDir, gameID)
-	if err := os.MkdirAll(gameVaultDir, 0755); err != nil {
+	if err := m.fs.MkdirAll(gameVaultDir, 0755); err != nil {
 		return "", "", fmt.Errorf("failed to create game vault directory: %w", err)
 	}
 
@@ -40,64 +75,119 @@ func (m *Manager) CreateCheckpoint(gameID, checkpointID, savePath string) (vault
 	zipPath := filepath.Join(gameVaultDir, checkpointID+".zip")
 
 	// Verify source path exists
-	if _, err := os.Stat(savePath); err != nil {
+	if _, err := m.fs.Stat(savePath); err != nil {
 		return "", "", fmt.Errorf("save path does not exist: %w", err)
 	}
 
-	// Create zip archive
-	if err := m.zipDirectory(savePath, zipPath); err != nil {
-		return "", "", fmt.Errorf("failed to create zip archive: %w", err)
-	}
-
-	// Calculate SHA256 hash
-	hash, err = m.calculateHash(zipPath)
+	// Create zip archive, hashing it on the way out
+	hash, err = m.zipDirectory(ctx, savePath, zipPath, opts)
 	if err != nil {
-		os.Remove(zipPath) // Clean up on error
-		return "", "", fmt.Errorf("failed to calculate hash: %w", err)
+		m.fs.Remove(zipPath) // Don't leave a partial archive behind
+		return "", "", fmt.Errorf("failed to create zip archive: %w", err)
 	}
 
 	// Return relative path from vault root
 	relPath, err := filepath.Rel(m.vaultDir, zipPath)
 	if err != nil {
+		m.fs.Remove(zipPath)
 		return "", "", fmt.Errorf("failed to get relative path: %w", err)
 	}
 
+	m.rememberHash(relPath, hash)
 	return relPath, hash, nil
 }
 
-// RestoreCheckpoint extracts a checkpoint archive to the save directory
+// RestoreCheckpoint extracts a checkpoint archive to the save directory.
+// The archive is extracted into a staging directory first, so a failed
+// extraction leaves the existing saves untouched.
 func (m *Manager) RestoreCheckpoint(vaultFile, targetPath string) error {
+	return m.RestoreCheckpointContext(context.Background(), vaultFile, targetPath, RestoreOptions{})
+}
+
+// RestoreCheckpointContext extracts a checkpoint archive to the save
+// directory like RestoreCheckpoint, holding the archive to opts' limits and
+// reporting progress to opts.Progress. If ctx is done or a limit is
+// exceeded during extraction, the existing saves are left untouched and
+// the error returned.
+func (m *Manager) RestoreCheckpointContext(ctx context.Context, vaultFile, targetPath string, opts RestoreOptions) error {
 	// Full path to zip file
 	zipPath := filepath.Join(m.vaultDir, vaultFile)
 
 	// Verify zip exists
-	if _, err := os.Stat(zipPath); err != nil {
+	if _, err := m.fs.Stat(zipPath); err != nil {
 		return fmt.Errorf("checkpoint file not found: %w", err)
 	}
 
-	// Remove existing save directory
-	if err := os.RemoveAll(targetPath); err != nil {
-		return fmt.Errorf("failed to remove existing save directory: %w", err)
-	}
+	targetPath = filepath.Clean(targetPath)
+	stagingPath := targetPath + ".gamekeep-restore"
+	backupPath := targetPath + ".gamekeep-old"
 
-	// Create target directory
-	if err := os.MkdirAll(targetPath, 0755); err != nil {
-		return fmt.Errorf("failed to create target directory: %w", err)
+	// Start from an empty staging directory
+	if err := m.fs.RemoveAll(stagingPath); err != nil {
+		return fmt.Errorf("failed to clear staging directory: %w", err)
+	}
+	if err := m.fs.MkdirAll(stagingPath, 0755); err != nil {
+		return fmt.Errorf("failed to create staging directory: %w", err)
 	}
 
 	// Extract zip
-	if err := m.unzipArchive(zipPath, targetPath); err != nil {
+	if err := m.unzipArchive(ctx, zipPath, stagingPath, opts); err != nil {
+		m.fs.RemoveAll(stagingPath)
 		return fmt.Errorf("failed to extract checkpoint: %w", err)
 	}
 
+	// Move current saves out of the way
+	hadExisting := false
+	if _, err := m.fs.Lstat(targetPath); err == nil {
+		if err := m.fs.RemoveAll(backupPath); err != nil {
+			m.fs.RemoveAll(stagingPath)
+			return fmt.Errorf("failed to clear backup directory: %w", err)
+		}
+		if err := m.fs.Rename(targetPath, backupPath); err != nil {
+			m.fs.RemoveAll(stagingPath)
+			return fmt.Errorf("failed to move existing save directory: %w", err)
+		}
+		hadExisting = true
+	} else if err := m.fs.MkdirAll(filepath.Dir(targetPath), 0755); err != nil {
+		m.fs.RemoveAll(stagingPath)
+		return fmt.Errorf("failed to create target directory: %w", err)
+	}
+
+	// Swap in the restored saves
+	if err := m.fs.Rename(stagingPath, targetPath); err != nil {
+		if hadExisting {
+			m.fs.Rename(backupPath, targetPath) // Roll back
+		}
+		m.fs.RemoveAll(stagingPath)
+		return fmt.Errorf("failed to move restored saves into place: %w", err)
+	}
+
+	if hadExisting {
+		m.fs.RemoveAll(backupPath)
+	}
+
 	return nil
 }
 
 // VerifyCheckpoint verifies the integrity of a checkpoint
 func (m *Manager) VerifyCheckpoint(vaultFile, expectedHash string) error {
+	return m.VerifyCheckpointContext(context.Background(), vaultFile, expectedHash, false)
+}
+
+// VerifyCheckpointContext verifies the integrity of a checkpoint, stopping
+// with ctx's error if ctx is done first. With useCache, an archive whose
+// size and modification time haven't changed since it was last hashed
+// isn't read again.
+func (m *Manager) VerifyCheckpointContext(ctx context.Context, vaultFile, expectedHash string, useCache bool) error {
+	if useCache {
+		if hash, ok := m.cachedHash(vaultFile); ok && hash == expectedHash {
+			return nil
+		}
+	}
+
 	zipPath := filepath.Join(m.vaultDir, vaultFile)
 
-	actualHash, err := m.calculateHash(zipPath)
+	actualHash, err := m.calculateHash(ctx, zipPath)
 	if err != nil {
 		return fmt.Errorf("failed to calculate hash: %w", err)
 	}
@@ -106,93 +196,381 @@ func (m *Manager) VerifyCheckpoint(vaultFile, expectedHash string) error {
 		return fmt.Errorf("hash mismatch: expected %s, got %s", expectedHash, actualHash)
 	}
 
+	m.rememberHash(vaultFile, actualHash)
 	return nil
 }
 
 // DeleteCheckpoint removes a checkpoint file from the vault
 func (m *Manager) DeleteCheckpoint(vaultFile string) error {
 	zipPath := filepath.Join(m.vaultDir, vaultFile)
-	return os.Remove(zipPath)
+	if err := m.fs.Remove(zipPath); err != nil {
+		return err
+	}
+	m.forgetHash(vaultFile)
+	return nil
 }
 
-// zipDirectory compresses a directory into a zip file
-func (m *Manager) zipDirectory(sourceDir, targetZip string) error {
-	// Create zip file
-	zipFile, err := os.Create(targetZip)
+// CheckpointSize returns the size in bytes of a checkpoint archive
+func (m *Manager) CheckpointSize(vaultFile string) (int64, error) {
+	info, err := m.fs.Stat(filepath.Join(m.vaultDir, vaultFile))
 	if err != nil {
-		return err
+		return 0, err
 	}
-	defer zipFile.Close()
+	return info.Size(), nil
+}
 
-	archive := zip.NewWriter(zipFile)
-	defer archive.Close()
+// OpenCheckpoint opens a checkpoint archive for reading
+func (m *Manager) OpenCheckpoint(vaultFile string) (fsys.File, error) {
+	return m.fs.Open(filepath.Join(m.vaultDir, vaultFile))
+}
 
-	// Walk through directory
-	return filepath.Walk(sourceDir, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return err
+// ImportCheckpoint stores an existing checkpoint archive read from r in the
+// vault and returns its vault path and SHA256 hash
+func (m *Manager) ImportCheckpoint(gameID, checkpointID string, r io.Reader) (vaultFile string, hash string, err error) {
+	gameVaultDir := filepath.Join(m.vaultDir, gameID)
+	if err := m.fs.MkdirAll(gameVaultDir, 0755); err != nil {
+		return "", "", fmt.Errorf("failed to create game vault directory: %w", err)
+	}
+
+	zipPath := filepath.Join(gameVaultDir, checkpointID+".zip")
+	file, err := m.fs.Create(zipPath)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to create checkpoint file: %w", err)
+	}
+
+	hasher := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(file, hasher), r); err != nil {
+		file.Close()
+		m.fs.Remove(zipPath)
+		return "", "", fmt.Errorf("failed to write checkpoint file: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		m.fs.Remove(zipPath)
+		return "", "", fmt.Errorf("failed to write checkpoint file: %w", err)
+	}
+
+	vaultFile = filepath.Join(gameID, checkpointID+".zip")
+	hash = hex.EncodeToString(hasher.Sum(nil))
+	m.rememberHash(vaultFile, hash)
+	return vaultFile, hash, nil
+}
+
+// RemoveGameDir removes a game's vault directory and anything left in it
+func (m *Manager) RemoveGameDir(gameID string) error {
+	if gameID == "" || gameID == trashDirName || gameID == coversDirName {
+		return fmt.Errorf("refusing to remove vault directory for game ID %q", gameID)
+	}
+	return m.fs.RemoveAll(filepath.Join(m.vaultDir, gameID))
+}
+
+// SaveCover stores a game's cover image read from r, replacing any previous
+// cover with the same extension, and returns its path relative to the vault root
+func (m *Manager) SaveCover(gameID, ext string, r io.Reader) (coverFile string, err error) {
+	coversDir := filepath.Join(m.vaultDir, coversDirName)
+	if err := m.fs.MkdirAll(coversDir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create covers directory: %w", err)
+	}
+
+	// Write to a temp file so a failed copy never replaces a good cover
+	coverFile = filepath.Join(coversDirName, gameID+ext)
+	target := filepath.Join(m.vaultDir, coverFile)
+	tmpPath := target + ".tmp"
+
+	file, err := m.fs.Create(tmpPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to create cover file: %w", err)
+	}
+	if _, err := io.Copy(file, r); err != nil {
+		file.Close()
+		m.fs.Remove(tmpPath)
+		return "", fmt.Errorf("failed to write cover file: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		m.fs.Remove(tmpPath)
+		return "", fmt.Errorf("failed to write cover file: %w", err)
+	}
+
+	if err := m.fs.Rename(tmpPath, target); err != nil {
+		m.fs.Remove(tmpPath)
+		return "", fmt.Errorf("failed to write cover file: %w", err)
+	}
+
+	return coverFile, nil
+}
+
+// CoverPath returns the full path of a stored cover image
+func (m *Manager) CoverPath(coverFile string) string {
+	return filepath.Join(m.vaultDir, coverFile)
+}
+
+// OpenCover opens a stored cover image for reading
+func (m *Manager) OpenCover(coverFile string) (fsys.File, error) {
+	return m.fs.Open(filepath.Join(m.vaultDir, coverFile))
+}
+
+// DeleteCover removes a stored cover image
+func (m *Manager) DeleteCover(coverFile string) error {
+	return m.fs.Remove(filepath.Join(m.vaultDir, coverFile))
+}
+
+// TrashCheckpoint moves a checkpoint file into the vault's trash area and
+// returns its new path relative to the vault root
+func (m *Manager) TrashCheckpoint(vaultFile string) (string, error) {
+	trashDir := filepath.Join(m.vaultDir, trashDirName)
+	if err := m.fs.MkdirAll(trashDir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create trash directory: %w", err)
+	}
+
+	trashFile := filepath.Join(trashDirName, filepath.Base(vaultFile))
+	if err := m.fs.Rename(filepath.Join(m.vaultDir, vaultFile), filepath.Join(m.vaultDir, trashFile)); err != nil {
+		return "", fmt.Errorf("failed to move checkpoint to trash: %w", err)
+	}
+
+	// Nothing is left under the vault name for a cached result to vouch for
+	m.forgetHash(vaultFile)
+	return trashFile, nil
+}
+
+// UntrashCheckpoint moves a trashed checkpoint file back to its vault location
+func (m *Manager) UntrashCheckpoint(trashFile, vaultFile string) error {
+	target := filepath.Join(m.vaultDir, vaultFile)
+	if err := m.fs.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return fmt.Errorf("failed to create game vault directory: %w", err)
+	}
+
+	if err := m.fs.Rename(filepath.Join(m.vaultDir, trashFile), target); err != nil {
+		return fmt.Errorf("failed to restore checkpoint from trash: %w", err)
+	}
+
+	// Whatever was cached under the vault name was for another file; the
+	// restored one is read in full when next verified
+	m.forgetHash(vaultFile)
+	return nil
+}
+
+// zipDirectory compresses a directory into a zip file and returns the
+// file's SHA256 hash. Files are compressed in parallel but written in walk
+// order, so the archive doesn't depend on the number of workers.
+func (m *Manager) zipDirectory(ctx context.Context, sourceDir, targetZip string, opts ArchiveOptions) (hash string, err error) {
+	compression := opts.compression()
+	if err := compression.Validate(); err != nil {
+		return "", err
+	}
+	if _, err := newCompressor(compression); err != nil {
+		return "", err
+	}
+
+	// Size up the work first so progress has totals
+	entries, files, bytes, err := m.listArchiveEntries(sourceDir, codecMethod(compression.Codec))
+	if err != nil {
+		return "", err
+	}
+	tracker := newProgressTracker(ctx, opts.Progress, files, bytes)
+
+	// Create zip file
+	zipFile, err := m.fs.Create(targetZip)
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		// Surface close errors: a failed flush means a truncated archive
+		if cerr := zipFile.Close(); err == nil {
+			err = cerr
 		}
+	}()
 
-		// Skip the root directory itself
-		if path == sourceDir {
-			return nil
+	// Hash the archive as it is written rather than reading it back
+	hasher := sha256.New()
+	archive := zip.NewWriter(io.MultiWriter(zipFile, hasher))
+	registerCompressor(archive, compression)
+
+	pool := startCompressionPool(ctx, m.fs, entries, opts.workers(), compression, opts.Contents != nil)
+	defer pool.stop()
+
+	for i, entry := range entries {
+		if err := tracker.startFile(entry.header.Name); err != nil {
+			archive.Close()
+			return "", err
 		}
+		if err := m.writeArchiveEntry(archive, pool, i, tracker, opts.Contents); err != nil {
+			archive.Close()
+			return "", err
+		}
+	}
 
-		// Get relative path
-		relPath, err := filepath.Rel(sourceDir, path)
+	// Closing writes the central directory, the last of the hashed bytes
+	if err := archive.Close(); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hasher.Sum(nil)), nil
+}
+
+// writeArchiveEntry writes the i'th entry to an archive: a directory, a
+// file the pool compressed, or a large file compressed as it is copied.
+// The entry is added to contents if it is not nil.
+func (m *Manager) writeArchiveEntry(archive *zip.Writer, pool *compressionPool, i int, tracker *progressTracker, contents *ContentHasher) error {
+	entry := pool.entries[i]
+	rel := strings.TrimSuffix(entry.header.Name, "/")
+
+	if entry.pooled {
+		compressed, err := pool.result(i)
 		if err != nil {
 			return err
 		}
+		defer pool.done()
 
-		// Create zip header
-		header, err := zip.FileInfoHeader(info)
+		writer, err := archive.CreateRaw(compressed.header)
 		if err != nil {
 			return err
 		}
-
-		// Use forward slashes for cross-platform compatibility
-		header.Name = filepath.ToSlash(relPath)
-
-		// Handle directories
-		if info.IsDir() {
-			header.Name += "/"
-		} else {
-			header.Method = zip.Deflate
+		if _, err := writer.Write(compressed.data); err != nil {
+			return err
 		}
+		if contents != nil {
+			contents.File(rel, int64(compressed.header.UncompressedSize64), compressed.digest)
+		}
+		tracker.add(int(compressed.header.UncompressedSize64))
+		tracker.finishFile()
+		return nil
+	}
 
-		// Create entry
-		writer, err := archive.CreateHeader(header)
+	if entry.header.Mode().IsDir() {
+		if contents != nil {
+			contents.Dir(rel)
+		}
+		_, err := archive.CreateHeader(entry.header)
+		return err
+	}
+	if entry.link != "" {
+		writer, err := archive.CreateHeader(entry.header)
 		if err != nil {
 			return err
 		}
+		if _, err := io.WriteString(writer, entry.link); err != nil {
+			return err
+		}
+		if contents != nil {
+			contents.Link(rel, entry.link)
+		}
+		tracker.add(len(entry.link))
+		tracker.finishFile()
+		return nil
+	}
 
-		// Write file content
-		if !info.IsDir() {
-			file, err := os.Open(path)
-			if err != nil {
-				return err
-			}
-			defer file.Close()
+	file, err := m.fs.Open(entry.path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
 
-			_, err = io.Copy(writer, file)
-			if err != nil {
-				return err
-			}
+	// Judge whether the file is worth compressing by its start
+	header := *entry.header
+	sample := make([]byte, sampleSize)
+	n, err := io.ReadFull(file, sample)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return err
+	}
+	sample = sample[:n]
+	if header.Method != zip.Store {
+		compresses, err := sampleCompresses(pool.compression, sample)
+		if err != nil {
+			return err
 		}
+		if !compresses {
+			header.Method = zip.Store
+		}
+	}
 
-		return nil
-	})
+	writer, err := archive.CreateHeader(&header)
+	if err != nil {
+		return err
+	}
+	src := io.MultiReader(bytes.NewReader(sample), file)
+	hasher := sha256.New()
+	if contents != nil {
+		src = io.TeeReader(src, hasher)
+	}
+	size, err := tracker.copy(writer, src)
+	if err != nil {
+		return err
+	}
+	if contents != nil {
+		contents.File(rel, size, hasher.Sum(nil))
+	}
+	tracker.finishFile()
+	return nil
+}
+
+// archiveHeader returns the zip header for a file or directory under
+// sourceDir
+func archiveHeader(sourceDir, path string, info os.FileInfo) (*zip.FileHeader, error) {
+	// Get relative path
+	relPath, err := filepath.Rel(sourceDir, path)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create zip header
+	header, err := zip.FileInfoHeader(info)
+	if err != nil {
+		return nil, err
+	}
+
+	// Use forward slashes for cross-platform compatibility
+	header.Name = filepath.ToSlash(relPath)
+
+	// Handle directories
+	if info.IsDir() {
+		header.Name += "/"
+	}
+
+	return header, nil
 }
 
 // unzipArchive extracts a zip file to a target directory
-func (m *Manager) unzipArchive(zipPath, targetDir string) error {
-	reader, err := zip.OpenReader(zipPath)
+func (m *Manager) unzipArchive(ctx context.Context, zipPath, targetDir string, opts RestoreOptions) error {
+	zipFile, err := m.fs.Open(zipPath)
 	if err != nil {
 		return err
 	}
-	defer reader.Close()
+	defer zipFile.Close()
+
+	info, err := zipFile.Stat()
+	if err != nil {
+		return err
+	}
+
+	limits := opts.limits()
+	reader, err := OpenArchive(zipFile, info.Size(), limits)
+	if err != nil {
+		return err
+	}
+	budget := &extractionBudget{limits: limits}
+
+	files, bytes := 0, int64(0)
+	for _, file := range reader.File {
+		// Refuse before extracting anything rather than part way through
+		if !knownMethod(file.Method) {
+			return fmt.Errorf("%w: %s uses zip method %d", models.ErrUnsupportedCodec, file.Name, file.Method)
+		}
+		if !file.FileInfo().IsDir() {
+			files++
+			bytes += int64(file.UncompressedSize64)
+		}
+	}
+	tracker := newProgressTracker(ctx, opts.Progress, files, bytes)
+
+	// Symlinks are made once everything else is extracted, so nothing in
+	// the archive can be written through one, wherever it points.
+	// Directories get their modes and times last, once nothing more is
+	// written into them.
+	var links, dirs []extractedEntry
 
 	for _, file := range reader.File {
+		if err := tracker.startFile(file.Name); err != nil {
+			return err
+		}
+
 		// Prevent zip slip vulnerability
 		filePath := filepath.Join(targetDir, file.Name)
 		if !strings.HasPrefix(filePath, filepath.Clean(targetDir)+string(os.PathSeparator)) {
@@ -200,17 +578,58 @@ func (m *Manager) unzipArchive(zipPath, targetDir string) error {
 		}
 
 		if file.FileInfo().IsDir() {
-			os.MkdirAll(filePath, file.Mode())
+			if err := m.fs.MkdirAll(filePath, 0755); err != nil {
+				return err
+			}
+			dirs = append(dirs, extractedEntry{path: filePath, file: file})
 			continue
 		}
 
 		// Create parent directories
-		if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+		if err := m.fs.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
 			return err
 		}
 
+		if file.Mode()&os.ModeSymlink != 0 {
+			target, err := readLinkTarget(file)
+			if err != nil {
+				return err
+			}
+			links = append(links, extractedEntry{path: filePath, file: file, target: target})
+			tracker.add(len(target))
+			tracker.finishFile()
+			continue
+		}
+
 		// Extract file
-		if err := m.extractFile(file, filePath); err != nil {
+		if err := m.extractFile(tracker, budget, file, filePath); err != nil {
+			return err
+		}
+		tracker.finishFile()
+	}
+
+	// Links are kept only if they stay inside the restored directory, so
+	// the saves can't reach elsewhere once restored
+	targets := make(map[string]string, len(links))
+	for _, link := range links {
+		targets[path.Clean(link.file.Name)] = link.target
+	}
+	for _, link := range links {
+		if _, ok := resolveLink(targets, path.Dir(path.Clean(link.file.Name)), link.target, 0); !ok {
+			if opts.SkippedLink != nil {
+				opts.SkippedLink(link.file.Name, link.target)
+			}
+			continue
+		}
+		if err := m.fs.Symlink(link.target, link.path); err != nil {
+			return err
+		}
+	}
+
+	// Deepest first, so finishing a directory doesn't touch its parent's
+	// modification time
+	for i := len(dirs) - 1; i >= 0; i-- {
+		if err := m.restoreAttributes(dirs[i].path, dirs[i].file); err != nil {
 			return err
 		}
 	}
@@ -218,34 +637,121 @@ func (m *Manager) unzipArchive(zipPath, targetDir string) error {
 	return nil
 }
 
+// maxLinkTarget is the longest symlink target restore accepts
+const maxLinkTarget = 4096
+
+// extractedEntry is an archive entry whose extraction is finished later
+type extractedEntry struct {
+	path   string
+	file   *zip.File
+	target string // For symlinks
+}
+
+// readLinkTarget reads the target a symlink entry stores
+func readLinkTarget(file *zip.File) (string, error) {
+	r, err := file.Open()
+	if err != nil {
+		return "", err
+	}
+	defer r.Close()
+
+	target, err := io.ReadAll(io.LimitReader(r, maxLinkTarget+1))
+	if err != nil {
+		return "", err
+	}
+	if len(target) == 0 || len(target) > maxLinkTarget {
+		return "", fmt.Errorf("invalid symlink: %s", file.Name)
+	}
+	return string(target), nil
+}
+
+// maxLinkHops is how many links resolving a symlink may pass through, like
+// the limit the OS puts on nested links
+const maxLinkHops = 40
+
+// resolveLink resolves a symlink target found in the slash-separated
+// directory dir, relative to the restored directory, following the
+// archive's links in links like the filesystem would. It returns where
+// the link leads, and false if it is absolute, leaves the restored
+// directory or passes through too many links.
+func resolveLink(links map[string]string, dir, target string, hops int) (string, bool) {
+	target = filepath.ToSlash(target)
+	if hops > maxLinkHops || path.IsAbs(target) || filepath.IsAbs(target) || filepath.VolumeName(target) != "" {
+		return "", false
+	}
+
+	resolved := ""
+	if dir != "." {
+		resolved = dir
+	}
+	for _, name := range strings.Split(target, "/") {
+		switch name {
+		case "", ".":
+		case "..":
+			if resolved == "" {
+				return "", false
+			}
+			resolved = strings.TrimSuffix(path.Dir(resolved), ".")
+		default:
+			resolved = path.Join(resolved, name)
+			if next, ok := links[resolved]; ok {
+				if resolved, ok = resolveLink(links, path.Dir(resolved), next, hops+1); !ok {
+					return "", false
+				}
+			}
+		}
+	}
+	return resolved, true
+}
+
 // extractFile extracts a single file from zip archive
-func (m *Manager) extractFile(file *zip.File, targetPath string) error {
+func (m *Manager) extractFile(tracker *progressTracker, budget *extractionBudget, file *zip.File, targetPath string) error {
 	srcFile, err := file.Open()
 	if err != nil {
 		return err
 	}
 	defer srcFile.Close()
 
-	dstFile, err := os.OpenFile(targetPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, file.Mode())
+	dstFile, err := m.fs.OpenFile(targetPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
 	if err != nil {
 		return err
 	}
-	defer dstFile.Close()
 
-	_, err = io.Copy(dstFile, srcFile)
-	return err
+	if _, err := tracker.copy(dstFile, budget.reader(file, srcFile)); err != nil {
+		dstFile.Close()
+		return err
+	}
+
+	if err := dstFile.Close(); err != nil {
+		return err
+	}
+	return m.restoreAttributes(targetPath, file)
+}
+
+// restoreAttributes gives an extracted file or directory the permissions
+// and modification time it was archived with. Zip keeps times to the
+// second.
+func (m *Manager) restoreAttributes(path string, file *zip.File) error {
+	if err := m.fs.Chmod(path, file.Mode().Perm()); err != nil {
+		return err
+	}
+	if file.Modified.IsZero() {
+		return nil
+	}
+	return m.fs.Chtimes(path, file.Modified, file.Modified)
 }
 
-// calculateHash calculates SHA256 hash of a file
-func (m *Manager) calculateHash(filePath string) (string, error) {
-	file, err := os.Open(filePath)
+// calculateHash calculates SHA256 hash of a file, stopping with ctx's
+// error if ctx is done first
+func (m *Manager) calculateHash(ctx context.Context, filePath string) (string, error) {
+	file, err := m.fs.Open(filePath)
 	if err != nil {
 		return "", err
 	}
 	defer file.Close()
 
 	hasher := sha256.New()
-	if _, err := io.Copy(hasher, file); err != nil {
+	if _, err := newProgressTracker(ctx, nil, 0, 0).copy(hasher, file); err != nil {
 		return "", err
 	}
 
@@ -0,0 +1,786 @@
+package vault
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/flate"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+
+	"github.com/adrielfilipedesign/gamekeep/internal/fsys"
+	"github.com/adrielfilipedesign/gamekeep/internal/models"
+)
+
+// newTestManager creates a vault on a fault-injectable in-memory filesystem
+// with a small save directory at /saves
+func newTestManager(t *testing.T) (*Manager, *fsys.FaultFS) {
+	t.Helper()
+
+	fs := fsys.NewFaultFS(fsys.NewMemFS())
+	writeFiles(t, fs, map[string]string{
+		"/saves/slot1.sav":         "level=12",
+		"/saves/profiles/main.cfg": "difficulty=hard",
+	})
+
+	m, err := NewManagerWithFS(fs, "/vault")
+	if err != nil {
+		t.Fatalf("NewManagerWithFS: %v", err)
+	}
+	return m, fs
+}
+
+func writeFiles(t *testing.T, fs fsys.FS, files map[string]string) {
+	t.Helper()
+
+	for path, content := range files {
+		if err := fs.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("MkdirAll: %v", err)
+		}
+		if err := fsys.WriteFile(fs, path, []byte(content), 0644); err != nil {
+			t.Fatalf("WriteFile: %v", err)
+		}
+	}
+}
+
+func readFile(t *testing.T, fs fsys.FS, path string) string {
+	t.Helper()
+
+	data, err := fsys.ReadFile(fs, path)
+	if err != nil {
+		t.Fatalf("ReadFile %s: %v", path, err)
+	}
+	return string(data)
+}
+
+func TestCheckpointRoundTrip(t *testing.T) {
+	m, fs := newTestManager(t)
+
+	vaultFile, hash, err := m.CreateCheckpoint("game", "cp1", "/saves")
+	if err != nil {
+		t.Fatalf("CreateCheckpoint: %v", err)
+	}
+	if err := m.VerifyCheckpoint(vaultFile, hash); err != nil {
+		t.Fatalf("VerifyCheckpoint: %v", err)
+	}
+
+	// Diverge from the checkpoint, then restore it
+	writeFiles(t, fs, map[string]string{
+		"/saves/slot1.sav": "level=13",
+		"/saves/extra.sav": "new",
+	})
+
+	if err := m.RestoreCheckpoint(vaultFile, "/saves"); err != nil {
+		t.Fatalf("RestoreCheckpoint: %v", err)
+	}
+
+	if got := readFile(t, fs, "/saves/slot1.sav"); got != "level=12" {
+		t.Errorf("slot1.sav = %q, want %q", got, "level=12")
+	}
+	if got := readFile(t, fs, "/saves/profiles/main.cfg"); got != "difficulty=hard" {
+		t.Errorf("main.cfg = %q, want %q", got, "difficulty=hard")
+	}
+	if _, err := fs.Stat("/saves/extra.sav"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("extra.sav should be gone after restore, got %v", err)
+	}
+}
+
+func TestCreateCheckpointDiskFullRemovesPartialArchive(t *testing.T) {
+	m, fs := newTestManager(t)
+	fs.Inject(fsys.Fault{Op: fsys.OpWrite, Path: "cp1.zip", After: 64, Err: syscall.ENOSPC})
+
+	_, _, err := m.CreateCheckpoint("game", "cp1", "/saves")
+	if !errors.Is(err, syscall.ENOSPC) {
+		t.Fatalf("expected ENOSPC, got %v", err)
+	}
+
+	if _, err := fs.Stat("/vault/game/cp1.zip"); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("partial archive left behind: %v", err)
+	}
+}
+
+func TestParallelArchivesMatchSerialOnes(t *testing.T) {
+	m, fs := newTestManager(t)
+
+	defaultLargeFileSize := largeFileSize
+	largeFileSize = 64 << 10
+	defer func() { largeFileSize = defaultLargeFileSize }()
+
+	files := map[string]string{
+		// Large enough to be compressed as it is written rather than by a worker
+		"/saves/world.dat": strings.Repeat("chunk ", int(largeFileSize)/6+1),
+	}
+	for i := 0; i < 40; i++ {
+		files[fmt.Sprintf("/saves/slots/slot%02d.sav", i)] = strings.Repeat(fmt.Sprintf("slot %d ", i), 100*i)
+	}
+	writeFiles(t, fs, files)
+
+	serialFile, serialHash, err := m.CreateCheckpointContext(context.Background(), "game", "serial", "/saves", ArchiveOptions{Workers: 1})
+	if err != nil {
+		t.Fatalf("CreateCheckpointContext with 1 worker: %v", err)
+	}
+	parallelFile, parallelHash, err := m.CreateCheckpointContext(context.Background(), "game", "parallel", "/saves", ArchiveOptions{Workers: 4})
+	if err != nil {
+		t.Fatalf("CreateCheckpointContext with 4 workers: %v", err)
+	}
+
+	if serialHash != parallelHash {
+		t.Fatalf("hashes differ: %s with 1 worker, %s with 4", serialHash, parallelHash)
+	}
+	if readFile(t, fs, filepath.Join("/vault", serialFile)) != readFile(t, fs, filepath.Join("/vault", parallelFile)) {
+		t.Fatal("archives differ between 1 and 4 workers")
+	}
+
+	if err := m.RestoreCheckpoint(parallelFile, "/restored"); err != nil {
+		t.Fatalf("RestoreCheckpoint: %v", err)
+	}
+	for path, content := range files {
+		restored := filepath.Join("/restored", strings.TrimPrefix(path, "/saves/"))
+		if got := readFile(t, fs, restored); got != content {
+			t.Errorf("%s restored with %d bytes, want %d", restored, len(got), len(content))
+		}
+	}
+}
+
+func TestArchiveContentHash(t *testing.T) {
+	m, fs := newTestManager(t)
+
+	archive := func(name string) string {
+		contents := NewContentHasher()
+		if _, _, err := m.CreateCheckpointContext(context.Background(), "game", name, "/saves", ArchiveOptions{Contents: contents}); err != nil {
+			t.Fatalf("CreateCheckpointContext: %v", err)
+		}
+		return contents.Sum()
+	}
+
+	pooled := archive("pooled")
+
+	// Files compressed as they are written hash the same as pooled ones
+	defaultLargeFileSize := largeFileSize
+	largeFileSize = 1
+	streamed := archive("streamed")
+	largeFileSize = defaultLargeFileSize
+	if streamed != pooled {
+		t.Fatalf("content hash = %s when streamed, %s when pooled", streamed, pooled)
+	}
+
+	// Only contents count, not when they were written
+	writeFiles(t, fs, map[string]string{"/saves/slot1.sav": "level=12"})
+	if got := archive("rewritten"); got != pooled {
+		t.Fatalf("content hash changed to %s for rewritten saves", got)
+	}
+	writeFiles(t, fs, map[string]string{"/saves/slot1.sav": "level=13"})
+	if got := archive("changed"); got == pooled {
+		t.Fatal("content hash missed a changed save")
+	}
+}
+
+func TestCompressionCodecs(t *testing.T) {
+	m, fs := newTestManager(t)
+
+	defaultLargeFileSize := largeFileSize
+	largeFileSize = 4 * sampleSize
+	defer func() { largeFileSize = defaultLargeFileSize }()
+
+	noise := make([]byte, largeFileSize+1)
+	rand.New(rand.NewSource(1)).Read(noise)
+	files := map[string]string{
+		"/saves/slot1.sav":  strings.Repeat("level=12 ", 1000),
+		"/saves/world.dat":  strings.Repeat("chunk ", int(largeFileSize)/6+1),
+		"/saves/shot.png":   string(noise[:64<<10]), // Already compressed, so stored
+		"/saves/movie.webm": string(noise),
+	}
+	writeFiles(t, fs, files)
+	compresses := map[string]bool{"slot1.sav": true, "world.dat": true}
+
+	tests := []struct {
+		compression models.Compression
+		method      uint16 // Of the files that compress; the rest are stored
+	}{
+		{models.Compression{}, zip.Deflate},
+		{models.Compression{Codec: models.CodecStore}, zip.Store},
+		{models.Compression{Codec: models.CodecDeflate, Level: 9}, zip.Deflate},
+		{models.Compression{Codec: models.CodecZstd}, zstd.ZipMethodWinZip},
+		{models.Compression{Codec: models.CodecZstd, Level: 19}, zstd.ZipMethodWinZip},
+	}
+	for _, tt := range tests {
+		t.Run(tt.compression.String(), func(t *testing.T) {
+			vaultFile, _, err := m.CreateCheckpointContext(context.Background(), "game", "cp-"+tt.compression.String(), "/saves", ArchiveOptions{Compression: tt.compression})
+			if err != nil {
+				t.Fatalf("CreateCheckpointContext: %v", err)
+			}
+
+			data := readFile(t, fs, filepath.Join("/vault", vaultFile))
+			reader, err := zip.NewReader(strings.NewReader(data), int64(len(data)))
+			if err != nil {
+				t.Fatalf("zip.NewReader: %v", err)
+			}
+			for _, file := range reader.File {
+				want := uint16(zip.Store)
+				if compresses[file.Name] {
+					want = tt.method
+				}
+				if file.Method != want {
+					t.Errorf("%s has method %d, want %d", file.Name, file.Method, want)
+				}
+			}
+
+			if err := m.RestoreCheckpoint(vaultFile, "/restored"); err != nil {
+				t.Fatalf("RestoreCheckpoint: %v", err)
+			}
+			for path, content := range files {
+				restored := filepath.Join("/restored", filepath.Base(path))
+				if readFile(t, fs, restored) != content {
+					t.Errorf("%s did not restore intact", restored)
+				}
+			}
+		})
+	}
+}
+
+func TestRestoreRefusesUnknownCompression(t *testing.T) {
+	m, fs := newTestManager(t)
+
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	writer, err := archive.CreateRaw(&zip.FileHeader{Name: "slot1.sav", Method: 99, CompressedSize64: 4, UncompressedSize64: 4})
+	if err != nil {
+		t.Fatalf("CreateRaw: %v", err)
+	}
+	writer.Write([]byte("????"))
+	archive.Close()
+	writeFiles(t, fs, map[string]string{"/vault/game/future.zip": buf.String()})
+
+	err = m.RestoreCheckpoint(filepath.Join("game", "future.zip"), "/saves")
+	if !errors.Is(err, models.ErrUnsupportedCodec) {
+		t.Fatalf("RestoreCheckpoint = %v, want ErrUnsupportedCodec", err)
+	}
+	if got := readFile(t, fs, "/saves/slot1.sav"); got != "level=12" {
+		t.Fatalf("slot1.sav = %q after a refused restore", got)
+	}
+}
+
+func TestRestorePreservesAttributesAndLinks(t *testing.T) {
+	filesystems := map[string]func(t *testing.T) (fsys.FS, string){
+		"mem": func(t *testing.T) (fsys.FS, string) { return fsys.NewMemFS(), "/" },
+		"os":  func(t *testing.T) (fsys.FS, string) { return fsys.OS{}, t.TempDir() },
+	}
+	for name, newFS := range filesystems {
+		t.Run(name, func(t *testing.T) {
+			fs, root := newFS(t)
+			saves := filepath.Join(root, "saves")
+			outside := filepath.Join(root, "outside.sav")
+			writeFiles(t, fs, map[string]string{
+				filepath.Join(saves, "slot1.sav"):         "level=12",
+				filepath.Join(saves, "tools/backup.sh"):   "#!/bin/sh",
+				filepath.Join(saves, "profiles/main.cfg"): "difficulty=hard",
+				outside: "not a save",
+			})
+			escaping := map[string]string{
+				"shared.sav": outside,
+				"escape.sav": "../outside.sav",
+				// Inside by its text, but profiles/up leads to the saves
+				// themselves, so .. leaves them
+				"sneaky.sav": "profiles/up/../outside.sav",
+			}
+			kept := map[string]string{
+				"latest.sav":    "slot1.sav",
+				"profiles/slot": "../slot1.sav",
+				"profiles/up":   "..",
+				"dangling.sav":  "missing.sav",
+			}
+			links := map[string]string{}
+			for path, target := range escaping {
+				links[path] = target
+			}
+			for path, target := range kept {
+				links[path] = target
+			}
+			for path, target := range links {
+				if err := fs.Symlink(target, filepath.Join(saves, path)); err != nil {
+					t.Fatalf("Symlink: %v", err)
+				}
+			}
+
+			modes := map[string]os.FileMode{"slot1.sav": 0600, "tools/backup.sh": 0755, "profiles/main.cfg": 0640, "profiles": 0750}
+			for path, mode := range modes {
+				if err := fs.Chmod(filepath.Join(saves, path), mode); err != nil {
+					t.Fatalf("Chmod: %v", err)
+				}
+			}
+			mtimes := map[string]time.Time{
+				"slot1.sav":         time.Date(2023, 5, 1, 10, 30, 15, 0, time.UTC),
+				"tools/backup.sh":   time.Date(2022, 1, 2, 3, 4, 5, 0, time.UTC),
+				"profiles/main.cfg": time.Date(2021, 12, 24, 20, 0, 0, 0, time.UTC),
+				"profiles":          time.Date(2021, 12, 25, 8, 0, 0, 0, time.UTC),
+			}
+			for path, mtime := range mtimes {
+				if err := fs.Chtimes(filepath.Join(saves, path), mtime, mtime); err != nil {
+					t.Fatalf("Chtimes: %v", err)
+				}
+			}
+
+			m, err := NewManagerWithFS(fs, filepath.Join(root, "vault"))
+			if err != nil {
+				t.Fatalf("NewManagerWithFS: %v", err)
+			}
+			vaultFile, _, err := m.CreateCheckpoint("game", "cp1", saves)
+			if err != nil {
+				t.Fatalf("CreateCheckpoint: %v", err)
+			}
+
+			// Archiving stored the links rather than following them
+			writeFiles(t, fs, map[string]string{outside: "changed"})
+			if err := fs.RemoveAll(saves); err != nil {
+				t.Fatalf("RemoveAll: %v", err)
+			}
+			skipped := map[string]string{}
+			opts := RestoreOptions{SkippedLink: func(name, target string) { skipped[name] = target }}
+			if err := m.RestoreCheckpointContext(context.Background(), vaultFile, saves, opts); err != nil {
+				t.Fatalf("RestoreCheckpointContext: %v", err)
+			}
+
+			for path, mode := range modes {
+				if info, err := fs.Lstat(filepath.Join(saves, path)); err != nil || info.Mode().Perm() != mode {
+					t.Errorf("%s restored with mode %v, want %v (%v)", path, info.Mode().Perm(), mode, err)
+				}
+			}
+			for path, mtime := range mtimes {
+				if info, err := fs.Lstat(filepath.Join(saves, path)); err != nil || !info.ModTime().Equal(mtime) {
+					t.Errorf("%s restored with modification time %v, want %v (%v)", path, info.ModTime(), mtime, err)
+				}
+			}
+			for path, target := range kept {
+				if got, err := fs.Readlink(filepath.Join(saves, path)); err != nil || got != target {
+					t.Errorf("%s restored as a link to %q, want %q (%v)", path, got, target, err)
+				}
+			}
+
+			// Links leading out of the saves are left out and reported
+			for path, target := range escaping {
+				if _, err := fs.Lstat(filepath.Join(saves, path)); !os.IsNotExist(err) {
+					t.Errorf("%s -> %s was restored (%v)", path, target, err)
+				}
+				if got, ok := skipped[path]; !ok || got != target {
+					t.Errorf("skipped %s -> %q, %v; want it reported with %q", path, got, ok, target)
+				}
+			}
+			if len(skipped) != len(escaping) {
+				t.Errorf("skipped %v, want only the escaping links", skipped)
+			}
+			if got := readFile(t, fs, filepath.Join(saves, "latest.sav")); got != "level=12" {
+				t.Errorf("latest.sav reads %q through its link", got)
+			}
+			if got := readFile(t, fs, outside); got != "changed" {
+				t.Errorf("restore wrote through a link to outside the saves: %q", got)
+			}
+		})
+	}
+}
+
+func TestRestoreNeverWritesThroughArchivedLinks(t *testing.T) {
+	m, fs := newTestManager(t)
+
+	// A crafted archive linking to a directory among the saves, then
+	// writing into it; links leading outside are left out anyway
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	writer, _ := archive.Create("profiles/main.cfg")
+	io.WriteString(writer, "difficulty=hard")
+	link := &zip.FileHeader{Name: "data"}
+	link.SetMode(os.ModeSymlink | 0777)
+	writer, _ = archive.CreateHeader(link)
+	io.WriteString(writer, "profiles")
+	writer, _ = archive.Create("data/main.cfg")
+	io.WriteString(writer, "overwritten")
+	archive.Close()
+	writeFiles(t, fs, map[string]string{"/vault/game/evil.zip": buf.String()})
+
+	if err := m.RestoreCheckpoint(filepath.Join("game", "evil.zip"), "/saves"); err == nil {
+		t.Fatal("RestoreCheckpoint of an archive writing through a link succeeded")
+	}
+	if got := readFile(t, fs, "/saves/profiles/main.cfg"); got != "difficulty=hard" {
+		t.Fatalf("main.cfg = %q, written through the archived link", got)
+	}
+	if got := readFile(t, fs, "/saves/slot1.sav"); got != "level=12" {
+		t.Fatalf("slot1.sav = %q after a failed restore", got)
+	}
+}
+
+func TestRestoreEnforcesArchiveLimits(t *testing.T) {
+	zipOf := func(files map[string][]byte) []byte {
+		var buf bytes.Buffer
+		archive := zip.NewWriter(&buf)
+		for name, data := range files {
+			writer, _ := archive.Create(name)
+			writer.Write(data)
+		}
+		archive.Close()
+		return buf.Bytes()
+	}
+
+	// An archive whose end record claims far more entries than it has
+	inflated := zipOf(map[string][]byte{"slot1.sav": []byte("level=1")})
+	binary.LittleEndian.PutUint16(inflated[len(inflated)-12:], 60000)
+
+	zeros := make([]byte, 2<<20)
+
+	// Zeros packed as tightly as deflate can, at about 1030:1
+	var deflated bytes.Buffer
+	archive := zip.NewWriter(&deflated)
+	archive.RegisterCompressor(zip.Deflate, func(w io.Writer) (io.WriteCloser, error) {
+		return flate.NewWriter(w, flate.BestCompression)
+	})
+	writer, _ := archive.Create("slot1.sav")
+	writer.Write(zeros)
+	archive.Close()
+
+	tests := []struct {
+		name    string
+		archive []byte
+		limits  models.ArchiveLimits
+		want    models.ArchiveLimit
+	}{
+		{"bomb", zipOf(map[string][]byte{"slot1.sav": zeros}), models.ArchiveLimits{MaxRatio: 100}, models.LimitRatio},
+		{"default ratio", deflated.Bytes(), models.DefaultArchiveLimits(), models.LimitRatio},
+		{"size", zipOf(map[string][]byte{"a.sav": zeros[:1<<19], "b.sav": zeros[:1<<19], "c.sav": zeros[:1<<19]}), models.ArchiveLimits{MaxSizeMB: 1}, models.LimitSize},
+		{"entries", zipOf(map[string][]byte{"a": nil, "b": nil, "c": nil, "d": nil}), models.ArchiveLimits{MaxEntries: 3}, models.LimitEntries},
+		{"declared entries", inflated, models.ArchiveLimits{MaxEntries: 10}, models.LimitEntries},
+		{"depth", zipOf(map[string][]byte{"a/b/c/slot1.sav": nil}), models.ArchiveLimits{MaxDepth: 2}, models.LimitDepth},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, fs := newTestManager(t)
+			writeFiles(t, fs, map[string]string{"/vault/game/bomb.zip": string(tt.archive)})
+
+			err := m.RestoreCheckpointContext(context.Background(), filepath.Join("game", "bomb.zip"), "/saves", RestoreOptions{Limits: &tt.limits})
+			var limitErr *models.ArchiveLimitError
+			if !errors.Is(err, models.ErrArchiveLimit) || !errors.As(err, &limitErr) || limitErr.Limit != tt.want {
+				t.Fatalf("restore = %v, want the %s limit", err, tt.want)
+			}
+			if got := readFile(t, fs, "/saves/slot1.sav"); got != "level=12" {
+				t.Fatalf("slot1.sav = %q after a refused restore", got)
+			}
+
+			// Turning the limits off lets every archive but the lying one through
+			if tt.name != "declared entries" {
+				if err := m.RestoreCheckpointContext(context.Background(), filepath.Join("game", "bomb.zip"), "/saves", RestoreOptions{Limits: &models.ArchiveLimits{}}); err != nil {
+					t.Fatalf("restore without limits: %v", err)
+				}
+			}
+		})
+	}
+}
+
+func TestExtractionBudgetCountsRealSizes(t *testing.T) {
+	// The directory says one byte; the budget counts what really comes out
+	file := &zip.File{FileHeader: zip.FileHeader{Name: "slot1.sav", UncompressedSize64: 1, CompressedSize64: 1}}
+	budget := &extractionBudget{limits: models.ArchiveLimits{MaxSizeMB: 1}}
+	_, err := io.Copy(io.Discard, budget.reader(file, bytes.NewReader(make([]byte, 2<<20))))
+	var limitErr *models.ArchiveLimitError
+	if !errors.As(err, &limitErr) || limitErr.Limit != models.LimitSize {
+		t.Fatalf("copy = %v, want the size limit", err)
+	}
+}
+
+func TestCheckpointProgress(t *testing.T) {
+	m, fs := newTestManager(t)
+
+	var reports []models.Progress
+	vaultFile, _, err := m.CreateCheckpointContext(context.Background(), "game", "cp1", "/saves", ArchiveOptions{Progress: func(p models.Progress) {
+		reports = append(reports, p)
+	}})
+	if err != nil {
+		t.Fatalf("CreateCheckpointContext: %v", err)
+	}
+	last := reports[len(reports)-1]
+	if last.FilesDone != 2 || last.FilesTotal != 2 || last.BytesDone != last.BytesTotal || last.BytesTotal != 23 {
+		t.Fatalf("last create progress = %+v, want 2 files and 23 bytes done", last)
+	}
+
+	reports = nil
+	writeFiles(t, fs, map[string]string{"/saves/slot1.sav": "level=99"})
+	if err := m.RestoreCheckpointContext(context.Background(), vaultFile, "/saves", RestoreOptions{Progress: func(p models.Progress) {
+		reports = append(reports, p)
+	}}); err != nil {
+		t.Fatalf("RestoreCheckpointContext: %v", err)
+	}
+	if last := reports[len(reports)-1]; last.Fraction() != 1 || last.FilesDone != 2 {
+		t.Fatalf("last restore progress = %+v, want all done", last)
+	}
+}
+
+func TestCancelledCheckpointCleansUp(t *testing.T) {
+	m, fs := newTestManager(t)
+
+	// Cancel as soon as the first file is done
+	ctx, cancel := context.WithCancel(context.Background())
+	_, _, err := m.CreateCheckpointContext(ctx, "game", "cp1", "/saves", ArchiveOptions{Progress: func(models.Progress) { cancel() }})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("cancelled create = %v, want context.Canceled", err)
+	}
+	if _, err := fs.Stat("/vault/game/cp1.zip"); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("partial archive left behind: %v", err)
+	}
+
+	vaultFile, _, err := m.CreateCheckpoint("game", "cp2", "/saves")
+	if err != nil {
+		t.Fatalf("CreateCheckpoint: %v", err)
+	}
+	writeFiles(t, fs, map[string]string{"/saves/slot1.sav": "level=99"})
+
+	ctx, cancel = context.WithCancel(context.Background())
+	err = m.RestoreCheckpointContext(ctx, vaultFile, "/saves", RestoreOptions{Progress: func(models.Progress) { cancel() }})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("cancelled restore = %v, want context.Canceled", err)
+	}
+	if got := readFile(t, fs, "/saves/slot1.sav"); got != "level=99" {
+		t.Errorf("cancelled restore changed the saves to %q", got)
+	}
+	if _, err := fs.Stat("/saves.gamekeep-restore"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("staging directory left behind: %v", err)
+	}
+}
+
+func TestCreateCheckpointUnreadableSave(t *testing.T) {
+	m, fs := newTestManager(t)
+	fs.Inject(fsys.Fault{Op: fsys.OpOpen, Path: "slot1.sav", Err: os.ErrPermission})
+
+	_, _, err := m.CreateCheckpoint("game", "cp1", "/saves")
+	if !errors.Is(err, os.ErrPermission) {
+		t.Fatalf("expected permission error, got %v", err)
+	}
+
+	if _, err := fs.Stat("/vault/game/cp1.zip"); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("partial archive left behind: %v", err)
+	}
+}
+
+func TestCreateCheckpointMissingSavePath(t *testing.T) {
+	m, _ := newTestManager(t)
+
+	_, _, err := m.CreateCheckpoint("game", "cp1", "/nowhere")
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected ErrNotExist, got %v", err)
+	}
+}
+
+func TestVerifyCheckpointDetectsCorruption(t *testing.T) {
+	m, fs := newTestManager(t)
+
+	vaultFile, hash, err := m.CreateCheckpoint("game", "cp1", "/saves")
+	if err != nil {
+		t.Fatalf("CreateCheckpoint: %v", err)
+	}
+
+	file, err := fs.OpenFile(filepath.Join("/vault", vaultFile), os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatalf("OpenFile: %v", err)
+	}
+	file.Write([]byte("garbage"))
+	file.Close()
+
+	err = m.VerifyCheckpoint(vaultFile, hash)
+	if err == nil || !strings.Contains(err.Error(), "hash mismatch") {
+		t.Fatalf("expected hash mismatch, got %v", err)
+	}
+}
+
+func TestVerifyCacheSkipsUnchangedArchives(t *testing.T) {
+	m, fs := newTestManager(t)
+	ctx := context.Background()
+
+	vaultFile, hash, err := m.CreateCheckpoint("game", "cp1", "/saves")
+	if err != nil {
+		t.Fatalf("CreateCheckpoint: %v", err)
+	}
+
+	// The hash taken while writing matches the archive on disk
+	if got, err := m.calculateHash(ctx, filepath.Join("/vault", vaultFile)); err != nil || got != hash {
+		t.Fatalf("archive hash = %s, %v; CreateCheckpoint returned %s", got, err, hash)
+	}
+
+	// An unchanged archive isn't read again unless asked
+	fs.Inject(fsys.Fault{Op: fsys.OpRead, Path: "cp1.zip", Err: syscall.EIO})
+	if err := m.VerifyCheckpointContext(ctx, vaultFile, hash, true); err != nil {
+		t.Fatalf("cached verify: %v", err)
+	}
+	if err := m.VerifyCheckpointContext(ctx, vaultFile, hash, false); !errors.Is(err, syscall.EIO) {
+		t.Fatalf("uncached verify = %v, want the read error", err)
+	}
+	fs.Reset()
+
+	// A changed archive is
+	file, err := fs.OpenFile(filepath.Join("/vault", vaultFile), os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatalf("OpenFile: %v", err)
+	}
+	file.Write([]byte("garbage"))
+	file.Close()
+
+	err = m.VerifyCheckpointContext(ctx, vaultFile, hash, true)
+	if err == nil || !strings.Contains(err.Error(), "hash mismatch") {
+		t.Fatalf("cached verify of changed archive = %v, want hash mismatch", err)
+	}
+}
+
+func TestTrashForgetsCachedVerification(t *testing.T) {
+	m, fs := newTestManager(t)
+	ctx := context.Background()
+
+	vaultFile, hash, err := m.CreateCheckpoint("game", "cp1", "/saves")
+	if err != nil {
+		t.Fatalf("CreateCheckpoint: %v", err)
+	}
+	trashFile, err := m.TrashCheckpoint(vaultFile)
+	if err != nil {
+		t.Fatalf("TrashCheckpoint: %v", err)
+	}
+	if _, ok := m.loadVerifyCache()[filepath.ToSlash(vaultFile)]; ok {
+		t.Fatal("trashed checkpoint still cached under its vault name")
+	}
+
+	// Cache a result under the vault name, as a later checkpoint reusing
+	// it would, then bring the trashed one back over it
+	writeFiles(t, fs, map[string]string{filepath.Join("/vault", vaultFile): "other"})
+	m.rememberHash(vaultFile, "stale")
+	fs.Remove(filepath.Join("/vault", vaultFile))
+	if err := m.UntrashCheckpoint(trashFile, vaultFile); err != nil {
+		t.Fatalf("UntrashCheckpoint: %v", err)
+	}
+	if _, ok := m.loadVerifyCache()[filepath.ToSlash(vaultFile)]; ok {
+		t.Fatal("untrashed checkpoint trusted a result cached for another file")
+	}
+	if err := m.VerifyCheckpointContext(ctx, vaultFile, hash, true); err != nil {
+		t.Fatalf("verify after untrash: %v", err)
+	}
+}
+
+func TestVerifyCheckpointReadError(t *testing.T) {
+	m, fs := newTestManager(t)
+
+	vaultFile, hash, err := m.CreateCheckpoint("game", "cp1", "/saves")
+	if err != nil {
+		t.Fatalf("CreateCheckpoint: %v", err)
+	}
+
+	fs.Inject(fsys.Fault{Op: fsys.OpRead, Path: "cp1.zip", After: 10, Err: syscall.EIO})
+
+	if err := m.VerifyCheckpoint(vaultFile, hash); !errors.Is(err, syscall.EIO) {
+		t.Fatalf("expected EIO, got %v", err)
+	}
+}
+
+func TestRestoreFailureKeepsExistingSaves(t *testing.T) {
+	tests := []struct {
+		name  string
+		fault fsys.Fault
+	}{
+		{
+			name:  "disk full while extracting",
+			fault: fsys.Fault{Op: fsys.OpWrite, Path: "main.cfg", After: 2, Err: syscall.ENOSPC},
+		},
+		{
+			name:  "permission denied creating file",
+			fault: fsys.Fault{Op: fsys.OpCreate, Path: "slot1.sav", Err: os.ErrPermission},
+		},
+		{
+			name:  "archive read error",
+			fault: fsys.Fault{Op: fsys.OpRead, Path: "cp1.zip", After: 0, Err: syscall.EIO},
+		},
+		{
+			name:  "cannot move existing saves aside",
+			fault: fsys.Fault{Op: fsys.OpRename, Path: "/saves", Err: os.ErrPermission},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, fs := newTestManager(t)
+
+			vaultFile, _, err := m.CreateCheckpoint("game", "cp1", "/saves")
+			if err != nil {
+				t.Fatalf("CreateCheckpoint: %v", err)
+			}
+			writeFiles(t, fs, map[string]string{"/saves/slot1.sav": "level=99"})
+
+			fs.Inject(tt.fault)
+			if err := m.RestoreCheckpoint(vaultFile, "/saves"); err == nil {
+				t.Fatal("expected restore to fail")
+			}
+			fs.Reset()
+
+			if got := readFile(t, fs, "/saves/slot1.sav"); got != "level=99" {
+				t.Errorf("existing save changed to %q", got)
+			}
+			if _, err := fs.Stat("/saves.gamekeep-restore"); !errors.Is(err, os.ErrNotExist) {
+				t.Errorf("staging directory left behind: %v", err)
+			}
+		})
+	}
+}
+
+func TestRestoreMissingArchive(t *testing.T) {
+	m, fs := newTestManager(t)
+
+	if err := m.RestoreCheckpoint("game/missing.zip", "/saves"); err == nil {
+		t.Fatal("expected error for missing archive")
+	}
+	if got := readFile(t, fs, "/saves/slot1.sav"); got != "level=12" {
+		t.Errorf("existing save changed to %q", got)
+	}
+}
+
+// BenchmarkCreateCheckpoint archives a save directory of many compressible
+// files from disk with different numbers of workers
+func BenchmarkCreateCheckpoint(b *testing.B) {
+	saveDir := filepath.Join(b.TempDir(), "saves")
+	if err := os.MkdirAll(saveDir, 0755); err != nil {
+		b.Fatal(err)
+	}
+	var size int64
+	for i := 0; i < 64; i++ {
+		var data strings.Builder
+		for data.Len() < 1<<20 {
+			fmt.Fprintf(&data, "region %d entity %d health=%d\n", i, data.Len(), data.Len()%97)
+		}
+		if err := os.WriteFile(filepath.Join(saveDir, fmt.Sprintf("region%02d.dat", i)), []byte(data.String()), 0644); err != nil {
+			b.Fatal(err)
+		}
+		size += int64(data.Len())
+	}
+
+	m, err := NewManagerWithFS(fsys.OS{}, filepath.Join(b.TempDir(), "vault"))
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	counts := []int{1, 2, 4}
+	if runtime.NumCPU() > 4 {
+		counts = append(counts, runtime.NumCPU())
+	}
+	for _, workers := range counts {
+		b.Run(fmt.Sprintf("workers=%d", workers), func(b *testing.B) {
+			b.SetBytes(size)
+			for i := 0; i < b.N; i++ {
+				checkpointID := fmt.Sprintf("w%d-%d", workers, i)
+				if _, _, err := m.CreateCheckpointContext(context.Background(), "game", checkpointID, saveDir, ArchiveOptions{Workers: workers}); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package vault
+
+import (
+	"context"
+	"io"
+
+	"github.com/adrielfilipedesign/gamekeep/internal/models"
+)
+
+// progressStep is the share of an operation's bytes that must pass between
+// reports, so large saves don't flood callers with them
+const progressStep = 0.01
+
+// progressTracker counts the work an archive operation has done, reports
+// it and stops the work once its context is done
+type progressTracker struct {
+	ctx      context.Context
+	report   models.ProgressFunc // May be nil
+	progress models.Progress
+	reported int64 // BytesDone at the last report
+}
+
+// newProgressTracker starts tracking an operation of files files and bytes
+// bytes in total
+func newProgressTracker(ctx context.Context, report models.ProgressFunc, files int, bytes int64) *progressTracker {
+	return &progressTracker{
+		ctx:    ctx,
+		report: report,
+		progress: models.Progress{
+			FilesTotal: files,
+			BytesTotal: bytes,
+		},
+	}
+}
+
+// startFile notes the file being worked on, or returns the context's error
+// if the operation should stop
+func (t *progressTracker) startFile(name string) error {
+	if err := t.ctx.Err(); err != nil {
+		return err
+	}
+	t.progress.Item = name
+	return nil
+}
+
+// finishFile counts a file as done
+func (t *progressTracker) finishFile() {
+	t.progress.FilesDone++
+	t.send()
+}
+
+// add counts bytes, reporting when enough have passed since the last report
+func (t *progressTracker) add(n int) {
+	t.progress.BytesDone += int64(n)
+	if float64(t.progress.BytesDone-t.reported) >= progressStep*float64(t.progress.BytesTotal) {
+		t.send()
+	}
+}
+
+// send reports the progress so far
+func (t *progressTracker) send() {
+	t.reported = t.progress.BytesDone
+	if t.report != nil {
+		t.report(t.progress)
+	}
+}
+
+// copy copies src to dst, counting the bytes and stopping early with the
+// context's error if the operation should stop
+func (t *progressTracker) copy(dst io.Writer, src io.Reader) (int64, error) {
+	return io.Copy(dst, &progressReader{tracker: t, r: src})
+}
+
+// progressReader counts bytes read for a tracker
+type progressReader struct {
+	tracker *progressTracker
+	r       io.Reader
+}
+
+func (r *progressReader) Read(p []byte) (int, error) {
+	if err := r.tracker.ctx.Err(); err != nil {
+		return 0, err
+	}
+	n, err := r.r.Read(p)
+	r.tracker.add(n)
+	return n, err
+}
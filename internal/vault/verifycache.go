@@ -0,0 +1,95 @@
+package vault
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"time"
+
+	"github.com/adrielfilipedesign/gamekeep/internal/fsys"
+)
+
+// verifyCacheName is the vault file remembering which archives have been
+// hashed, so unchanged ones needn't be read again to verify them
+const verifyCacheName = ".verified.json"
+
+// verifiedArchive is an archive's hash as of its size and modification time.
+// Archives are never rewritten in place, so a change to either means the
+// archive must be hashed again. It guards against damage, not tampering.
+type verifiedArchive struct {
+	Size    int64     `json:"size"`
+	ModTime time.Time `json:"mod_time"`
+	Hash    string    `json:"hash"`
+}
+
+// cachedHash returns the remembered hash of an archive, if the archive
+// hasn't changed since
+func (m *Manager) cachedHash(vaultFile string) (string, bool) {
+	info, err := m.fs.Stat(filepath.Join(m.vaultDir, vaultFile))
+	if err != nil {
+		return "", false
+	}
+
+	cached, ok := m.loadVerifyCache()[filepath.ToSlash(vaultFile)]
+	if !ok || cached.Size != info.Size() || !cached.ModTime.Equal(info.ModTime()) {
+		return "", false
+	}
+	return cached.Hash, true
+}
+
+// rememberHash records an archive's hash as of now. The cache is only an
+// optimization, so failures to update it are ignored.
+func (m *Manager) rememberHash(vaultFile, hash string) {
+	info, err := m.fs.Stat(filepath.Join(m.vaultDir, vaultFile))
+	if err != nil {
+		return
+	}
+
+	m.updateVerifyCache(func(cache map[string]verifiedArchive) {
+		cache[filepath.ToSlash(vaultFile)] = verifiedArchive{
+			Size:    info.Size(),
+			ModTime: info.ModTime(),
+			Hash:    hash,
+		}
+	})
+}
+
+// forgetHash drops a deleted archive from the cache
+func (m *Manager) forgetHash(vaultFile string) {
+	m.updateVerifyCache(func(cache map[string]verifiedArchive) {
+		delete(cache, filepath.ToSlash(vaultFile))
+	})
+}
+
+// loadVerifyCache reads the cache; a missing or unreadable one is empty
+func (m *Manager) loadVerifyCache() map[string]verifiedArchive {
+	cache := map[string]verifiedArchive{}
+	data, err := fsys.ReadFile(m.fs, filepath.Join(m.vaultDir, verifyCacheName))
+	if err == nil {
+		json.Unmarshal(data, &cache)
+	}
+	return cache
+}
+
+// updateVerifyCache changes the cache and writes it back through a temp
+// file, so readers never see a partial one
+func (m *Manager) updateVerifyCache(change func(map[string]verifiedArchive)) {
+	m.verifyMu.Lock()
+	defer m.verifyMu.Unlock()
+
+	cache := m.loadVerifyCache()
+	change(cache)
+
+	data, err := json.Marshal(cache)
+	if err != nil {
+		return
+	}
+
+	path := filepath.Join(m.vaultDir, verifyCacheName)
+	if err := fsys.WriteFile(m.fs, path+".tmp", data, 0644); err != nil {
+		m.fs.Remove(path + ".tmp")
+		return
+	}
+	if err := m.fs.Rename(path+".tmp", path); err != nil {
+		m.fs.Remove(path + ".tmp")
+	}
+}
@@ -1,7 +1,10 @@
 package ui
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"strings"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/container"
@@ -19,8 +22,22 @@ type CheckpointsView struct {
 	checkpoints []models.Checkpoint
 	container   *fyne.Container
 	emptyLabel  *widget.Label
+	tagSelect   *widget.Select
+	tagFilter   string
+	sortSelect  *widget.Select
+	whereEntry  *widget.Entry
 }
 
+// allTagsOption is the tag filter option that shows every checkpoint
+const allTagsOption = "All tags"
+
+// Built-in sort options; custom fields add "<key> ↑" and "<key> ↓"
+const (
+	sortOldest = "Oldest first"
+	sortNewest = "Newest first"
+	sortName   = "Name"
+)
+
 // NewCheckpointsView creates a new checkpoints view
 func NewCheckpointsView(mainUI *MainUI) *CheckpointsView {
 	return &CheckpointsView{
@@ -64,9 +81,46 @@ func (v *CheckpointsView) Build() fyne.CanvasObject {
 
 	buttons := container.NewHBox(createBtn)
 
+	// Tag filter
+	v.tagSelect = widget.NewSelect([]string{allTagsOption}, func(selected string) {
+		filter := selected
+		if filter == allTagsOption {
+			filter = ""
+		}
+		if filter == v.tagFilter {
+			return
+		}
+		v.tagFilter = filter
+		if v.currentGame != nil {
+			v.LoadCheckpoints(v.currentGame)
+		}
+	})
+	v.tagSelect.SetSelected(allTagsOption)
+
+	// Sorting and field filters
+	v.sortSelect = widget.NewSelect([]string{sortOldest, sortNewest, sortName}, func(string) {
+		if v.currentGame != nil {
+			v.LoadCheckpoints(v.currentGame)
+		}
+	})
+	v.sortSelect.SetSelected(sortOldest)
+
+	v.whereEntry = widget.NewEntry()
+	v.whereEntry.SetPlaceHolder("Fields, e.g. level>=10, chapter=3")
+	v.whereEntry.OnSubmitted = func(string) {
+		if v.currentGame != nil {
+			v.LoadCheckpoints(v.currentGame)
+		}
+	}
+
+	filterBar := container.NewVBox(
+		container.NewBorder(nil, nil, widget.NewLabel(IconTag+" Filter:"), v.sortSelect, v.tagSelect),
+		v.whereEntry,
+	)
+
 	// Container with conditional content
 	v.container = container.NewBorder(
-		nil,
+		filterBar,
 		buttons,
 		nil,
 		nil,
@@ -82,21 +136,32 @@ func (v *CheckpointsView) createCheckpointCard() fyne.CanvasObject {
 	dateLabel := widget.NewLabel("")
 	noteLabel := widget.NewLabel("")
 	noteLabel.Wrapping = fyne.TextWrapWord
+	tagsBox := container.NewHBox()
+	fieldsLabel := widget.NewLabel("")
+	fieldsLabel.Wrapping = fyne.TextWrapWord
 
 	info := container.NewVBox(
 		nameLabel,
 		dateLabel,
 		noteLabel,
+		tagsBox,
+		fieldsLabel,
 	)
 
 	restoreBtn := widget.NewButton(IconRestore+" Restore", func() {})
 	restoreBtn.Importance = widget.HighImportance
 
+	editBtn := widget.NewButton(IconEdit, func() {})
+
+	pinBtn := widget.NewButton(IconPin, func() {})
+
 	deleteBtn := widget.NewButton(IconDelete, func() {})
 	deleteBtn.Importance = widget.DangerImportance
 
 	actions := container.NewHBox(
 		restoreBtn,
+		editBtn,
+		pinBtn,
 		deleteBtn,
 	)
 
@@ -122,7 +187,11 @@ func (v *CheckpointsView) updateCheckpointCard(obj fyne.CanvasObject, cp models.
 	dateLabel := info.Objects[1].(*widget.Label)
 	noteLabel := info.Objects[2].(*widget.Label)
 
-	nameLabel.SetText(IconCheckpoint + " " + cp.Name)
+	if cp.Protected {
+		nameLabel.SetText(IconPin + " " + cp.Name)
+	} else {
+		nameLabel.SetText(IconCheckpoint + " " + cp.Name)
+	}
 	dateLabel.SetText(fmt.Sprintf("Created: %s", cp.CreatedAt.Local().Format("2006-01-02 15:04")))
 
 	if cp.Note != "" {
@@ -132,14 +201,63 @@ func (v *CheckpointsView) updateCheckpointCard(obj fyne.CanvasObject, cp models.
 		noteLabel.Hide()
 	}
 
+	// Tag chips
+	tagsBox := info.Objects[3].(*fyne.Container)
+	tagsBox.Objects = nil
+	for _, tag := range cp.Tags {
+		chip := widget.NewLabel(IconTag + " " + tag)
+		chip.Importance = widget.HighImportance
+		tagsBox.Add(chip)
+	}
+	if len(cp.Tags) > 0 {
+		tagsBox.Show()
+	} else {
+		tagsBox.Hide()
+	}
+
+	// Custom fields and what was read from the saves
+	var details []string
+	if len(cp.Fields) > 0 && v.currentGame != nil {
+		details = append(details, formatFields(v.currentGame.Fields, cp.Fields))
+	}
+	if cp.SaveInfo != nil {
+		if summary := cp.SaveInfo.Summary(); summary != "" {
+			details = append(details, "Save: "+summary)
+		}
+	}
+	fieldsLabel := info.Objects[4].(*widget.Label)
+	if len(details) > 0 {
+		fieldsLabel.SetText(strings.Join(details, "\n"))
+		fieldsLabel.Show()
+	} else {
+		fieldsLabel.Hide()
+	}
+
 	// Update buttons
 	restoreBtn := actions.Objects[0].(*widget.Button)
-	deleteBtn := actions.Objects[1].(*widget.Button)
+	editBtn := actions.Objects[1].(*widget.Button)
+	pinBtn := actions.Objects[2].(*widget.Button)
+	deleteBtn := actions.Objects[3].(*widget.Button)
 
 	restoreBtn.OnTapped = func() {
 		v.confirmRestore(cp)
 	}
 
+	editBtn.OnTapped = func() {
+		v.showEditCheckpointDialog(cp)
+	}
+
+	// Highlight the pin while protected
+	if cp.Protected {
+		pinBtn.Importance = widget.WarningImportance
+	} else {
+		pinBtn.Importance = widget.MediumImportance
+	}
+	pinBtn.OnTapped = func() {
+		v.toggleProtected(cp)
+	}
+	pinBtn.Refresh()
+
 	deleteBtn.OnTapped = func() {
 		v.confirmDelete(cp)
 	}
@@ -148,8 +266,17 @@ func (v *CheckpointsView) updateCheckpointCard(obj fyne.CanvasObject, cp models.
 // LoadCheckpoints loads checkpoints for a game
 func (v *CheckpointsView) LoadCheckpoints(game *models.Game) {
 	v.currentGame = game
+	v.mainUI.gameDetailView.Load(game) // Keep the stats in step
+	v.loadTags(game)
+	v.loadSortOptions(game)
+
+	query, err := v.query()
+	if err != nil {
+		ShowError(v.mainUI.GetWindow(), "Invalid field filter", err)
+		return
+	}
 
-	checkpoints, err := v.mainUI.GetService().ListCheckpoints(game.ID)
+	checkpoints, err := v.mainUI.GetService().QueryCheckpoints(game.ID, query)
 	if err != nil {
 		ShowError(v.mainUI.GetWindow(), "Failed to load checkpoints", err)
 		return
@@ -160,6 +287,10 @@ func (v *CheckpointsView) LoadCheckpoints(game *models.Game) {
 	// Update UI
 	if len(v.checkpoints) > 0 {
 		v.container.Objects[0] = v.list
+	} else if query.Tag != "" || len(query.Filters) > 0 {
+		emptyMsg := widget.NewLabel(fmt.Sprintf("No matching checkpoints for %s", game.Name))
+		emptyMsg.Alignment = fyne.TextAlignCenter
+		v.container.Objects[0] = emptyMsg
 	} else {
 		emptyMsg := widget.NewLabel(fmt.Sprintf("No checkpoints yet for %s\nClick 'Create Checkpoint' to create one", game.Name))
 		emptyMsg.Alignment = fyne.TextAlignCenter
@@ -170,6 +301,95 @@ func (v *CheckpointsView) LoadCheckpoints(game *models.Game) {
 	v.list.Refresh()
 }
 
+// loadTags fills the tag filter with the game's tags, dropping a filter
+// for a tag the game no longer uses
+func (v *CheckpointsView) loadTags(game *models.Game) {
+	tags, err := v.mainUI.GetService().ListTags(game.ID)
+	if err != nil {
+		return
+	}
+
+	found := false
+	for _, tag := range tags {
+		if tag == v.tagFilter {
+			found = true
+		}
+	}
+	if !found {
+		v.tagFilter = ""
+	}
+
+	v.tagSelect.Options = append([]string{allTagsOption}, tags...)
+	if v.tagFilter == "" {
+		v.tagSelect.SetSelected(allTagsOption)
+	}
+	v.tagSelect.Refresh()
+}
+
+// loadSortOptions offers sorting by the game's custom fields, falling back
+// to creation order if the selected field no longer exists
+func (v *CheckpointsView) loadSortOptions(game *models.Game) {
+	options := []string{sortOldest, sortNewest, sortName}
+	for _, def := range game.Fields {
+		options = append(options, def.Key+" ↑", def.Key+" ↓")
+	}
+
+	selected := v.sortSelect.Selected
+	v.sortSelect.Options = options
+	found := false
+	for _, option := range options {
+		if option == selected {
+			found = true
+		}
+	}
+	if !found {
+		v.sortSelect.Selected = sortOldest
+	}
+	v.sortSelect.Refresh()
+}
+
+// query builds the checkpoint query from the filter bar
+func (v *CheckpointsView) query() (models.CheckpointQuery, error) {
+	query := models.CheckpointQuery{Tag: v.tagFilter}
+
+	switch selected := v.sortSelect.Selected; {
+	case selected == sortNewest:
+		query.SortBy, query.Descending = "created", true
+	case selected == sortName:
+		query.SortBy = "name"
+	case strings.HasSuffix(selected, " ↑"):
+		query.SortBy = strings.TrimSuffix(selected, " ↑")
+	case strings.HasSuffix(selected, " ↓"):
+		query.SortBy, query.Descending = strings.TrimSuffix(selected, " ↓"), true
+	}
+
+	for _, expr := range strings.Split(v.whereEntry.Text, ",") {
+		if strings.TrimSpace(expr) == "" {
+			continue
+		}
+		filter, err := models.ParseFieldFilter(expr)
+		if err != nil {
+			return query, err
+		}
+		query.Filters = append(query.Filters, filter)
+	}
+
+	return query, nil
+}
+
+// Clear empties the view when no game is selected
+func (v *CheckpointsView) Clear() {
+	v.currentGame = nil
+	v.checkpoints = []models.Checkpoint{}
+	v.tagFilter = ""
+	v.tagSelect.Options = []string{allTagsOption}
+	v.tagSelect.SetSelected(allTagsOption)
+	v.whereEntry.SetText("")
+	v.container.Objects[0] = v.emptyLabel
+	v.container.Refresh()
+	v.list.Refresh()
+}
+
 // showCreateCheckpointDialog shows dialog to create checkpoint
 func (v *CheckpointsView) showCreateCheckpointDialog() {
 	nameEntry := widget.NewEntry()
@@ -179,12 +399,15 @@ func (v *CheckpointsView) showCreateCheckpointDialog() {
 	noteEntry.SetPlaceHolder("Optional notes...")
 	noteEntry.SetMinRowsVisible(3)
 
+	fieldsForm, collectFields := newFieldInputs(v.currentGame.Fields, nil)
+
 	form := container.NewVBox(
 		widget.NewLabel("Checkpoint Name:"),
 		nameEntry,
 		widget.NewLabel(""),
 		widget.NewLabel("Notes (optional):"),
 		noteEntry,
+		fieldsForm,
 	)
 
 	d := dialog.NewCustomConfirm(
@@ -203,33 +426,125 @@ func (v *CheckpointsView) showCreateCheckpointDialog() {
 				return
 			}
 
-			// Show progress
-			progress := dialog.NewProgressInfinite(
-				"Creating Checkpoint",
-				fmt.Sprintf("Backing up saves for %s...", v.currentGame.Name),
+			v.createCheckpoint(v.currentGame, name, noteEntry.Text, models.CheckpointOptions{Fields: collectFields()})
+		},
+		v.mainUI.GetWindow(),
+	)
+
+	d.Resize(CheckpointDialogSize)
+	d.Show()
+}
+
+// createCheckpoint creates a checkpoint in the background, offering to
+// create it anyway if the saves haven't changed
+func (v *CheckpointsView) createCheckpoint(game *models.Game, name, note string, opts models.CheckpointOptions) {
+	// Show progress
+	progress, ctx := ShowProgress(
+		v.mainUI.GetWindow(),
+		"Creating Checkpoint",
+		fmt.Sprintf("Backing up saves for %s...", game.Name),
+	)
+	opts.Progress = progress.Update
+
+	// Create checkpoint
+	go func() {
+		cp, err := v.mainUI.GetService().CreateCheckpointContext(ctx, game.ID, name, note, opts)
+
+		// Close progress
+		progress.Hide()
+
+		if errors.Is(err, context.Canceled) {
+			ShowInfo(v.mainUI.GetWindow(), "Cancelled, no checkpoint was created")
+			return
+		}
+
+		var noChanges *models.NoChangesError
+		if errors.As(err, &noChanges) {
+			dialog.ShowConfirm(
+				"No Changes",
+				fmt.Sprintf("The saves haven't changed since checkpoint '%s'.\n\nCreate a checkpoint anyway?", noChanges.Checkpoint.Name),
+				func(confirmed bool) {
+					if confirmed {
+						opts.Force = true
+						v.createCheckpoint(game, name, note, opts)
+					}
+				},
 				v.mainUI.GetWindow(),
 			)
-			progress.Show()
+			return
+		}
+		if err != nil {
+			ShowError(v.mainUI.GetWindow(), "Failed to create checkpoint", err)
+			return
+		}
 
-			// Create checkpoint
-			go func() {
-				cp, err := v.mainUI.GetService().CreateCheckpoint(
-					v.currentGame.ID,
-					name,
-					noteEntry.Text,
-				)
+		ShowSuccess(v.mainUI.GetWindow(), fmt.Sprintf("Checkpoint '%s' created successfully!", cp.Name))
+		v.LoadCheckpoints(game)
+	}()
+}
 
-				// Close progress
-				progress.Hide()
+// showEditCheckpointDialog shows dialog to rename a checkpoint or change its note
+func (v *CheckpointsView) showEditCheckpointDialog(cp models.Checkpoint) {
+	nameEntry := widget.NewEntry()
+	nameEntry.SetText(cp.Name)
 
-				if err != nil {
-					ShowError(v.mainUI.GetWindow(), "Failed to create checkpoint", err)
-					return
+	noteEntry := widget.NewMultiLineEntry()
+	noteEntry.SetText(cp.Note)
+	noteEntry.SetMinRowsVisible(3)
+
+	tagsEntry := widget.NewEntry()
+	tagsEntry.SetText(strings.Join(cp.Tags, ", "))
+	tagsEntry.SetPlaceHolder("boss, pre-dlc, 100%")
+
+	var fieldDefs []models.FieldDef
+	if v.currentGame != nil {
+		fieldDefs = v.currentGame.Fields
+	}
+	fieldsForm, collectFields := newFieldInputs(fieldDefs, cp.Fields)
+
+	form := container.NewVBox(
+		widget.NewLabel("Checkpoint Name:"),
+		nameEntry,
+		widget.NewLabel(""),
+		widget.NewLabel("Notes (optional):"),
+		noteEntry,
+		widget.NewLabel(""),
+		widget.NewLabel("Tags (comma separated):"),
+		tagsEntry,
+		fieldsForm,
+	)
+
+	d := dialog.NewCustomConfirm(
+		"Edit Checkpoint",
+		"Save",
+		"Cancel",
+		form,
+		func(confirmed bool) {
+			if !confirmed {
+				return
+			}
+
+			name := nameEntry.Text
+			note := noteEntry.Text
+			tags := []string{}
+			for _, tag := range strings.Split(tagsEntry.Text, ",") {
+				if tag = strings.TrimSpace(tag); tag != "" {
+					tags = append(tags, tag)
 				}
+			}
+			update := models.CheckpointUpdate{
+				Name:   &name,
+				Note:   &note,
+				Tags:   &tags,
+				Fields: collectFields(),
+			}
 
-				ShowSuccess(v.mainUI.GetWindow(), fmt.Sprintf("Checkpoint '%s' created successfully!", cp.Name))
-				v.LoadCheckpoints(v.currentGame)
-			}()
+			if _, err := v.mainUI.GetService().UpdateCheckpoint(cp.ID, update); err != nil {
+				ShowError(v.mainUI.GetWindow(), "Failed to update checkpoint", err)
+				return
+			}
+
+			v.LoadCheckpoints(v.currentGame)
 		},
 		v.mainUI.GetWindow(),
 	)
@@ -238,6 +553,17 @@ func (v *CheckpointsView) showCreateCheckpointDialog() {
 	d.Show()
 }
 
+// toggleProtected pins or unpins a checkpoint
+func (v *CheckpointsView) toggleProtected(cp models.Checkpoint) {
+	protected := !cp.Protected
+	if _, err := v.mainUI.GetService().UpdateCheckpoint(cp.ID, models.CheckpointUpdate{Protected: &protected}); err != nil {
+		ShowError(v.mainUI.GetWindow(), "Failed to update checkpoint", err)
+		return
+	}
+
+	v.LoadCheckpoints(v.currentGame)
+}
+
 // confirmRestore shows confirmation dialog for restore
 func (v *CheckpointsView) confirmRestore(cp models.Checkpoint) {
 	message := fmt.Sprintf(
@@ -255,24 +581,29 @@ func (v *CheckpointsView) confirmRestore(cp models.Checkpoint) {
 			}
 
 			// Show progress
-			progress := dialog.NewProgressInfinite(
+			progress, ctx := ShowProgress(
+				v.mainUI.GetWindow(),
 				"Restoring Checkpoint",
 				"Extracting save files...",
-				v.mainUI.GetWindow(),
 			)
-			progress.Show()
 
 			// Restore
 			go func() {
-				err := v.mainUI.GetService().RestoreCheckpoint(cp.ID)
+				err := v.mainUI.GetService().RestoreCheckpointContext(ctx, cp.ID, progress.Update)
 				progress.Hide()
 
+				if errors.Is(err, context.Canceled) {
+					ShowInfo(v.mainUI.GetWindow(), "Cancelled, the saves were left as they were")
+					return
+				}
+
 				if err != nil {
 					ShowError(v.mainUI.GetWindow(), "Failed to restore checkpoint", err)
 					return
 				}
 
 				ShowSuccess(v.mainUI.GetWindow(), fmt.Sprintf("Checkpoint '%s' restored successfully!", cp.Name))
+				v.mainUI.timelineView.Refresh()
 			}()
 		},
 		v.mainUI.GetWindow(),
@@ -284,10 +615,13 @@ func (v *CheckpointsView) confirmRestore(cp models.Checkpoint) {
 // confirmDelete shows confirmation dialog for delete
 func (v *CheckpointsView) confirmDelete(cp models.Checkpoint) {
 	message := fmt.Sprintf(
-		"Delete checkpoint '%s'?\n\nCreated: %s\n\nThis action cannot be undone.",
+		"Delete checkpoint '%s'?\n\nCreated: %s\n\nIt will be moved to the trash, where it can be restored.",
 		cp.Name,
 		cp.CreatedAt.Local().Format("2006-01-02 15:04:05"),
 	)
+	if cp.Protected {
+		message += "\n\n" + IconPin + " This checkpoint is protected. Delete it anyway?"
+	}
 
 	d := dialog.NewConfirm(
 		"Confirm Delete",
@@ -297,14 +631,16 @@ func (v *CheckpointsView) confirmDelete(cp models.Checkpoint) {
 				return
 			}
 
-			err := v.mainUI.GetService().DeleteCheckpoint(cp.ID)
+			// Confirming the warning above is the user's explicit override
+			err := v.mainUI.GetService().DeleteCheckpoint(cp.ID, cp.Protected)
 			if err != nil {
 				ShowError(v.mainUI.GetWindow(), "Failed to delete checkpoint", err)
 				return
 			}
 
-			ShowSuccess(v.mainUI.GetWindow(), "Checkpoint deleted")
+			ShowSuccess(v.mainUI.GetWindow(), "Checkpoint moved to trash")
 			v.LoadCheckpoints(v.currentGame)
+			v.mainUI.trashView.Refresh()
 		},
 		v.mainUI.GetWindow(),
 	)
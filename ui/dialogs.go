@@ -1,12 +1,15 @@
 package ui
 
 import (
+	"context"
 	"fmt"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/container"
 	"fyne.io/fyne/v2/dialog"
 	"fyne.io/fyne/v2/widget"
+
+	"github.com/adrielfilipedesign/gamekeep/internal/models"
 )
 
 // ShowError displays an error dialog
@@ -22,7 +25,7 @@ func ShowError(window fyne.Window, message string, err error) {
 // ShowSuccess displays a success notification
 func ShowSuccess(window fyne.Window, message string) {
 	content := container.NewVBox(
-		widget.NewLabel(IconSuccess+" "+message),
+		widget.NewLabel(IconSuccess + " " + message),
 	)
 
 	d := dialog.NewCustom("Success", "OK", content, window)
@@ -33,7 +36,7 @@ func ShowSuccess(window fyne.Window, message string) {
 // ShowInfo displays an info notification
 func ShowInfo(window fyne.Window, message string) {
 	content := container.NewVBox(
-		widget.NewLabel(IconInfo+" "+message),
+		widget.NewLabel(IconInfo + " " + message),
 	)
 
 	d := dialog.NewCustom("Information", "OK", content, window)
@@ -44,7 +47,7 @@ func ShowInfo(window fyne.Window, message string) {
 // ShowWarning displays a warning notification
 func ShowWarning(window fyne.Window, message string) {
 	content := container.NewVBox(
-		widget.NewLabel(IconWarning+" "+message),
+		widget.NewLabel(IconWarning + " " + message),
 	)
 
 	d := dialog.NewCustom("Warning", "OK", content, window)
@@ -82,3 +85,71 @@ func (d *CustomDialog) Hide() {
 func (d *CustomDialog) Resize(size fyne.Size) {
 	d.dialog.Resize(size)
 }
+
+// ProgressDialog shows how far a long operation has got, with a Cancel
+// button that cancels the operation's context
+type ProgressDialog struct {
+	dialog    *dialog.CustomDialog
+	bar       *widget.ProgressBar
+	detail    *widget.Label
+	cancelBtn *widget.Button
+}
+
+// ShowProgress shows a progress dialog and returns it with the context
+// to run the operation under
+func ShowProgress(window fyne.Window, title, message string) (*ProgressDialog, context.Context) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	p := &ProgressDialog{
+		bar:    widget.NewProgressBar(),
+		detail: widget.NewLabel(""),
+	}
+	p.cancelBtn = widget.NewButton("Cancel", func() {
+		p.cancelBtn.Disable()
+		p.detail.SetText("Cancelling...")
+		cancel()
+	})
+
+	content := container.NewVBox(
+		widget.NewLabel(message),
+		p.bar,
+		p.detail,
+		container.NewHBox(p.cancelBtn),
+	)
+
+	p.dialog = dialog.NewCustomWithoutButtons(title, content, window)
+	p.dialog.Resize(SmallDialogSize)
+	p.dialog.SetOnClosed(cancel)
+	p.dialog.Show()
+
+	return p, ctx
+}
+
+// Update shows a progress report; it can be called from any goroutine
+func (p *ProgressDialog) Update(progress models.Progress) {
+	p.bar.SetValue(progress.Fraction())
+	if p.cancelBtn.Disabled() {
+		return
+	}
+	p.detail.SetText(fmt.Sprintf("%d of %d files, %s of %s",
+		progress.FilesDone, progress.FilesTotal, FormatBytes(progress.BytesDone), FormatBytes(progress.BytesTotal)))
+}
+
+// Hide closes the dialog
+func (p *ProgressDialog) Hide() {
+	p.dialog.Hide()
+}
+
+// FormatBytes formats a byte count for display
+func FormatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
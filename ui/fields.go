@@ -0,0 +1,132 @@
+package ui
+
+import (
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/adrielfilipedesign/gamekeep/internal/models"
+)
+
+// newFieldInputs builds a form for a game's custom fields, pre-filled with
+// values. The returned function collects the entered values; fields left
+// empty map to "".
+func newFieldInputs(defs []models.FieldDef, values map[string]string) (fyne.CanvasObject, func() map[string]string) {
+	form := container.NewVBox()
+	collectors := make(map[string]func() string, len(defs))
+
+	for _, def := range defs {
+		form.Add(widget.NewLabel(def.Key + ":"))
+
+		if def.Type == models.FieldBool {
+			choice := widget.NewSelect([]string{"true", "false"}, nil)
+			choice.PlaceHolder = "(not set)"
+			if value, ok := values[def.Key]; ok {
+				choice.SetSelected(value)
+			}
+			form.Add(choice)
+			collectors[def.Key] = func() string { return choice.Selected }
+			continue
+		}
+
+		entry := widget.NewEntry()
+		switch def.Type {
+		case models.FieldNumber:
+			entry.SetPlaceHolder("Number")
+		case models.FieldDate:
+			entry.SetPlaceHolder("YYYY-MM-DD")
+		}
+		entry.SetText(values[def.Key])
+		form.Add(entry)
+		collectors[def.Key] = func() string { return entry.Text }
+	}
+
+	collect := func() map[string]string {
+		collected := make(map[string]string, len(collectors))
+		for key, get := range collectors {
+			collected[key] = get()
+		}
+		return collected
+	}
+
+	return form, collect
+}
+
+// Inspector choices besides the registered inspectors' names
+const (
+	inspectorAuto = "Automatic"
+	inspectorOff  = "Off"
+)
+
+// inspectorOption maps a game's inspector setting to a select option
+func inspectorOption(name string) string {
+	switch name {
+	case "":
+		return inspectorAuto
+	case models.InspectorNone:
+		return inspectorOff
+	}
+	return name
+}
+
+// inspectorName maps a select option back to an inspector setting
+func inspectorName(option string) string {
+	switch option {
+	case inspectorAuto:
+		return ""
+	case inspectorOff:
+		return models.InspectorNone
+	}
+	return option
+}
+
+// compressionDefault is the compression choice for the default codec
+const compressionDefault = "Default"
+
+// compressionSetting maps a codec choice and level to a game's compression
+// setting; the zero Compression stands for the default
+func compressionSetting(option, level string) (models.Compression, error) {
+	if option == compressionDefault || option == "" {
+		return models.Compression{}, nil
+	}
+	if strings.TrimSpace(level) == "" {
+		return models.ParseCompression(option)
+	}
+	return models.ParseCompression(option + ":" + strings.TrimSpace(level))
+}
+
+// formatFields formats checkpoint field values for display
+func formatFields(defs []models.FieldDef, fields map[string]string) string {
+	parts := make([]string, 0, len(fields))
+	for _, key := range models.SortedFieldKeys(defs, fields) {
+		parts = append(parts, key+": "+fields[key])
+	}
+	return strings.Join(parts, " · ")
+}
+
+// formatFieldDefs formats field definitions one per line, for editing
+func formatFieldDefs(defs []models.FieldDef) string {
+	lines := make([]string, len(defs))
+	for i, def := range defs {
+		lines[i] = def.String()
+	}
+	return strings.Join(lines, "\n")
+}
+
+// parseFieldDefs parses field definitions entered one per line
+func parseFieldDefs(text string) ([]models.FieldDef, error) {
+	defs := []models.FieldDef{}
+	for _, line := range strings.Split(text, "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		def, err := models.ParseFieldDef(line)
+		if err != nil {
+			return nil, err
+		}
+		defs = append(defs, def)
+	}
+	return defs, nil
+}
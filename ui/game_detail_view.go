@@ -0,0 +1,135 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/adrielfilipedesign/gamekeep/internal/models"
+)
+
+// coverSize is the size the detail panel shows cover art at
+var coverSize = fyne.NewSize(96, 128)
+
+// GameDetailView shows the selected game's cover art, details and stats
+type GameDetailView struct {
+	mainUI        *MainUI
+	container     *fyne.Container
+	cover         *canvas.Image
+	nameLabel     *widget.Label
+	platformLabel *widget.Label
+	datesLabel    *widget.Label
+	statsLabel    *widget.Label
+	notesLabel    *widget.Label
+}
+
+// NewGameDetailView creates a new game detail view
+func NewGameDetailView(mainUI *MainUI) *GameDetailView {
+	return &GameDetailView{
+		mainUI: mainUI,
+	}
+}
+
+// Build creates the game detail view UI
+func (v *GameDetailView) Build() fyne.CanvasObject {
+	v.cover = &canvas.Image{FillMode: canvas.ImageFillContain}
+	v.cover.SetMinSize(coverSize)
+
+	v.nameLabel = widget.NewLabelWithStyle("", fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
+	v.platformLabel = widget.NewLabel("")
+	v.datesLabel = widget.NewLabel("")
+	v.statsLabel = widget.NewLabel("")
+	v.notesLabel = widget.NewLabel("")
+	v.notesLabel.Wrapping = fyne.TextWrapWord
+	v.notesLabel.TextStyle.Italic = true
+
+	info := container.NewVBox(
+		v.nameLabel,
+		v.platformLabel,
+		v.datesLabel,
+		v.statsLabel,
+		v.notesLabel,
+	)
+
+	v.container = container.NewBorder(nil, nil, v.cover, nil, info)
+	v.container.Hide()
+
+	return v.container
+}
+
+// Load shows a game's details and current checkpoint stats
+func (v *GameDetailView) Load(game *models.Game) {
+	service := v.mainUI.GetService()
+
+	// Re-read the game so edits made elsewhere show up
+	if current, err := service.GetGame(game.ID); err == nil {
+		game = current
+	}
+
+	if path := service.CoverPath(game); path != "" {
+		v.cover.File = path
+		v.cover.Show()
+	} else {
+		v.cover.File = ""
+		v.cover.Hide()
+	}
+	v.cover.Refresh()
+
+	v.nameLabel.SetText(game.Name)
+
+	platform := game.Platform
+	if platform == "" {
+		platform = "Unknown platform"
+	}
+	if game.Executable != "" {
+		platform += " · " + game.Executable
+	}
+	v.platformLabel.SetText(platform)
+
+	played := "never"
+	if game.LastPlayedAt != nil {
+		played = formatDate(*game.LastPlayedAt)
+	}
+	dates := fmt.Sprintf("Added %s · Last played %s", formatDate(game.CreatedAt), played)
+	if game.PlaytimeSeconds > 0 {
+		dates += " · " + models.FormatPlaytime(game.PlaytimeSeconds) + " played"
+	}
+	v.datesLabel.SetText(dates)
+
+	if stats, err := service.GameStats(game.ID); err == nil {
+		last := "none yet"
+		if stats.CheckpointCount > 0 {
+			last = formatDate(stats.LastCheckpointAt)
+		}
+		v.statsLabel.SetText(fmt.Sprintf("%s %d checkpoints · %s · Last %s",
+			IconCheckpoint, stats.CheckpointCount, FormatBytes(stats.TotalSize), last))
+	} else {
+		v.statsLabel.SetText("")
+	}
+
+	v.notesLabel.SetText(game.Notes)
+	if game.Notes != "" {
+		v.notesLabel.Show()
+	} else {
+		v.notesLabel.Hide()
+	}
+
+	v.container.Show()
+}
+
+// Clear hides the panel when no game is selected
+func (v *GameDetailView) Clear() {
+	v.container.Hide()
+}
+
+// formatDate formats a timestamp for the detail panel, or "unknown" if it is zero
+func formatDate(t time.Time) string {
+	if t.IsZero() {
+		return "unknown"
+	}
+	return t.Local().Format("2006-01-02 15:04")
+}
@@ -2,10 +2,13 @@ package ui
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/container"
 	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
 	"fyne.io/fyne/v2/widget"
 
 	"github.com/adrielfilipedesign/gamekeep/internal/models"
@@ -46,7 +49,11 @@ func (v *GamesView) Build() fyne.CanvasObject {
 				return
 			}
 			game := v.games[id]
-			obj.(*fyne.Container).Objects[0].(*widget.Label).SetText(fmt.Sprintf("%s %s", IconGame, game.Name))
+			icon := IconGame
+			if v.mainUI.IsPlaying(game.ID) {
+				icon = IconPlaying
+			}
+			obj.(*fyne.Container).Objects[0].(*widget.Label).SetText(fmt.Sprintf("%s %s", icon, game.Name))
 		},
 	)
 
@@ -61,10 +68,30 @@ func (v *GamesView) Build() fyne.CanvasObject {
 		v.showAddGameDialog()
 	})
 
+	// Edit game button
+	editBtn := widget.NewButton(IconEdit+" Edit", func() {
+		game := v.mainUI.currentGame
+		if game == nil {
+			ShowInfo(v.mainUI.GetWindow(), "Please select a game first")
+			return
+		}
+		v.showEditGameDialog(game)
+	})
+
+	// Remove game button
+	removeBtn := widget.NewButton(IconDelete+" Remove", func() {
+		game := v.mainUI.currentGame
+		if game == nil {
+			ShowInfo(v.mainUI.GetWindow(), "Please select a game first")
+			return
+		}
+		v.showRemoveGameDialog(game)
+	})
+
 	// Container
 	v.container = container.NewBorder(
 		nil,
-		addBtn,
+		container.NewGridWithColumns(3, addBtn, editBtn, removeBtn),
 		nil,
 		nil,
 		v.list,
@@ -151,3 +178,320 @@ func (v *GamesView) showAddGameDialog() {
 	d.Resize(DialogSize)
 	d.Show()
 }
+
+// showEditGameDialog shows the dialog to change a game's name, save path and details
+func (v *GamesView) showEditGameDialog(game *models.Game) {
+	nameEntry := widget.NewEntry()
+	nameEntry.SetText(game.Name)
+
+	pathEntry := widget.NewEntry()
+	pathEntry.SetText(game.SavePath)
+
+	fieldsEntry := widget.NewMultiLineEntry()
+	fieldsEntry.SetText(formatFieldDefs(game.Fields))
+	fieldsEntry.SetPlaceHolder("level:number\nchapter:string")
+	fieldsEntry.SetMinRowsVisible(3)
+
+	inspectorOptions := append([]string{inspectorAuto, inspectorOff}, v.mainUI.GetService().Inspectors().Names()...)
+	inspectorSelect := widget.NewSelect(inspectorOptions, nil)
+	inspectorSelect.SetSelected(inspectorOption(game.Inspector))
+
+	compressionOptions := []string{compressionDefault}
+	for _, codec := range models.AllCodecs {
+		compressionOptions = append(compressionOptions, string(codec))
+	}
+	compressionSelect := widget.NewSelect(compressionOptions, nil)
+	compressionSelect.SetSelected(compressionDefault)
+	levelEntry := widget.NewEntry()
+	levelEntry.SetPlaceHolder("Default level")
+	if game.Compression != nil {
+		compressionSelect.SetSelected(string(game.Compression.Codec))
+		if game.Compression.Level != 0 {
+			levelEntry.SetText(strconv.Itoa(game.Compression.Level))
+		}
+	}
+
+	platformEntry := widget.NewEntry()
+	platformEntry.SetText(game.Platform)
+	platformEntry.SetPlaceHolder("Steam, GOG, Emulator...")
+
+	exeEntry := widget.NewEntry()
+	exeEntry.SetText(game.Executable)
+
+	launchEntry := widget.NewEntry()
+	launchEntry.SetText(game.LaunchCommand)
+
+	processesEntry := widget.NewEntry()
+	processesEntry.SetText(strings.Join(game.ProcessNames, ", "))
+	processesEntry.SetPlaceHolder("Game.exe, GameLauncher")
+
+	notesEntry := widget.NewMultiLineEntry()
+	notesEntry.SetText(game.Notes)
+	notesEntry.SetMinRowsVisible(3)
+
+	// Cover changes apply on save; nil leaves the cover as it is
+	var coverChoice *string
+	coverLabel := widget.NewLabel("No cover")
+	if game.CoverImage != "" {
+		coverLabel.SetText("Cover set")
+	}
+	chooseCoverBtn := widget.NewButton(IconFolder+" Choose...", func() {
+		open := dialog.NewFileOpen(func(file fyne.URIReadCloser, err error) {
+			if err != nil || file == nil {
+				return
+			}
+			file.Close()
+			path := file.URI().Path()
+			coverChoice = &path
+			coverLabel.SetText(file.URI().Name())
+		}, v.mainUI.GetWindow())
+		open.SetFilter(storage.NewExtensionFileFilter([]string{".png", ".jpg", ".jpeg", ".gif", ".webp"}))
+		open.Show()
+	})
+	removeCoverBtn := widget.NewButton("Remove", func() {
+		none := ""
+		coverChoice = &none
+		coverLabel.SetText("No cover")
+	})
+
+	browseBtn := widget.NewButton(IconFolder+" Browse", func() {
+		dialog.ShowFolderOpen(func(dir fyne.ListableURI, err error) {
+			if err != nil || dir == nil {
+				return
+			}
+			pathEntry.SetText(dir.Path())
+		}, v.mainUI.GetWindow())
+	})
+
+	form := container.NewVBox(
+		widget.NewLabel("Game Name:"),
+		nameEntry,
+		widget.NewLabel(""),
+		widget.NewLabel("Save Directory:"),
+		container.NewBorder(nil, nil, nil, browseBtn, pathEntry),
+		widget.NewLabel("Existing checkpoints stay attached to this game."),
+		widget.NewLabel(""),
+		widget.NewLabel("Platform:"),
+		platformEntry,
+		widget.NewLabel("Executable:"),
+		exeEntry,
+		widget.NewLabel("Launch Command:"),
+		launchEntry,
+		widget.NewLabel("Other process names (comma-separated, for the game watcher):"),
+		processesEntry,
+		widget.NewLabel("Cover Image:"),
+		container.NewBorder(nil, nil, nil, container.NewHBox(chooseCoverBtn, removeCoverBtn), coverLabel),
+		widget.NewLabel("Notes:"),
+		notesEntry,
+		widget.NewLabel(""),
+		widget.NewLabel("Checkpoint fields (key:type per line; string, number, bool or date):"),
+		fieldsEntry,
+		widget.NewLabel("Read checkpoint details from save files:"),
+		inspectorSelect,
+		widget.NewLabel("Compression for new checkpoints (deflate 1-9, zstd 1-22):"),
+		container.NewGridWithColumns(2, compressionSelect, levelEntry),
+	)
+
+	d := dialog.NewCustomConfirm(
+		"Edit Game",
+		"Save",
+		"Cancel",
+		container.NewVScroll(form),
+		func(confirmed bool) {
+			if !confirmed {
+				return
+			}
+
+			// Only send the fields that changed
+			var update models.GameUpdate
+			if name := nameEntry.Text; name != game.Name {
+				update.Name = &name
+			}
+			if path := pathEntry.Text; path != game.SavePath {
+				update.SavePath = &path
+			}
+			if fieldsEntry.Text != formatFieldDefs(game.Fields) {
+				defs, err := parseFieldDefs(fieldsEntry.Text)
+				if err != nil {
+					ShowError(v.mainUI.GetWindow(), "Invalid field definition", err)
+					return
+				}
+				update.Fields = &defs
+			}
+			if inspectorSelect.Selected != inspectorOption(game.Inspector) {
+				inspector := inspectorName(inspectorSelect.Selected)
+				update.Inspector = &inspector
+			}
+			compression, err := compressionSetting(compressionSelect.Selected, levelEntry.Text)
+			if err != nil {
+				ShowError(v.mainUI.GetWindow(), "Invalid compression", err)
+				return
+			}
+			current := models.Compression{}
+			if game.Compression != nil {
+				current = *game.Compression
+			}
+			if compression != current {
+				update.Compression = &compression
+			}
+			if platform := platformEntry.Text; platform != game.Platform {
+				update.Platform = &platform
+			}
+			if exe := exeEntry.Text; exe != game.Executable {
+				update.Executable = &exe
+			}
+			if launch := launchEntry.Text; launch != game.LaunchCommand {
+				update.LaunchCommand = &launch
+			}
+			if processesEntry.Text != strings.Join(game.ProcessNames, ", ") {
+				names := strings.Split(processesEntry.Text, ",")
+				update.ProcessNames = &names
+			}
+			if notes := notesEntry.Text; notes != game.Notes {
+				update.Notes = &notes
+			}
+			update.CoverImage = coverChoice
+			if update == (models.GameUpdate{}) {
+				return
+			}
+
+			updated, err := v.mainUI.GetService().UpdateGame(game.ID, update)
+			if err != nil {
+				ShowError(v.mainUI.GetWindow(), "Failed to update game", err)
+				return
+			}
+
+			ShowSuccess(v.mainUI.GetWindow(), fmt.Sprintf("Game '%s' updated successfully!", updated.Name))
+			v.Refresh()
+			v.mainUI.SelectGame(updated)
+		},
+		v.mainUI.GetWindow(),
+	)
+
+	d.Resize(DialogSize)
+	d.Show()
+}
+
+// Labels for the remove game dialog's checkpoint options
+const (
+	removeKeepLabel   = "Keep checkpoints (archive the game)"
+	removeDeleteLabel = "Delete checkpoints"
+	removeExportLabel = "Export checkpoints to a file, then delete them"
+)
+
+// showRemoveGameDialog asks what to do with a game's checkpoints and removes it
+func (v *GamesView) showRemoveGameDialog(game *models.Game) {
+	modes := map[string]models.RemoveMode{
+		removeKeepLabel:   models.RemoveKeep,
+		removeDeleteLabel: models.RemoveDelete,
+		removeExportLabel: models.RemoveExport,
+	}
+
+	summaryLabel := widget.NewLabel("")
+	summaryLabel.Wrapping = fyne.TextWrapWord
+
+	permanentCheck := widget.NewCheck("Skip the trash and free the space now", nil)
+	forceCheck := widget.NewCheck("Also delete protected checkpoints", nil)
+
+	modeRadio := widget.NewRadioGroup([]string{removeKeepLabel, removeDeleteLabel, removeExportLabel}, nil)
+
+	// Keep the summary in step with the chosen options
+	options := func() models.RemoveGameOptions {
+		return models.RemoveGameOptions{
+			Mode:       modes[modeRadio.Selected],
+			ExportPath: "-", // Chosen after confirming
+			Permanent:  permanentCheck.Checked,
+			Force:      forceCheck.Checked,
+		}
+	}
+	updateSummary := func() {
+		opts := options()
+		if opts.Mode == models.RemoveKeep {
+			permanentCheck.Disable()
+			forceCheck.Disable()
+		} else {
+			permanentCheck.Enable()
+			forceCheck.Enable()
+		}
+
+		summary, err := v.mainUI.GetService().PlanRemoveGame(game.ID, opts)
+		if err != nil {
+			summaryLabel.SetText(err.Error())
+			return
+		}
+
+		text := fmt.Sprintf("%d checkpoint(s) using %s.", summary.CheckpointCount, FormatBytes(summary.VaultBytes))
+		switch {
+		case opts.Mode == models.RemoveKeep:
+			text += "\nThey stay in the vault and the game is hidden from the list."
+		case opts.Permanent:
+			text += fmt.Sprintf("\n%s will be freed.", FormatBytes(summary.FreedBytes))
+		default:
+			text += "\nThey move to the trash; space is freed when it is emptied."
+		}
+		if summary.ProtectedCount > 0 && opts.Mode != models.RemoveKeep && !opts.Force {
+			text += fmt.Sprintf("\n%s %d protected checkpoint(s) block removal.", IconPin, summary.ProtectedCount)
+		}
+		summaryLabel.SetText(text)
+	}
+	modeRadio.OnChanged = func(string) { updateSummary() }
+	permanentCheck.OnChanged = func(bool) { updateSummary() }
+	forceCheck.OnChanged = func(bool) { updateSummary() }
+	modeRadio.SetSelected(removeKeepLabel)
+
+	form := container.NewVBox(
+		widget.NewLabel(fmt.Sprintf("Remove '%s'?", game.Name)),
+		widget.NewLabel(""),
+		widget.NewLabel("Checkpoints:"),
+		modeRadio,
+		permanentCheck,
+		forceCheck,
+		widget.NewLabel(""),
+		summaryLabel,
+	)
+
+	d := dialog.NewCustomConfirm(
+		"Remove Game",
+		"Remove",
+		"Cancel",
+		form,
+		func(confirmed bool) {
+			if !confirmed {
+				return
+			}
+
+			opts := options()
+			if opts.Mode != models.RemoveExport {
+				v.removeGame(game, opts)
+				return
+			}
+
+			dialog.ShowFileSave(func(writer fyne.URIWriteCloser, err error) {
+				if err != nil || writer == nil {
+					return
+				}
+				// The service writes the bundle itself
+				writer.Close()
+				opts.ExportPath = writer.URI().Path()
+				v.removeGame(game, opts)
+			}, v.mainUI.GetWindow())
+		},
+		v.mainUI.GetWindow(),
+	)
+
+	d.Resize(DialogSize)
+	d.Show()
+}
+
+// removeGame removes a game and resets the selection
+func (v *GamesView) removeGame(game *models.Game, opts models.RemoveGameOptions) {
+	summary, err := v.mainUI.GetService().RemoveGame(game.ID, opts)
+	if err != nil {
+		ShowError(v.mainUI.GetWindow(), "Failed to remove game", err)
+		return
+	}
+
+	v.mainUI.ClearGame()
+	v.mainUI.RefreshAll()
+	ShowSuccess(v.mainUI.GetWindow(), fmt.Sprintf("Game '%s' removed", summary.Game.Name))
+}
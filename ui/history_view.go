@@ -0,0 +1,152 @@
+package ui
+
+import (
+	"fmt"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/adrielfilipedesign/gamekeep/internal/models"
+)
+
+// historyLimit caps how many journal entries the history tab shows
+const historyLimit = 500
+
+// allOperations is the label for the unfiltered operation choice
+const allOperations = "All operations"
+
+// HistoryView handles the operations journal display
+type HistoryView struct {
+	mainUI     *MainUI
+	list       *widget.List
+	entries    []models.JournalEntry
+	opSelect   *widget.Select
+	failedOnly *widget.Check
+}
+
+// NewHistoryView creates a new history view
+func NewHistoryView(mainUI *MainUI) *HistoryView {
+	return &HistoryView{
+		mainUI:  mainUI,
+		entries: []models.JournalEntry{},
+	}
+}
+
+// Build creates the history view UI
+func (v *HistoryView) Build() fyne.CanvasObject {
+	// Create list
+	v.list = widget.NewList(
+		func() int {
+			return len(v.entries)
+		},
+		func() fyne.CanvasObject {
+			return v.createEntryCard()
+		},
+		func(id widget.ListItemID, obj fyne.CanvasObject) {
+			if id >= len(v.entries) {
+				return
+			}
+			v.updateEntryCard(obj, v.entries[id])
+		},
+	)
+
+	// Filters
+	options := []string{allOperations}
+	for _, op := range models.AllOperations {
+		options = append(options, string(op))
+	}
+
+	v.opSelect = widget.NewSelect(options, func(string) {
+		v.Refresh()
+	})
+	v.opSelect.SetSelected(allOperations)
+
+	v.failedOnly = widget.NewCheck("Failures only", func(bool) {
+		v.Refresh()
+	})
+
+	filters := container.NewHBox(
+		widget.NewLabel("Show:"),
+		v.opSelect,
+		v.failedOnly,
+	)
+
+	return container.NewBorder(
+		filters,
+		nil,
+		nil,
+		nil,
+		v.list,
+	)
+}
+
+// createEntryCard creates a card template for a journal entry
+func (v *HistoryView) createEntryCard() fyne.CanvasObject {
+	summaryLabel := widget.NewLabelWithStyle("", fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
+	detailsLabel := widget.NewLabel("")
+	errorLabel := widget.NewLabel("")
+	errorLabel.Wrapping = fyne.TextWrapWord
+
+	return container.NewPadded(container.NewVBox(
+		summaryLabel,
+		detailsLabel,
+		errorLabel,
+	))
+}
+
+// updateEntryCard updates a card with journal entry data
+func (v *HistoryView) updateEntryCard(obj fyne.CanvasObject, entry models.JournalEntry) {
+	info := obj.(*fyne.Container).Objects[0].(*fyne.Container)
+
+	summaryLabel := info.Objects[0].(*widget.Label)
+	detailsLabel := info.Objects[1].(*widget.Label)
+	errorLabel := info.Objects[2].(*widget.Label)
+
+	icon := IconSuccess
+	if entry.Outcome == models.OutcomeFailure {
+		icon = IconWarning
+	}
+
+	summary := fmt.Sprintf("%s %s", icon, entry.Operation)
+	if entry.Detail != "" {
+		summary += " — " + entry.Detail
+	}
+	summaryLabel.SetText(summary)
+
+	details := entry.Timestamp.Local().Format("2006-01-02 15:04:05")
+	if entry.GameID != "" {
+		details += " • Game: " + entry.GameID
+	}
+	details += fmt.Sprintf(" • %s@%s", entry.Origin, entry.Hostname)
+	detailsLabel.SetText(details)
+
+	if entry.Error != "" {
+		errorLabel.SetText("Error: " + entry.Error)
+		errorLabel.Show()
+	} else {
+		errorLabel.Hide()
+	}
+}
+
+// Refresh reloads the journal with the current filters
+func (v *HistoryView) Refresh() {
+	filter := models.JournalFilter{Limit: historyLimit}
+	if v.opSelect != nil && v.opSelect.Selected != allOperations {
+		filter.Operation = models.Operation(v.opSelect.Selected)
+	}
+	if v.failedOnly != nil {
+		filter.FailedOnly = v.failedOnly.Checked
+	}
+
+	entries, err := v.mainUI.GetService().ListJournal(filter)
+	if err != nil {
+		ShowError(v.mainUI.GetWindow(), "Failed to load history", err)
+		return
+	}
+	v.entries = entries
+
+	if v.list != nil {
+		v.list.Refresh()
+	}
+}
@@ -1,6 +1,10 @@
 package ui
 
 import (
+	"fmt"
+	"strings"
+	"time"
+
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/container"
 	"fyne.io/fyne/v2/layout"
@@ -16,9 +20,29 @@ type MainUI struct {
 	service         *core.Service
 	gamesView       *GamesView
 	checkpointsView *CheckpointsView
+	trashView       *TrashView
+	historyView     *HistoryView
+	timelineView    *TimelineView
+	gameDetailView  *GameDetailView
+	settingsView    *SettingsView
 	currentGame     *models.Game
+
+	// The game watcher runs in the background while the window is open
+	watcher      *core.Watcher
+	stopWatcher  chan struct{}
+	playingLabel *widget.Label
+
+	// Schedules run in the background while the window is open
+	stopScheduler chan struct{}
+
+	// Changes made by other processes are picked up from the journal
+	stopFollowing chan struct{}
 }
 
+// journalFollowInterval is how often the GUI looks for changes made by
+// other processes, such as the CLI
+const journalFollowInterval = 2 * time.Second
+
 // NewMainUI creates a new main UI controller
 func NewMainUI(window fyne.Window, service *core.Service) *MainUI {
 	ui := &MainUI{
@@ -28,6 +52,15 @@ func NewMainUI(window fyne.Window, service *core.Service) *MainUI {
 
 	ui.gamesView = NewGamesView(ui)
 	ui.checkpointsView = NewCheckpointsView(ui)
+	ui.trashView = NewTrashView(ui)
+	ui.historyView = NewHistoryView(ui)
+	ui.timelineView = NewTimelineView(ui)
+	ui.gameDetailView = NewGameDetailView(ui)
+	ui.settingsView = NewSettingsView(ui)
+	ui.playingLabel = widget.NewLabel("")
+
+	// Keep the views current whatever changes the checkpoints
+	service.Subscribe(ui.onServiceEvent)
 
 	return ui
 }
@@ -44,13 +77,38 @@ func (m *MainUI) Build() fyne.CanvasObject {
 	split := container.NewHSplit(leftPanel, rightPanel)
 	split.Offset = 0.35 // 35% for games list, 65% for checkpoints
 
+	// Tabs
+	timelineTab := container.NewTabItem(IconTimeline+" Timeline", m.timelineView.Build())
+	trashTab := container.NewTabItem(IconDelete+" Trash", m.trashView.Build())
+	historyTab := container.NewTabItem(IconHistory+" History", m.historyView.Build())
+	settingsTab := container.NewTabItem(IconSettings+" Settings", m.settingsView.Build())
+	tabs := container.NewAppTabs(
+		container.NewTabItem(IconGame+" Games", split),
+		timelineTab,
+		trashTab,
+		historyTab,
+		settingsTab,
+	)
+	tabs.OnSelected = func(tab *container.TabItem) {
+		switch tab {
+		case timelineTab:
+			m.timelineView.Refresh()
+		case trashTab:
+			m.trashView.Refresh()
+		case historyTab:
+			m.historyView.Refresh()
+		case settingsTab:
+			m.settingsView.Refresh()
+		}
+	}
+
 	// Main container
 	content := container.NewBorder(
 		header, // top
 		nil,    // bottom
 		nil,    // left
 		nil,    // right
-		split,  // center
+		tabs,   // center
 	)
 
 	return content
@@ -80,6 +138,7 @@ func (m *MainUI) buildHeader() fyne.CanvasObject {
 
 	buttons := container.NewHBox(
 		layout.NewSpacer(),
+		m.playingLabel,
 		refreshBtn,
 		aboutBtn,
 	)
@@ -106,12 +165,12 @@ func (m *MainUI) buildLeftPanel() fyne.CanvasObject {
 	)
 }
 
-// buildRightPanel creates the checkpoints panel
+// buildRightPanel creates the game details and checkpoints panel
 func (m *MainUI) buildRightPanel() fyne.CanvasObject {
 	title := widget.NewLabelWithStyle(IconCheckpoint+" Checkpoints", fyne.TextAlignCenter, fyne.TextStyle{Bold: true})
 
 	return container.NewBorder(
-		title,
+		container.NewVBox(m.gameDetailView.Build(), title),
 		nil,
 		nil,
 		nil,
@@ -125,14 +184,175 @@ func (m *MainUI) SelectGame(game *models.Game) {
 	m.checkpointsView.LoadCheckpoints(game)
 }
 
+// ClearGame deselects the current game, e.g. after it was removed
+func (m *MainUI) ClearGame() {
+	m.currentGame = nil
+	m.gameDetailView.Clear()
+	m.checkpointsView.Clear()
+}
+
 // RefreshAll refreshes all views
 func (m *MainUI) RefreshAll() {
 	m.gamesView.Refresh()
+	m.trashView.Refresh()
+	m.historyView.Refresh()
+	m.timelineView.Refresh()
 	if m.currentGame != nil {
 		m.checkpointsView.LoadCheckpoints(m.currentGame)
 	}
 }
 
+// StartWatcher starts watching for running games, if the WatchGames
+// setting is on
+func (m *MainUI) StartWatcher() {
+	settings, err := m.service.GetSettings()
+	if err != nil || !settings.WatchGames || m.watcher != nil {
+		return
+	}
+
+	m.watcher = m.service.NewWatcher(models.WatchOptions{OnEvent: m.onWatchEvent})
+	m.stopWatcher = make(chan struct{})
+	watcher, stop := m.watcher, m.stopWatcher
+	go func() {
+		if err := watcher.Run(stop); err != nil {
+			m.playingLabel.SetText(IconWarning + " Game watcher unavailable")
+		}
+	}()
+}
+
+// StopWatcher stops watching for running games
+func (m *MainUI) StopWatcher() {
+	if m.watcher == nil {
+		return
+	}
+	close(m.stopWatcher)
+	m.watcher = nil
+	m.playingLabel.SetText("")
+}
+
+// StartScheduler starts running checkpoint schedules as they come due
+func (m *MainUI) StartScheduler() {
+	if m.stopScheduler != nil {
+		return
+	}
+
+	scheduler := m.service.NewScheduler(models.SchedulerOptions{OnRun: m.onScheduleRun})
+	m.stopScheduler = make(chan struct{})
+	go scheduler.Run(m.stopScheduler)
+}
+
+// StopScheduler stops running checkpoint schedules
+func (m *MainUI) StopScheduler() {
+	if m.stopScheduler == nil {
+		return
+	}
+	close(m.stopScheduler)
+	m.stopScheduler = nil
+}
+
+// onScheduleRun shows a schedule's new state after it ran; its checkpoint
+// arrives as a service event
+func (m *MainUI) onScheduleRun(run models.ScheduleRun) {
+	if run.CheckpointID == "" && run.Err == nil {
+		return
+	}
+
+	m.settingsView.Refresh()
+}
+
+// StartFollowingChanges starts picking up changes that other processes,
+// such as the CLI, make while the window is open
+func (m *MainUI) StartFollowingChanges() {
+	if m.stopFollowing != nil {
+		return
+	}
+
+	m.stopFollowing = make(chan struct{})
+	go m.service.FollowJournal(m.stopFollowing, journalFollowInterval)
+}
+
+// StopFollowingChanges stops picking up changes made by other processes
+func (m *MainUI) StopFollowingChanges() {
+	if m.stopFollowing == nil {
+		return
+	}
+	close(m.stopFollowing)
+	m.stopFollowing = nil
+}
+
+// onServiceEvent refreshes the views a service event affects
+func (m *MainUI) onServiceEvent(event models.Event) {
+	switch event.Type {
+	case models.EventGameAdded:
+		m.gamesView.Refresh()
+
+	case models.EventCheckpointCreated, models.EventCheckpointRestored, models.EventCheckpointDeleted:
+		m.gamesView.Refresh()
+		m.timelineView.Refresh()
+		if event.Type == models.EventCheckpointDeleted {
+			m.trashView.Refresh()
+		}
+		if m.currentGame != nil && m.currentGame.ID == event.GameID {
+			m.checkpointsView.LoadCheckpoints(m.currentGame)
+		}
+
+	case models.EventOperation:
+		m.historyView.Refresh()
+		// Other processes' changes without events of their own, such as
+		// edits, trash and game removal
+		if event.External {
+			m.gamesView.Refresh()
+			m.trashView.Refresh()
+		}
+
+	case models.EventVerifyFailed:
+		fyne.CurrentApp().SendNotification(fyne.NewNotification(
+			"Checkpoint failed verification",
+			fmt.Sprintf("%s: %v", event.CheckpointID, event.Err),
+		))
+
+	case models.EventHookFailed, models.EventLinkSkipped:
+		// Post hook failures and links left out of a restore don't fail
+		// the operation, so just report them
+		ShowWarning(m.window, event.Err.Error())
+	}
+}
+
+// IsPlaying reports whether the watcher has seen a game running
+func (m *MainUI) IsPlaying(gameID string) bool {
+	return m.watcher != nil && m.watcher.IsRunning(gameID)
+}
+
+// onWatchEvent updates the views when a game starts or stops
+func (m *MainUI) onWatchEvent(event models.WatchEvent) {
+	if event.Type == models.WatchError {
+		return
+	}
+
+	var names []string
+	if m.watcher != nil {
+		for _, game := range m.watcher.Running() {
+			names = append(names, game.GameName)
+		}
+	}
+	if len(names) > 0 {
+		m.playingLabel.SetText(IconPlaying + " Playing: " + strings.Join(names, ", "))
+	} else {
+		m.playingLabel.SetText("")
+	}
+	m.gamesView.Refresh()
+
+	if event.Type != models.WatchStopped {
+		return
+	}
+
+	message := fmt.Sprintf("Played for %s", event.Session.Duration().Round(time.Second))
+	if event.Session.PostCheckpointID != "" {
+		message += ", saves checkpointed"
+	}
+	fyne.CurrentApp().SendNotification(fyne.NewNotification(event.GameName, message))
+}
+
 // GetService returns the core service
 func (m *MainUI) GetService() *core.Service {
 	return m.service
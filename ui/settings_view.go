@@ -0,0 +1,531 @@
+package ui
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/adrielfilipedesign/gamekeep/internal/models"
+)
+
+// Labels for the add schedule dialog's kinds
+const (
+	scheduleEveryLabel = "Every N minutes while the saves change"
+	scheduleDailyLabel = "Daily at a time"
+	scheduleCronLabel  = "Cron expression"
+)
+
+// SettingsView handles the settings and schedules display
+type SettingsView struct {
+	mainUI    *MainUI
+	list      *widget.List
+	schedules []models.Schedule
+	gameNames map[string]string
+
+	trashDaysEntry     *widget.Entry
+	cacheVerification  *widget.Check
+	workersEntry       *widget.Entry
+	maxSizeEntry       *widget.Entry
+	maxEntriesEntry    *widget.Entry
+	maxRatioEntry      *widget.Entry
+	maxDepthEntry      *widget.Entry
+	watchCheck         *widget.Check
+	watchIntervalEntry *widget.Entry
+	exitCheckpoint     *widget.Check
+}
+
+// NewSettingsView creates a new settings view
+func NewSettingsView(mainUI *MainUI) *SettingsView {
+	return &SettingsView{
+		mainUI:    mainUI,
+		schedules: []models.Schedule{},
+		gameNames: map[string]string{},
+	}
+}
+
+// Build creates the settings view UI
+func (v *SettingsView) Build() fyne.CanvasObject {
+	v.trashDaysEntry = widget.NewEntry()
+	v.workersEntry = widget.NewEntry()
+	v.maxSizeEntry = widget.NewEntry()
+	v.maxEntriesEntry = widget.NewEntry()
+	v.maxRatioEntry = widget.NewEntry()
+	v.maxDepthEntry = widget.NewEntry()
+	v.cacheVerification = widget.NewCheck("Skip re-reading unchanged checkpoints to verify them before restoring", nil)
+	v.watchCheck = widget.NewCheck("Watch for running games while GameKeep is open", nil)
+	v.watchIntervalEntry = widget.NewEntry()
+	v.exitCheckpoint = widget.NewCheck("Checkpoint a game's saves when it exits, if they changed", nil)
+
+	saveBtn := widget.NewButton(IconSuccess+" Save Settings", func() {
+		v.saveSettings()
+	})
+	saveBtn.Importance = widget.HighImportance
+
+	settingsForm := container.NewVBox(
+		widget.NewLabelWithStyle("General", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		widget.NewForm(
+			widget.NewFormItem("Keep deleted checkpoints (days, 0 = until emptied)", v.trashDaysEntry),
+			widget.NewFormItem("Files to compress at once (0 = one per CPU)", v.workersEntry),
+		),
+		v.cacheVerification,
+		widget.NewLabelWithStyle("Restore Limits", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		widget.NewLabel("Restored checkpoints and imported bundles are refused if they go over these (0 = no limit)"),
+		widget.NewForm(
+			widget.NewFormItem("Largest unpacked size (MB)", v.maxSizeEntry),
+			widget.NewFormItem("Most files", v.maxEntriesEntry),
+			widget.NewFormItem("Most a file may compress (N:1)", v.maxRatioEntry),
+			widget.NewFormItem("Deepest folder nesting", v.maxDepthEntry),
+		),
+		widget.NewLabelWithStyle("Game Watcher", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		v.watchCheck,
+		widget.NewForm(
+			widget.NewFormItem("Look for games every (seconds)", v.watchIntervalEntry),
+		),
+		v.exitCheckpoint,
+		container.NewHBox(saveBtn),
+		widget.NewSeparator(),
+		widget.NewLabelWithStyle(IconSchedule+" Schedules", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+	)
+
+	v.list = widget.NewList(
+		func() int {
+			return len(v.schedules)
+		},
+		func() fyne.CanvasObject {
+			return v.createScheduleCard()
+		},
+		func(id widget.ListItemID, obj fyne.CanvasObject) {
+			if id >= len(v.schedules) {
+				return
+			}
+			v.updateScheduleCard(obj, v.schedules[id])
+		},
+	)
+
+	addBtn := widget.NewButton(IconAdd+" Add Schedule", func() {
+		v.showAddScheduleDialog()
+	})
+	hooksBtn := widget.NewButton("Hooks...", func() {
+		v.showHooksDialog()
+	})
+
+	return container.NewBorder(
+		settingsForm,
+		container.NewHBox(addBtn, layout.NewSpacer(), hooksBtn),
+		nil,
+		nil,
+		v.list,
+	)
+}
+
+// createScheduleCard creates a card template for a schedule
+func (v *SettingsView) createScheduleCard() fyne.CanvasObject {
+	nameLabel := widget.NewLabelWithStyle("", fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
+	detailsLabel := widget.NewLabel("")
+
+	pauseBtn := widget.NewButton("Pause", func() {})
+	removeBtn := widget.NewButton(IconDelete, func() {})
+	removeBtn.Importance = widget.DangerImportance
+
+	card := container.NewBorder(
+		nil,
+		nil,
+		nil,
+		container.NewHBox(pauseBtn, removeBtn),
+		container.NewVBox(nameLabel, detailsLabel),
+	)
+
+	return container.NewPadded(card)
+}
+
+// updateScheduleCard updates a card with schedule data
+func (v *SettingsView) updateScheduleCard(obj fyne.CanvasObject, schedule models.Schedule) {
+	card := obj.(*fyne.Container).Objects[0].(*fyne.Container)
+	info := card.Objects[0].(*fyne.Container)
+	actions := card.Objects[1].(*fyne.Container)
+
+	gameName := v.gameNames[schedule.GameID]
+	if gameName == "" {
+		gameName = schedule.GameID
+	}
+	info.Objects[0].(*widget.Label).SetText(fmt.Sprintf("%s • %s", gameName, schedule.Describe()))
+
+	lastRun := "never"
+	if schedule.LastRunAt != nil {
+		lastRun = schedule.LastRunAt.Local().Format("2006-01-02 15:04")
+	}
+	status := "Next: " + formatScheduleTime(schedule.NextRun())
+	if schedule.Paused {
+		status = "Paused"
+	}
+	details := fmt.Sprintf("Last run: %s • %s", lastRun, status)
+	if schedule.LastError != "" {
+		details += " • " + IconWarning + " " + schedule.LastError
+	}
+	info.Objects[1].(*widget.Label).SetText(details)
+
+	pauseBtn := actions.Objects[0].(*widget.Button)
+	removeBtn := actions.Objects[1].(*widget.Button)
+
+	pauseBtn.SetText("Pause")
+	if schedule.Paused {
+		pauseBtn.SetText("Resume")
+	}
+	pauseBtn.OnTapped = func() {
+		if _, err := v.mainUI.GetService().SetSchedulePaused(schedule.ID, !schedule.Paused); err != nil {
+			ShowError(v.mainUI.GetWindow(), "Failed to update schedule", err)
+			return
+		}
+		v.Refresh()
+	}
+
+	removeBtn.OnTapped = func() {
+		dialog.ShowConfirm(
+			"Remove Schedule",
+			fmt.Sprintf("Stop taking checkpoints of %s %s?\n\nCheckpoints it already took are kept.", gameName, schedule.Describe()),
+			func(confirmed bool) {
+				if !confirmed {
+					return
+				}
+				if err := v.mainUI.GetService().RemoveSchedule(schedule.ID); err != nil {
+					ShowError(v.mainUI.GetWindow(), "Failed to remove schedule", err)
+					return
+				}
+				v.Refresh()
+			},
+			v.mainUI.GetWindow(),
+		)
+	}
+}
+
+// formatScheduleTime formats a schedule's next run; zero means never
+func formatScheduleTime(t time.Time) string {
+	if t.IsZero() {
+		return "never"
+	}
+	if t.Before(time.Now()) {
+		return "due now"
+	}
+	return t.Local().Format("2006-01-02 15:04")
+}
+
+// Refresh reloads the settings and schedules
+func (v *SettingsView) Refresh() {
+	service := v.mainUI.GetService()
+
+	settings, err := service.GetSettings()
+	if err != nil {
+		ShowError(v.mainUI.GetWindow(), "Failed to load settings", err)
+		return
+	}
+	if v.trashDaysEntry != nil {
+		v.trashDaysEntry.SetText(strconv.Itoa(settings.TrashRetentionDays))
+		v.cacheVerification.SetChecked(settings.CacheVerification)
+		v.workersEntry.SetText(strconv.Itoa(settings.CompressionWorkers))
+		v.maxSizeEntry.SetText(strconv.FormatInt(settings.ArchiveLimits.MaxSizeMB, 10))
+		v.maxEntriesEntry.SetText(strconv.Itoa(settings.ArchiveLimits.MaxEntries))
+		v.maxRatioEntry.SetText(strconv.Itoa(settings.ArchiveLimits.MaxRatio))
+		v.maxDepthEntry.SetText(strconv.Itoa(settings.ArchiveLimits.MaxDepth))
+		v.watchCheck.SetChecked(settings.WatchGames)
+		v.watchIntervalEntry.SetText(strconv.Itoa(settings.WatchIntervalSeconds))
+		v.exitCheckpoint.SetChecked(settings.CheckpointOnExit)
+	}
+
+	schedules, err := service.ListSchedules("")
+	if err != nil {
+		ShowError(v.mainUI.GetWindow(), "Failed to load schedules", err)
+		return
+	}
+	v.schedules = schedules
+
+	games, err := service.ListGames()
+	if err == nil {
+		for _, game := range games {
+			v.gameNames[game.ID] = game.Name
+		}
+	}
+
+	if v.list != nil {
+		v.list.Refresh()
+	}
+}
+
+// saveSettings validates and saves the settings form
+func (v *SettingsView) saveSettings() {
+	service := v.mainUI.GetService()
+
+	settings, err := service.GetSettings()
+	if err != nil {
+		ShowError(v.mainUI.GetWindow(), "Failed to load settings", err)
+		return
+	}
+
+	trashDays, err := strconv.Atoi(v.trashDaysEntry.Text)
+	if err != nil {
+		ShowError(v.mainUI.GetWindow(), "Invalid settings", fmt.Errorf("trash days must be a number"))
+		return
+	}
+	workers, err := strconv.Atoi(v.workersEntry.Text)
+	if err != nil {
+		ShowError(v.mainUI.GetWindow(), "Invalid settings", fmt.Errorf("compression workers must be a number"))
+		return
+	}
+	interval, err := strconv.Atoi(v.watchIntervalEntry.Text)
+	if err != nil {
+		ShowError(v.mainUI.GetWindow(), "Invalid settings", fmt.Errorf("watch interval must be a number of seconds"))
+		return
+	}
+	maxSize, err := strconv.ParseInt(v.maxSizeEntry.Text, 10, 64)
+	if err != nil {
+		ShowError(v.mainUI.GetWindow(), "Invalid settings", fmt.Errorf("largest unpacked size must be a number of MB"))
+		return
+	}
+	maxEntries, err := strconv.Atoi(v.maxEntriesEntry.Text)
+	if err != nil {
+		ShowError(v.mainUI.GetWindow(), "Invalid settings", fmt.Errorf("most files must be a number"))
+		return
+	}
+	maxRatio, err := strconv.Atoi(v.maxRatioEntry.Text)
+	if err != nil {
+		ShowError(v.mainUI.GetWindow(), "Invalid settings", fmt.Errorf("compression ratio must be a number"))
+		return
+	}
+	maxDepth, err := strconv.Atoi(v.maxDepthEntry.Text)
+	if err != nil {
+		ShowError(v.mainUI.GetWindow(), "Invalid settings", fmt.Errorf("folder nesting must be a number"))
+		return
+	}
+
+	settings.TrashRetentionDays = trashDays
+	settings.CacheVerification = v.cacheVerification.Checked
+	settings.CompressionWorkers = workers
+	settings.ArchiveLimits = models.ArchiveLimits{
+		MaxSizeMB:  maxSize,
+		MaxEntries: maxEntries,
+		MaxRatio:   maxRatio,
+		MaxDepth:   maxDepth,
+	}
+	settings.WatchGames = v.watchCheck.Checked
+	settings.WatchIntervalSeconds = interval
+	settings.CheckpointOnExit = v.exitCheckpoint.Checked
+
+	if err := service.UpdateSettings(settings); err != nil {
+		ShowError(v.mainUI.GetWindow(), "Failed to save settings", err)
+		return
+	}
+
+	// Apply the watcher setting straight away
+	if settings.WatchGames {
+		v.mainUI.StartWatcher()
+	} else {
+		v.mainUI.StopWatcher()
+	}
+
+	ShowSuccess(v.mainUI.GetWindow(), "Settings saved")
+}
+
+// showAddScheduleDialog asks for a game and timing and adds a schedule
+func (v *SettingsView) showAddScheduleDialog() {
+	games, err := v.mainUI.GetService().ListGames()
+	if err != nil {
+		ShowError(v.mainUI.GetWindow(), "Failed to load games", err)
+		return
+	}
+	if len(games) == 0 {
+		ShowInfo(v.mainUI.GetWindow(), "Add a game first")
+		return
+	}
+
+	gameIDs := make(map[string]string, len(games))
+	names := make([]string, len(games))
+	for i, game := range games {
+		names[i] = game.Name
+		gameIDs[game.Name] = game.ID
+	}
+	gameSelect := widget.NewSelect(names, nil)
+	if current := v.mainUI.currentGame; current != nil {
+		gameSelect.SetSelected(current.Name)
+	} else {
+		gameSelect.SetSelected(names[0])
+	}
+
+	valueEntry := widget.NewEntry()
+	hintLabel := widget.NewLabel("")
+	kindRadio := widget.NewRadioGroup([]string{scheduleEveryLabel, scheduleDailyLabel, scheduleCronLabel}, func(kind string) {
+		switch kind {
+		case scheduleEveryLabel:
+			valueEntry.SetPlaceHolder("30")
+			hintLabel.SetText("Minutes between checkpoints")
+		case scheduleDailyLabel:
+			valueEntry.SetPlaceHolder("21:30")
+			hintLabel.SetText("Local time, 24-hour")
+		case scheduleCronLabel:
+			valueEntry.SetPlaceHolder("0 */2 * * *")
+			hintLabel.SetText("minute hour day month weekday, in local time")
+		}
+	})
+	kindRadio.SetSelected(scheduleEveryLabel)
+
+	form := container.NewVBox(
+		widget.NewLabel("Game:"),
+		gameSelect,
+		widget.NewLabel("When:"),
+		kindRadio,
+		valueEntry,
+		hintLabel,
+		widget.NewLabel("Runs are skipped while the saves haven't changed."),
+	)
+
+	d := dialog.NewCustomConfirm(
+		"Add Schedule",
+		"Add",
+		"Cancel",
+		form,
+		func(confirmed bool) {
+			if !confirmed {
+				return
+			}
+
+			var spec models.Schedule
+			switch kindRadio.Selected {
+			case scheduleEveryLabel:
+				minutes, err := strconv.Atoi(valueEntry.Text)
+				if err != nil {
+					ShowError(v.mainUI.GetWindow(), "Invalid schedule", fmt.Errorf("minutes must be a number"))
+					return
+				}
+				spec = models.Schedule{Kind: models.ScheduleInterval, IntervalMinutes: minutes}
+			case scheduleDailyLabel:
+				spec = models.Schedule{Kind: models.ScheduleDaily, DailyAt: valueEntry.Text}
+			default:
+				spec = models.Schedule{Kind: models.ScheduleCron, Cron: valueEntry.Text}
+			}
+
+			if _, err := v.mainUI.GetService().AddSchedule(gameIDs[gameSelect.Selected], spec); err != nil {
+				ShowError(v.mainUI.GetWindow(), "Failed to add schedule", err)
+				return
+			}
+			v.Refresh()
+		},
+		v.mainUI.GetWindow(),
+	)
+
+	d.Resize(DialogSize)
+	d.Show()
+}
+
+// allGamesLabel picks the global hooks in the hooks dialog
+const allGamesLabel = "All games"
+
+// showHooksDialog lists, adds and removes the global and per-game hooks
+func (v *SettingsView) showHooksDialog() {
+	service := v.mainUI.GetService()
+
+	games, err := service.ListGames()
+	if err != nil {
+		ShowError(v.mainUI.GetWindow(), "Failed to load games", err)
+		return
+	}
+	gameIDs := map[string]string{allGamesLabel: ""}
+	names := []string{allGamesLabel}
+	for _, game := range games {
+		names = append(names, game.Name)
+		gameIDs[game.Name] = game.ID
+	}
+
+	var hooks []models.Hook
+	scopeSelect := widget.NewSelect(names, nil)
+	hookList := widget.NewList(
+		func() int {
+			return len(hooks)
+		},
+		func() fyne.CanvasObject {
+			removeBtn := widget.NewButton(IconDelete, func() {})
+			removeBtn.Importance = widget.DangerImportance
+			return container.NewBorder(nil, nil, nil, removeBtn, widget.NewLabel(""))
+		},
+		func(id widget.ListItemID, obj fyne.CanvasObject) {
+			if id >= len(hooks) {
+				return
+			}
+			row := obj.(*fyne.Container)
+			hook := hooks[id]
+			row.Objects[0].(*widget.Label).SetText(fmt.Sprintf("%s (%s): %s", hook.Event, hook.Timeout(), hook.Command))
+			row.Objects[1].(*widget.Button).OnTapped = func() {
+				if err := service.RemoveHook(gameIDs[scopeSelect.Selected], id); err != nil {
+					ShowError(v.mainUI.GetWindow(), "Failed to remove hook", err)
+					return
+				}
+				scopeSelect.OnChanged(scopeSelect.Selected)
+			}
+		},
+	)
+	scopeSelect.OnChanged = func(name string) {
+		list, err := service.ListHooks(gameIDs[name])
+		if err != nil {
+			ShowError(v.mainUI.GetWindow(), "Failed to load hooks", err)
+			return
+		}
+		hooks = list
+		hookList.Refresh()
+	}
+	scopeSelect.SetSelected(allGamesLabel)
+
+	events := make([]string, len(models.AllHookEvents))
+	for i, event := range models.AllHookEvents {
+		events[i] = string(event)
+	}
+	eventSelect := widget.NewSelect(events, nil)
+	eventSelect.SetSelected(events[0])
+	commandEntry := widget.NewEntry()
+	commandEntry.SetPlaceHolder("Shell command, e.g. syncctl pause")
+	timeoutEntry := widget.NewEntry()
+	timeoutEntry.SetText(strconv.Itoa(models.DefaultHookTimeoutSeconds))
+
+	addBtn := widget.NewButton(IconAdd+" Add Hook", func() {
+		timeout, err := strconv.Atoi(timeoutEntry.Text)
+		if err != nil {
+			ShowError(v.mainUI.GetWindow(), "Invalid hook", fmt.Errorf("timeout must be a number of seconds"))
+			return
+		}
+		hook := models.Hook{
+			Event:          models.HookEvent(eventSelect.Selected),
+			Command:        commandEntry.Text,
+			TimeoutSeconds: timeout,
+		}
+		if err := service.AddHook(gameIDs[scopeSelect.Selected], hook); err != nil {
+			ShowError(v.mainUI.GetWindow(), "Failed to add hook", err)
+			return
+		}
+		commandEntry.SetText("")
+		scopeSelect.OnChanged(scopeSelect.Selected)
+	})
+
+	form := container.NewVBox(
+		widget.NewForm(
+			widget.NewFormItem("Event", eventSelect),
+			widget.NewFormItem("Command", commandEntry),
+			widget.NewFormItem("Timeout (seconds)", timeoutEntry),
+		),
+		container.NewHBox(addBtn),
+		widget.NewLabel("A pre hook that fails or times out stops the operation.\nHooks get GAMEKEEP_* variables and JSON details on stdin."),
+	)
+
+	content := container.NewBorder(
+		container.NewVBox(widget.NewLabel("Run hooks for:"), scopeSelect),
+		form,
+		nil,
+		nil,
+		hookList,
+	)
+
+	d := dialog.NewCustom("Hooks", "Close", content, v.mainUI.GetWindow())
+	d.Resize(DialogSize)
+	d.Show()
+}
@@ -9,18 +9,18 @@ import (
 
 // Window sizes
 var (
-	MainWindowSize      = fyne.NewSize(1000, 700)
-	DialogSize          = fyne.NewSize(500, 400)
-	SmallDialogSize     = fyne.NewSize(400, 200)
+	MainWindowSize       = fyne.NewSize(1000, 700)
+	DialogSize           = fyne.NewSize(500, 400)
+	SmallDialogSize      = fyne.NewSize(400, 200)
 	CheckpointDialogSize = fyne.NewSize(600, 450)
 )
 
 // Custom theme colors
 var (
-	PrimaryColor   = color.NRGBA{R: 66, G: 135, B: 245, A: 255}  // Blue
-	SuccessColor   = color.NRGBA{R: 52, G: 199, B: 89, A: 255}   // Green
-	WarningColor   = color.NRGBA{R: 255, G: 159, B: 10, A: 255}  // Orange
-	DangerColor    = color.NRGBA{R: 255, G: 69, B: 58, A: 255}   // Red
+	PrimaryColor    = color.NRGBA{R: 66, G: 135, B: 245, A: 255} // Blue
+	SuccessColor    = color.NRGBA{R: 52, G: 199, B: 89, A: 255}  // Green
+	WarningColor    = color.NRGBA{R: 255, G: 159, B: 10, A: 255} // Orange
+	DangerColor     = color.NRGBA{R: 255, G: 69, B: 58, A: 255}  // Red
 	BackgroundColor = color.NRGBA{R: 28, G: 28, B: 30, A: 255}   // Dark gray
 )
 
@@ -61,4 +61,12 @@ const (
 	IconSuccess    = "✅"
 	IconWarning    = "⚠️"
 	IconInfo       = "ℹ️"
+	IconHistory    = "📜"
+	IconEdit       = "✏️"
+	IconTag        = "🏷️"
+	IconPin        = "📌"
+	IconTimeline   = "🌳"
+	IconPlaying    = "▶️"
+	IconSettings   = "⚙️"
+	IconSchedule   = "⏰"
 )
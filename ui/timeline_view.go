@@ -0,0 +1,151 @@
+package ui
+
+import (
+	"fmt"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/adrielfilipedesign/gamekeep/internal/models"
+)
+
+// TimelineView shows how the current game's checkpoints branch
+type TimelineView struct {
+	mainUI     *MainUI
+	tree       *widget.Tree
+	titleLabel *widget.Label
+	nodes      map[string]*models.CheckpointNode
+	roots      []string
+	head       string
+	selected   string
+}
+
+// NewTimelineView creates a new timeline view
+func NewTimelineView(mainUI *MainUI) *TimelineView {
+	return &TimelineView{
+		mainUI: mainUI,
+		nodes:  map[string]*models.CheckpointNode{},
+	}
+}
+
+// Build creates the timeline view UI
+func (v *TimelineView) Build() fyne.CanvasObject {
+	v.titleLabel = widget.NewLabel("← Select a game to view its timeline")
+
+	v.tree = widget.NewTree(
+		func(id widget.TreeNodeID) []widget.TreeNodeID {
+			if id == "" {
+				return v.roots
+			}
+			return v.childIDs(id)
+		},
+		func(id widget.TreeNodeID) bool {
+			return id == "" || len(v.childIDs(id)) > 0
+		},
+		func(branch bool) fyne.CanvasObject {
+			return widget.NewLabel("Checkpoint")
+		},
+		func(id widget.TreeNodeID, branch bool, obj fyne.CanvasObject) {
+			node, ok := v.nodes[id]
+			if !ok {
+				return
+			}
+			obj.(*widget.Label).SetText(v.nodeLabel(node.Checkpoint))
+		},
+	)
+
+	v.tree.OnSelected = func(id widget.TreeNodeID) {
+		v.selected = id
+	}
+
+	restoreBtn := widget.NewButton(IconRestore+" Restore Selected", func() {
+		node, ok := v.nodes[v.selected]
+		if !ok {
+			ShowInfo(v.mainUI.GetWindow(), "Please select a checkpoint first")
+			return
+		}
+		v.mainUI.checkpointsView.confirmRestore(node.Checkpoint)
+	})
+	restoreBtn.Importance = widget.HighImportance
+
+	legend := widget.NewLabel("▶ marks the current save (last created or restored)")
+	legend.TextStyle.Italic = true
+
+	return container.NewBorder(
+		v.titleLabel,
+		container.NewBorder(nil, nil, nil, restoreBtn, legend),
+		nil,
+		nil,
+		v.tree,
+	)
+}
+
+// Refresh reloads the tree for the selected game
+func (v *TimelineView) Refresh() {
+	game := v.mainUI.currentGame
+
+	v.nodes = map[string]*models.CheckpointNode{}
+	v.roots = nil
+	v.head = ""
+	v.selected = ""
+
+	if game == nil {
+		v.titleLabel.SetText("← Select a game to view its timeline")
+		v.tree.Refresh()
+		return
+	}
+
+	roots, head, err := v.mainUI.GetService().CheckpointTree(game.ID)
+	if err != nil {
+		ShowError(v.mainUI.GetWindow(), "Failed to load timeline", err)
+		return
+	}
+
+	v.head = head
+	for _, root := range roots {
+		v.roots = append(v.roots, root.Checkpoint.ID)
+		v.index(root)
+	}
+
+	v.titleLabel.SetText(fmt.Sprintf("%s %s timeline", IconGame, game.Name))
+	v.tree.UnselectAll()
+	v.tree.Refresh()
+	v.tree.OpenAllBranches()
+}
+
+// index adds a node and its descendants to the lookup map
+func (v *TimelineView) index(node *models.CheckpointNode) {
+	v.nodes[node.Checkpoint.ID] = node
+	for _, child := range node.Children {
+		v.index(child)
+	}
+}
+
+// childIDs returns the IDs of a checkpoint's children
+func (v *TimelineView) childIDs(id string) []string {
+	node, ok := v.nodes[id]
+	if !ok {
+		return nil
+	}
+
+	ids := make([]string, 0, len(node.Children))
+	for _, child := range node.Children {
+		ids = append(ids, child.Checkpoint.ID)
+	}
+	return ids
+}
+
+// nodeLabel formats a checkpoint for the tree
+func (v *TimelineView) nodeLabel(cp models.Checkpoint) string {
+	icon := IconCheckpoint
+	if cp.Protected {
+		icon = IconPin
+	}
+
+	label := fmt.Sprintf("%s %s  (%s)", icon, cp.Name, cp.CreatedAt.Local().Format("2006-01-02 15:04"))
+	if cp.ID == v.head {
+		label = "▶ " + label
+	}
+	return label
+}
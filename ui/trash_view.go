@@ -0,0 +1,218 @@
+package ui
+
+import (
+	"fmt"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/adrielfilipedesign/gamekeep/internal/models"
+)
+
+// TrashView handles the recycle bin display
+type TrashView struct {
+	mainUI    *MainUI
+	list      *widget.List
+	entries   []models.TrashEntry
+	container *fyne.Container
+}
+
+// NewTrashView creates a new trash view
+func NewTrashView(mainUI *MainUI) *TrashView {
+	return &TrashView{
+		mainUI:  mainUI,
+		entries: []models.TrashEntry{},
+	}
+}
+
+// Build creates the trash view UI
+func (v *TrashView) Build() fyne.CanvasObject {
+	// Create list
+	v.list = widget.NewList(
+		func() int {
+			return len(v.entries)
+		},
+		func() fyne.CanvasObject {
+			return v.createTrashCard()
+		},
+		func(id widget.ListItemID, obj fyne.CanvasObject) {
+			if id >= len(v.entries) {
+				return
+			}
+			v.updateTrashCard(obj, v.entries[id])
+		},
+	)
+
+	// Empty trash button
+	emptyBtn := widget.NewButton(IconDelete+" Empty Trash", func() {
+		v.confirmEmpty()
+	})
+	emptyBtn.Importance = widget.DangerImportance
+
+	v.container = container.NewBorder(
+		nil,
+		container.NewHBox(emptyBtn),
+		nil,
+		nil,
+		v.list,
+	)
+
+	return v.container
+}
+
+// createTrashCard creates a card template for a trash entry
+func (v *TrashView) createTrashCard() fyne.CanvasObject {
+	nameLabel := widget.NewLabelWithStyle("", fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
+	detailsLabel := widget.NewLabel("")
+
+	info := container.NewVBox(
+		nameLabel,
+		detailsLabel,
+	)
+
+	restoreBtn := widget.NewButton(IconRestore+" Restore", func() {})
+	restoreBtn.Importance = widget.HighImportance
+
+	deleteBtn := widget.NewButton(IconDelete, func() {})
+	deleteBtn.Importance = widget.DangerImportance
+
+	actions := container.NewHBox(
+		restoreBtn,
+		deleteBtn,
+	)
+
+	card := container.NewBorder(
+		nil,
+		nil,
+		nil,
+		actions,
+		info,
+	)
+
+	return container.NewPadded(card)
+}
+
+// updateTrashCard updates a card with trash entry data
+func (v *TrashView) updateTrashCard(obj fyne.CanvasObject, entry models.TrashEntry) {
+	card := obj.(*fyne.Container).Objects[0].(*fyne.Container)
+	info := card.Objects[0].(*fyne.Container)
+	actions := card.Objects[1].(*fyne.Container)
+
+	nameLabel := info.Objects[0].(*widget.Label)
+	detailsLabel := info.Objects[1].(*widget.Label)
+
+	cp := entry.Checkpoint
+	nameLabel.SetText(IconCheckpoint + " " + cp.Name)
+
+	expires := "never expires"
+	if !entry.ExpiresAt.IsZero() {
+		expires = "expires " + entry.ExpiresAt.Local().Format("2006-01-02 15:04")
+	}
+	detailsLabel.SetText(fmt.Sprintf("Game: %s • Deleted: %s • %s",
+		cp.GameID,
+		entry.DeletedAt.Local().Format("2006-01-02 15:04"),
+		expires,
+	))
+
+	restoreBtn := actions.Objects[0].(*widget.Button)
+	deleteBtn := actions.Objects[1].(*widget.Button)
+
+	restoreBtn.OnTapped = func() {
+		v.restore(entry)
+	}
+
+	deleteBtn.OnTapped = func() {
+		v.confirmDeleteForever(entry)
+	}
+}
+
+// Refresh reloads and updates the list
+func (v *TrashView) Refresh() {
+	entries, err := v.mainUI.GetService().ListTrash()
+	if err != nil {
+		ShowError(v.mainUI.GetWindow(), "Failed to load trash", err)
+		return
+	}
+	v.entries = entries
+
+	if v.list != nil {
+		v.list.Refresh()
+	}
+}
+
+// restore moves a trashed checkpoint back to its game
+func (v *TrashView) restore(entry models.TrashEntry) {
+	cp, err := v.mainUI.GetService().RestoreFromTrash(entry.Checkpoint.ID)
+	if err != nil {
+		ShowError(v.mainUI.GetWindow(), "Failed to restore checkpoint", err)
+		return
+	}
+
+	ShowSuccess(v.mainUI.GetWindow(), fmt.Sprintf("Checkpoint '%s' restored from trash", cp.Name))
+	v.mainUI.RefreshAll()
+}
+
+// confirmDeleteForever shows confirmation dialog for permanent deletion
+func (v *TrashView) confirmDeleteForever(entry models.TrashEntry) {
+	message := fmt.Sprintf(
+		"Permanently delete checkpoint '%s'?\n\nThis action cannot be undone.",
+		entry.Checkpoint.Name,
+	)
+
+	d := dialog.NewConfirm(
+		"Confirm Delete",
+		message,
+		func(confirmed bool) {
+			if !confirmed {
+				return
+			}
+
+			if err := v.mainUI.GetService().DeleteFromTrash(entry.Checkpoint.ID); err != nil {
+				ShowError(v.mainUI.GetWindow(), "Failed to delete checkpoint", err)
+				return
+			}
+
+			v.Refresh()
+		},
+		v.mainUI.GetWindow(),
+	)
+
+	d.Show()
+}
+
+// confirmEmpty shows confirmation dialog for emptying the trash
+func (v *TrashView) confirmEmpty() {
+	if len(v.entries) == 0 {
+		ShowInfo(v.mainUI.GetWindow(), "Trash is already empty")
+		return
+	}
+
+	message := fmt.Sprintf(
+		"Permanently delete all %d checkpoint(s) in the trash?\n\nThis action cannot be undone.",
+		len(v.entries),
+	)
+
+	d := dialog.NewConfirm(
+		"Empty Trash",
+		message,
+		func(confirmed bool) {
+			if !confirmed {
+				return
+			}
+
+			count, err := v.mainUI.GetService().EmptyTrash()
+			if err != nil {
+				ShowError(v.mainUI.GetWindow(), "Failed to empty trash", err)
+				return
+			}
+
+			ShowSuccess(v.mainUI.GetWindow(), fmt.Sprintf("Permanently deleted %d checkpoint(s)", count))
+			v.Refresh()
+		},
+		v.mainUI.GetWindow(),
+	)
+
+	d.Show()
+}